@@ -0,0 +1,112 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	zapf "sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/storage"
+)
+
+const (
+	storageSecrets    = "secrets"
+	storageConfigMaps = "configmaps"
+)
+
+type gcOptions struct {
+	namespace   string
+	storageKind string
+	owners      []string
+	gracePeriod time.Duration
+	dryRun      bool
+}
+
+func NewCmd() *cobra.Command {
+	o := &gcOptions{}
+	zapfs := flag.NewFlagSet("zap", flag.ExitOnError)
+	zapOpts := &zapf.Options{}
+	zapOpts.BindFlags(zapfs)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete orphaned chunks from chunked release storage",
+		Long: `Delete chunks of chunked release storage that are not referenced by any
+index of their owner and that are older than the grace period.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.run(cmd, zapf.New(zapf.UseFlagOptions(zapOpts)))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "", "Namespace of the release storage")
+	cmd.Flags().StringVar(&o.storageKind, "storage", storageSecrets, fmt.Sprintf("Kind of objects the release storage is backed by, one of %q or %q", storageSecrets, storageConfigMaps))
+	cmd.Flags().StringSliceVar(&o.owners, "owner", nil, "Only collect chunks of these owners (default all owners)")
+	cmd.Flags().DurationVar(&o.gracePeriod, "grace-period", storage.DefaultGarbageCollectionGracePeriod, "Minimum age of orphaned chunks to delete")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only report orphaned chunks without deleting them")
+	cmd.Flags().AddGoFlagSet(zapfs)
+	return cmd
+}
+
+func (o *gcOptions) run(cmd *cobra.Command, log logr.Logger) error {
+	if o.namespace == "" {
+		return errors.New("--namespace must be set")
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("get config: %w", err)
+	}
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	var store storage.ObjectStore
+	switch o.storageKind {
+	case storageSecrets:
+		store = storage.NewSecretsStore(cs.CoreV1().Secrets(o.namespace))
+	case storageConfigMaps:
+		store = storage.NewConfigMapsStore(cs.CoreV1().ConfigMaps(o.namespace))
+	default:
+		return fmt.Errorf("unknown storage %q", o.storageKind)
+	}
+
+	gc := storage.NewGarbageCollector(store, storage.GarbageCollectorConfig{
+		Owners:      o.owners,
+		GracePeriod: o.gracePeriod,
+		DryRun:      o.dryRun,
+		Log:         log,
+	})
+	result, err := gc.Collect(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	verb := "deleted"
+	if o.dryRun {
+		verb = "found"
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s %d orphaned chunks (%d bytes)\n", verb, result.Chunks, result.Bytes)
+	return err
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/crd"
+	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate manifests for a Helm operator",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newCRDCmd())
+	return cmd
+}
+
+type crdOptions struct {
+	watchesFile string
+	outputDir   string
+	scope       string
+}
+
+func newCRDCmd() *cobra.Command {
+	o := &crdOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "crd",
+		Short: "Generate CustomResourceDefinitions for the watched kinds",
+		Long: `Generate a CustomResourceDefinition for each kind in the watches file.
+
+The schema of the spec is converted from the chart's values.schema.json. If the
+chart has no schema, it is inferred from the chart's default values. The
+CustomResourceDefinitions enable the status subresource and have printer
+columns for the conditions that the operator sets.
+
+By default, the CustomResourceDefinitions are written to stdout. If
+--output-dir is set, each one is written to a file named <group>_<plural>.yaml
+in that directory.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.run(cmd)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.watchesFile, "watches-file", "./watches.yaml", "Path to the watches file")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "Directory to write the CustomResourceDefinitions to, instead of stdout")
+	cmd.Flags().StringVar(&o.scope, "scope", string(apiextensionsv1.NamespaceScoped), "Scope of the custom resources, either Namespaced or Cluster")
+	return cmd
+}
+
+func (o *crdOptions) run(cmd *cobra.Command) error {
+	scope := apiextensionsv1.ResourceScope(o.scope)
+	if scope != apiextensionsv1.NamespaceScoped && scope != apiextensionsv1.ClusterScoped {
+		return fmt.Errorf("invalid scope %q: must be %s or %s", o.scope, apiextensionsv1.NamespaceScoped, apiextensionsv1.ClusterScoped)
+	}
+
+	ws, err := watches.Load(o.watchesFile)
+	if err != nil {
+		return fmt.Errorf("could not load watches file: %w", err)
+	}
+	cmd.SilenceUsage = true
+
+	if o.outputDir != "" {
+		if err := os.MkdirAll(o.outputDir, 0o755); err != nil {
+			return fmt.Errorf("could not create output directory: %w", err)
+		}
+	}
+	for i, w := range ws {
+		c, err := crd.Generate(w, crd.WithScope(scope))
+		if err != nil {
+			return fmt.Errorf("could not generate CustomResourceDefinition for %s: %w", w.GroupVersionKind, err)
+		}
+		data, err := marshal(c)
+		if err != nil {
+			return fmt.Errorf("could not marshal CustomResourceDefinition %s: %w", c.Name, err)
+		}
+
+		if o.outputDir == "" {
+			if err := writeDocument(cmd.OutOrStdout(), data, i == 0); err != nil {
+				return err
+			}
+			continue
+		}
+		path := filepath.Join(o.outputDir, fmt.Sprintf("%s_%s.yaml", c.Spec.Group, c.Spec.Names.Plural))
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("could not write CustomResourceDefinition: %w", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s\n", path)
+	}
+	return nil
+}
+
+// marshal marshals c to YAML without the fields that are only set by the
+// API server.
+func marshal(c *apiextensionsv1.CustomResourceDefinition) ([]byte, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
+	if err != nil {
+		return nil, err
+	}
+	delete(obj, "status")
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	return yaml.Marshal(obj)
+}
+
+func writeDocument(w io.Writer, data []byte, first bool) error {
+	if !first {
+		if _, err := io.WriteString(w, "---\n"); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(data)
+	return err
+}
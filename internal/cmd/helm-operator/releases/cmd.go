@@ -0,0 +1,339 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package releases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
+)
+
+const (
+	storageDriverSecret        = "secret"
+	storageDriverChunkedSecret = "chunked-secret"
+)
+
+type releasesOptions struct {
+	watchesFile   string
+	namespace     string
+	allNamespaces bool
+	storageDriver string
+	storageOwner  string
+	output        string
+	revision      int
+	allValues     bool
+}
+
+func NewCmd() *cobra.Command {
+	o := &releasesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "releases",
+		Short: "Inspect the releases managed by the operator",
+		Long: `Inspect the releases managed by the operator. Releases are read with the
+same storage driver as the operator uses, and are identified by the custom
+resource they belong to, e.g. "nginx/my-nginx" or "nginx.example.com/my-nginx".`,
+	}
+	cmd.PersistentFlags().StringVar(&o.watchesFile, "watches-file", "./watches.yaml", "Path to the watches file to use")
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Namespace of the custom resources")
+	cmd.PersistentFlags().StringVar(&o.storageDriver, "storage-driver", storageDriverSecret, fmt.Sprintf("Storage driver used by the operator, one of %q or %q", storageDriverSecret, storageDriverChunkedSecret))
+	cmd.PersistentFlags().StringVar(&o.storageOwner, "storage-owner", "helm", fmt.Sprintf("Owner label of releases stored by the %q storage driver", storageDriverChunkedSecret))
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", outputTable, fmt.Sprintf("Output format, one of %q, %q or %q", outputTable, outputYAML, outputJSON))
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the latest releases of all custom resources",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.runList(cmd)
+		},
+	}
+	listCmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "List releases of custom resources in all namespaces")
+
+	getCmd := &cobra.Command{
+		Use:   "get <kind>/<name>",
+		Short: "Show a release of a custom resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runGet(cmd, args[0])
+		},
+	}
+
+	historyCmd := &cobra.Command{
+		Use:   "history <kind>/<name>",
+		Short: "Show all revisions of the release of a custom resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runHistory(cmd, args[0])
+		},
+	}
+
+	manifestCmd := &cobra.Command{
+		Use:   "manifest <kind>/<name>",
+		Short: "Show the manifest of a release of a custom resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runManifest(cmd, args[0])
+		},
+	}
+
+	valuesCmd := &cobra.Command{
+		Use:   "values <kind>/<name>",
+		Short: "Show the values of a release of a custom resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runValues(cmd, args[0])
+		},
+	}
+	valuesCmd.Flags().BoolVarP(&o.allValues, "all", "a", false, "Show the values coalesced with the chart's defaults")
+
+	for _, c := range []*cobra.Command{getCmd, manifestCmd, valuesCmd} {
+		c.Flags().IntVar(&o.revision, "revision", 0, "Revision of the release (default latest)")
+	}
+
+	cmd.AddCommand(listCmd, getCmd, historyCmd, manifestCmd, valuesCmd)
+	return cmd
+}
+
+func (o *releasesOptions) runList(cmd *cobra.Command) error {
+	ws, err := watches.Load(o.watchesFile)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("get config: %w", err)
+	}
+	cl, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+	acg, err := o.actionClientGetter(cfg, cl.RESTMapper())
+	if err != nil {
+		return err
+	}
+
+	var listOpts []client.ListOption
+	if !o.allNamespaces {
+		listOpts = append(listOpts, client.InNamespace(o.namespace))
+	}
+
+	var infos []releaseInfo
+	for _, w := range ws {
+		objs := &unstructured.UnstructuredList{}
+		objs.SetGroupVersionKind(w.GroupVersionKind.GroupVersion().WithKind(w.Kind + "List"))
+		if err := cl.List(cmd.Context(), objs, listOpts...); err != nil {
+			return fmt.Errorf("list %s: %w", w.GroupVersionKind, err)
+		}
+		for i := range objs.Items {
+			obj := &objs.Items[i]
+			rel, err := getRelease(cmd.Context(), acg, obj, 0)
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			infos = append(infos, newReleaseInfo(obj, rel))
+		}
+	}
+	return writeReleaseInfos(cmd.OutOrStdout(), o.output, infos)
+}
+
+func (o *releasesOptions) runGet(cmd *cobra.Command, ref string) error {
+	obj, acg, err := o.setup(ref)
+	if err != nil {
+		return err
+	}
+	rel, err := getRelease(cmd.Context(), acg, obj, o.revision)
+	if err != nil {
+		return err
+	}
+	return writeReleaseInfo(cmd.OutOrStdout(), o.output, newReleaseInfo(obj, rel))
+}
+
+func (o *releasesOptions) runHistory(cmd *cobra.Command, ref string) error {
+	obj, acg, err := o.setup(ref)
+	if err != nil {
+		return err
+	}
+	ac, err := acg.ActionClientFor(cmd.Context(), obj)
+	if err != nil {
+		return fmt.Errorf("get action client: %w", err)
+	}
+	rels, err := ac.History(obj.GetName())
+	if err != nil {
+		return fmt.Errorf("get history of %s: %w", ref, err)
+	}
+	infos := make([]releaseInfo, 0, len(rels))
+	for i := len(rels) - 1; i >= 0; i-- {
+		infos = append(infos, newReleaseInfo(obj, rels[i]))
+	}
+	return writeReleaseInfos(cmd.OutOrStdout(), o.output, infos)
+}
+
+func (o *releasesOptions) runManifest(cmd *cobra.Command, ref string) error {
+	obj, acg, err := o.setup(ref)
+	if err != nil {
+		return err
+	}
+	rel, err := getRelease(cmd.Context(), acg, obj, o.revision)
+	if err != nil {
+		return err
+	}
+	return writeManifest(cmd.OutOrStdout(), o.output, rel.Manifest)
+}
+
+func (o *releasesOptions) runValues(cmd *cobra.Command, ref string) error {
+	obj, acg, err := o.setup(ref)
+	if err != nil {
+		return err
+	}
+	rel, err := getRelease(cmd.Context(), acg, obj, o.revision)
+	if err != nil {
+		return err
+	}
+	vals := rel.Config
+	if o.allValues {
+		vals, err = chartutil.CoalesceValues(rel.Chart, rel.Config)
+		if err != nil {
+			return fmt.Errorf("coalesce values: %w", err)
+		}
+	}
+	return writeValues(cmd.OutOrStdout(), o.output, vals)
+}
+
+// setup returns a placeholder for the custom resource that ref refers to,
+// and an action client getter for it. The custom resource itself is not
+// read, so that releases of deleted custom resources can be inspected.
+func (o *releasesOptions) setup(ref string) (*unstructured.Unstructured, helmclient.ActionClientGetter, error) {
+	kind, name, ok := strings.Cut(ref, "/")
+	if !ok || kind == "" || name == "" {
+		return nil, nil, fmt.Errorf("invalid custom resource %q, expected <kind>/<name>", ref)
+	}
+	ws, err := watches.Load(o.watchesFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	gvk, err := resolveKind(ws, kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get config: %w", err)
+	}
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create http client: %w", err)
+	}
+	rm, err := apiutil.NewDynamicRESTMapper(cfg, httpClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create rest mapper: %w", err)
+	}
+	acg, err := o.actionClientGetter(cfg, rm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(o.namespace)
+	obj.SetName(name)
+	return obj, acg, nil
+}
+
+func (o *releasesOptions) actionClientGetter(cfg *rest.Config, rm meta.RESTMapper) (helmclient.ActionClientGetter, error) {
+	var storageDriver helmclient.ObjectToStorageDriverMapper
+	switch o.storageDriver {
+	case storageDriverSecret:
+		storageDriver = helmclient.DefaultSecretsStorageDriver(helmclient.SecretsStorageDriverOpts{})
+	case storageDriverChunkedSecret:
+		storageDriver = helmclient.ChunkedSecretsStorageDriver(helmclient.ChunkedSecretsStorageDriverOpts{Owner: o.storageOwner})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", o.storageDriver)
+	}
+	switch o.output {
+	case outputTable, outputYAML, outputJSON:
+	default:
+		return nil, fmt.Errorf("unknown output format %q", o.output)
+	}
+
+	acg, err := helmclient.NewActionConfigGetter(cfg, rm, helmclient.StorageDriverMapper(storageDriver))
+	if err != nil {
+		return nil, fmt.Errorf("create action config getter: %w", err)
+	}
+	return helmclient.NewActionClientGetter(acg)
+}
+
+// resolveKind returns the GroupVersionKind of the watch that kind refers to.
+// kind is matched case-insensitively against the kind, "kind.group" and
+// "kind.version.group" of each watch.
+func resolveKind(ws []watches.Watch, kind string) (schema.GroupVersionKind, error) {
+	var matches []schema.GroupVersionKind
+	for _, w := range ws {
+		candidates := []string{
+			w.Kind,
+			w.Kind + "." + w.Group,
+			w.Kind + "." + w.Version + "." + w.Group,
+		}
+		for _, c := range candidates {
+			if strings.EqualFold(c, kind) {
+				matches = append(matches, w.GroupVersionKind)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return schema.GroupVersionKind{}, fmt.Errorf("no watch for kind %q", kind)
+	case 1:
+		return matches[0], nil
+	}
+	return schema.GroupVersionKind{}, fmt.Errorf("kind %q is ambiguous, use <kind>.<version>.<group> to select one of %v", kind, matches)
+}
+
+func getRelease(ctx context.Context, acg helmclient.ActionClientGetter, obj *unstructured.Unstructured, revision int) (*release.Release, error) {
+	ac, err := acg.ActionClientFor(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("get action client: %w", err)
+	}
+	rel, err := ac.Get(obj.GetName(), func(g *action.Get) error {
+		g.Version = revision
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get release of %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return rel, nil
+}
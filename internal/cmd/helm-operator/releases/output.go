@@ -0,0 +1,140 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputTable = "table"
+	outputYAML  = "yaml"
+	outputJSON  = "json"
+)
+
+// releaseInfo is the summary of a release that is printed by the list, get
+// and history commands.
+type releaseInfo struct {
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	APIVersion  string    `json:"apiVersion"`
+	Revision    int       `json:"revision"`
+	Status      string    `json:"status"`
+	Chart       string    `json:"chart"`
+	AppVersion  string    `json:"appVersion,omitempty"`
+	Updated     time.Time `json:"updated"`
+	Description string    `json:"description,omitempty"`
+}
+
+func newReleaseInfo(obj *unstructured.Unstructured, rel *release.Release) releaseInfo {
+	info := releaseInfo{
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Kind:       obj.GetKind(),
+		APIVersion: obj.GetAPIVersion(),
+		Revision:   rel.Version,
+	}
+	if rel.Info != nil {
+		info.Status = rel.Info.Status.String()
+		info.Updated = rel.Info.LastDeployed.Time
+		info.Description = rel.Info.Description
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		info.Chart = rel.Chart.Metadata.Name + "-" + rel.Chart.Metadata.Version
+		info.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	return info
+}
+
+func writeReleaseInfo(w io.Writer, format string, info releaseInfo) error {
+	if format == outputTable {
+		return writeReleaseInfos(w, format, []releaseInfo{info})
+	}
+	return writeStructured(w, format, info)
+}
+
+func writeReleaseInfos(w io.Writer, format string, infos []releaseInfo) error {
+	if format != outputTable {
+		if infos == nil {
+			infos = []releaseInfo{}
+		}
+		return writeStructured(w, format, infos)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tKIND\tREVISION\tSTATUS\tCHART\tAPP VERSION\tUPDATED\tDESCRIPTION")
+	for _, info := range infos {
+		updated := ""
+		if !info.Updated.IsZero() {
+			updated = info.Updated.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			info.Namespace, info.Name, info.Kind, info.Revision, info.Status,
+			info.Chart, info.AppVersion, updated, info.Description)
+	}
+	return tw.Flush()
+}
+
+// writeManifest writes manifest as is in table format, or as a string
+// field in JSON and YAML format.
+func writeManifest(w io.Writer, format, manifest string) error {
+	if format == outputTable {
+		_, err := io.WriteString(w, strings.TrimSpace(manifest)+"\n")
+		return err
+	}
+	return writeStructured(w, format, map[string]string{"manifest": manifest})
+}
+
+// writeValues writes values as YAML, unless JSON output is requested.
+func writeValues(w io.Writer, format string, values map[string]interface{}) error {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	if format == outputTable {
+		format = outputYAML
+	}
+	return writeStructured(w, format, values)
+}
+
+func writeStructured(w io.Writer, format string, v interface{}) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch format {
+	case outputJSON:
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	case outputYAML:
+		data, err = yaml.Marshal(v)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("marshal output: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/run"
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler"
+	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
+)
+
+type renderOptions struct {
+	watchesFile string
+	namespace   string
+	kubeVersion string
+	apiVersions []string
+	noHooks     bool
+}
+
+func NewCmd() *cobra.Command {
+	o := &renderOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "render <custom-resource-file>",
+		Short: "Render the manifest that the operator would install for a custom resource",
+		Long: `Render the manifest that the operator would install for a custom resource,
+without a cluster. The reconciler is configured from the matching entry of the
+watches file like by the run command, so the same values are computed and the
+manifest is post-rendered the same way, e.g. with the dependent watches label.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&o.watchesFile, "watches-file", "./watches.yaml", "Path to the watches file to use")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "default", "Namespace of the custom resource, if it does not set one")
+	cmd.Flags().StringVar(&o.kubeVersion, "kube-version", "", "Kubernetes version used for Capabilities.KubeVersion")
+	cmd.Flags().StringSliceVarP(&o.apiVersions, "api-versions", "a", nil, "Kubernetes api versions used for Capabilities.APIVersions")
+	cmd.Flags().BoolVar(&o.noHooks, "no-hooks", false, "Do not render hooks")
+	return cmd
+}
+
+func (o *renderOptions) run(cmd *cobra.Command, crFile string) error {
+	obj, err := readObject(crFile)
+	if err != nil {
+		return err
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(o.namespace)
+	}
+
+	ws, err := watches.Load(o.watchesFile)
+	if err != nil {
+		return err
+	}
+	var w *watches.Watch
+	for i := range ws {
+		if ws[i].GroupVersionKind == obj.GroupVersionKind() {
+			w = &ws[i]
+			break
+		}
+	}
+	if w == nil {
+		return fmt.Errorf("no watch for %s in %q", obj.GroupVersionKind(), o.watchesFile)
+	}
+
+	reconcilerOpts, err := run.ReconcilerOptions(*w, helmclient.NewRemoteClusters(nil))
+	if err != nil {
+		return err
+	}
+	r, err := reconciler.New(reconcilerOpts...)
+	if err != nil {
+		return fmt.Errorf("create reconciler: %w", err)
+	}
+
+	opts, err := o.installOptions()
+	if err != nil {
+		return err
+	}
+	rel, err := r.Render(cmd.Context(), obj, opts...)
+	if err != nil {
+		return err
+	}
+	return writeRelease(cmd.OutOrStdout(), rel, o.noHooks)
+}
+
+func (o *renderOptions) installOptions() ([]helmclient.InstallOption, error) {
+	var kubeVersion *chartutil.KubeVersion
+	if o.kubeVersion != "" {
+		var err error
+		kubeVersion, err = chartutil.ParseKubeVersion(o.kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --kube-version: %w", err)
+		}
+	}
+	return []helmclient.InstallOption{func(i *action.Install) error {
+		i.KubeVersion = kubeVersion
+		i.APIVersions = o.apiVersions
+		i.DisableHooks = o.noHooks
+		return nil
+	}}, nil
+}
+
+func readObject(path string) (*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read custom resource: %w", err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+		return nil, fmt.Errorf("parse custom resource %q: %w", path, err)
+	}
+	if obj.GetName() == "" || obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+		return nil, fmt.Errorf("custom resource %q must set apiVersion, kind and metadata.name", path)
+	}
+	return obj, nil
+}
+
+// writeRelease writes the manifest of rel followed by its hooks in the same
+// format as "helm template".
+func writeRelease(out io.Writer, rel *release.Release, noHooks bool) error {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(rel.Manifest))
+	b.WriteString("\n")
+	if !noHooks {
+		for _, h := range rel.Hooks {
+			fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", h.Path, strings.TrimSpace(h.Manifest))
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/render"
+)
+
+var _ = Describe("render", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		chartPath, err := filepath.Abs("../../../../pkg/internal/testdata/test-chart-1.2.0.tgz")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(dir, "cr.yaml"), []byte(`
+apiVersion: example.com/v1
+kind: TestApp
+metadata:
+  name: test
+spec:
+  replicaCount: 2
+`), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "watches.yaml"), []byte(`
+- group: example.com
+  version: v1
+  kind: TestApp
+  chart: `+chartPath+`
+  dependentWatches:
+    label: app.example.com/managed-by=test-operator
+`), 0600)).To(Succeed())
+	})
+
+	renderObjects := func() ([]unstructured.Unstructured, error) {
+		GinkgoHelper()
+		cmd := render.NewCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(GinkgoWriter)
+		cmd.SetArgs([]string{filepath.Join(dir, "cr.yaml"), "--watches-file", filepath.Join(dir, "watches.yaml"), "--no-hooks"})
+		if err := cmd.Execute(); err != nil {
+			return nil, err
+		}
+		var objs []unstructured.Unstructured
+		for _, doc := range strings.Split(out.String(), "\n---\n") {
+			obj := unstructured.Unstructured{}
+			Expect(yaml.Unmarshal([]byte(doc), &obj.Object)).To(Succeed())
+			if len(obj.Object) > 0 {
+				objs = append(objs, obj)
+			}
+		}
+		return objs, nil
+	}
+
+	It("labels all objects with the label of the dependent watches", func() {
+		objs, err := renderObjects()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objs).ToNot(BeEmpty())
+		for _, obj := range objs {
+			Expect(obj.GetLabels()).To(HaveKeyWithValue("app.example.com/managed-by", "test-operator"), "%s %s", obj.GetKind(), obj.GetName())
+		}
+	})
+
+})
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -30,14 +31,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/operator-framework/helm-operator-plugins/internal/flags"
 	"github.com/operator-framework/helm-operator-plugins/internal/metrics"
 	"github.com/operator-framework/helm-operator-plugins/internal/version"
-	"github.com/operator-framework/helm-operator-plugins/pkg/annotation"
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
 	helmmgr "github.com/operator-framework/helm-operator-plugins/pkg/manager"
 	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler"
+	"github.com/operator-framework/helm-operator-plugins/pkg/sharding"
+	"github.com/operator-framework/helm-operator-plugins/pkg/storage"
 	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
 )
 
@@ -75,6 +77,7 @@ func NewCmd() *cobra.Command {
 func run(cmd *cobra.Command, f *flags.Flags) {
 	printVersion()
 	metrics.RegisterBuildInfo(crmetrics.Registry)
+	storage.RegisterGarbageCollectorMetrics(crmetrics.Registry)
 
 	// Load config options from the config at f.ManagerConfigPath.
 	// These options will not override those set by flags.
@@ -82,7 +85,6 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		options manager.Options
 		err     error
 	)
-	exitIfUnsupported(options)
 
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -119,6 +121,11 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 	// Set default manager options
 	options = f.ToManagerOptions(options)
 
+	if f.Sharding && options.LeaderElection {
+		log.Error(errors.New("--sharding cannot be combined with leader election"), "invalid flags usage")
+		os.Exit(1)
+	}
+
 	// Log manager option flags
 	// Log manager option flags
 	optionsLog := map[string]interface{}{
@@ -158,19 +165,53 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		os.Exit(1)
 	}
 
+	var sharder *sharding.Sharder
+	if f.Sharding {
+		if sharder, err = newSharder(mgr, f); err != nil {
+			log.Error(err, "Failed to configure sharding")
+			os.Exit(1)
+		}
+		log.Info("sharding custom resources", "group", f.ShardGroup, "identity", sharder.Identity())
+	}
+
+	if f.ChunkGCInterval > 0 {
+		gc, err := newGarbageCollector(mgr, f)
+		if err != nil {
+			log.Error(err, "Failed to configure chunk garbage collection")
+			os.Exit(1)
+		}
+		if err := mgr.Add(gc); err != nil {
+			log.Error(err, "Failed to add chunk garbage collector")
+			os.Exit(1)
+		}
+		log.Info("garbage collecting orphaned chunks", "namespace", f.ChunkGCNamespace, "storage", f.ChunkGCStorage, "interval", f.ChunkGCInterval)
+	}
+
+	storageDriver, err := newStorageDriver(f)
+	if err != nil {
+		log.Error(err, "Failed to configure release storage")
+		os.Exit(1)
+	}
+
+	remoteClusters := helmclient.NewRemoteClusters(mgr.GetClient())
 	for _, w := range ws {
-		r, err := reconciler.New(
-			reconciler.WithChart(*w.Chart),
-			reconciler.WithGroupVersionKind(w.GroupVersionKind),
-			reconciler.WithOverrideValues(w.OverrideValues),
-			reconciler.WithSelector(*w.Selector),
-			reconciler.SkipDependentWatches(*w.WatchDependentResources),
+		opts, err := ReconcilerOptions(w, remoteClusters)
+		if err != nil {
+			log.Error(err, "invalid watch", "gvk", w.GroupVersionKind)
+			os.Exit(1)
+		}
+		opts = append(opts,
 			reconciler.WithMaxConcurrentReconciles(f.MaxConcurrentReconciles),
 			reconciler.WithReconcilePeriod(f.ReconcilePeriod),
-			reconciler.WithInstallAnnotations(annotation.DefaultInstallAnnotations...),
-			reconciler.WithUpgradeAnnotations(annotation.DefaultUpgradeAnnotations...),
-			reconciler.WithUninstallAnnotations(annotation.DefaultUninstallAnnotations...),
+			reconciler.WithUpdateStrategy(reconciler.UpdateStrategy(f.UpdateStrategy)),
 		)
+		if sharder != nil {
+			opts = append(opts, reconciler.WithSharder(sharder))
+		}
+		if storageDriver != nil {
+			opts = append(opts, reconciler.WithStorageDriver(storageDriver))
+		}
+		r, err := reconciler.New(opts...)
 		if err != nil {
 			log.Error(err, "unable to create helm reconciler", "controller", "Helm")
 			os.Exit(1)
@@ -180,7 +221,7 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 			log.Error(err, "unable to create controller", "Helm")
 			os.Exit(1)
 		}
-		log.Info("configured watch", "gvk", w.GroupVersionKind, "chartDir", w.ChartPath, "maxConcurrentReconciles", f.MaxConcurrentReconciles, "reconcilePeriod", f.ReconcilePeriod)
+		log.Info("configured watch", "gvk", w.GroupVersionKind, "chartDir", w.ChartPath, "maxConcurrentReconciles", f.MaxConcurrentReconciles, "reconcilePeriod", f.ReconcilePeriod, "validatingWebhook", w.ValidatingWebhook, "defaultingWebhook", w.DefaultingWebhook != nil, "remoteClusters", w.RemoteClusters, "impersonation", w.Impersonation != nil, "maintenanceWindows", w.MaintenanceWindows, "statusMappings", len(w.StatusMappings), "dependentWatches", w.DependentWatches, "dryRunFullCheckPeriod", w.DryRunFullCheckPeriod, "sharding", sharder != nil, "storageDriver", f.StorageDriver)
 	}
 
 	log.Info("starting manager")
@@ -191,25 +232,96 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 	}
 }
 
-// exitIfUnsupported prints an error containing unsupported field names and exits
-// if any of those fields are not their default values.
-func exitIfUnsupported(options manager.Options) {
-	var keys []string
+// serviceAccountNamespaceFile contains the namespace of the pod of the
+// operator when it runs in a cluster.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 
-	if options.WebhookServer != nil {
-		// The below options are webhook-specific, which is not supported by helm.
-		// Adding logs only for the previously supported values through manager.
-		if options.WebhookServer.(*webhook.DefaultServer).Options.CertDir != "" {
-			keys = append(keys, "certDir")
+// newSharder returns a sharder whose identity is the name of the pod of the
+// operator, as set in the POD_NAME environment variable, or its hostname.
+func newSharder(mgr manager.Manager, f *flags.Flags) (*sharding.Sharder, error) {
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("get hostname: %w", err)
 		}
-		if options.WebhookServer.(*webhook.DefaultServer).Options.Host != "" {
-			keys = append(keys, "host")
+		identity = hostname
+	}
+	namespace := f.ShardNamespace
+	if namespace == "" {
+		var err error
+		if namespace, err = podNamespace(); err != nil {
+			return nil, fmt.Errorf("--shard-namespace must be set when not running in a cluster: %w", err)
 		}
-		if options.WebhookServer.(*webhook.DefaultServer).Options.Port != 0 {
-			keys = append(keys, "port")
+	}
+	return sharding.New(mgr.GetClient(), mgr.GetAPIReader(), identity, namespace,
+		sharding.WithGroup(f.ShardGroup),
+		sharding.WithLeaseDuration(f.ShardLeaseDuration),
+		sharding.WithLogger(logf.Log.WithName("sharding")),
+	)
+}
+
+// newStorageDriver returns the storage driver of --storage-driver, or nil for
+// the default storage driver.
+func newStorageDriver(f *flags.Flags) (helmclient.ObjectToStorageDriverMapper, error) {
+	switch f.StorageDriver {
+	case "secret":
+		return nil, nil
+	case "chunked-secret":
+		codec, err := storage.CodecForName(f.StorageCodec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --storage-codec: %w", err)
 		}
-		log.Error(fmt.Errorf(`options for setting webhook server configuration is not supported. 
-		%s set in manager options`, strings.Join(keys, ", ")), "unsupported fields")
-		os.Exit(1)
+		return helmclient.ChunkedSecretsStorageDriver(helmclient.ChunkedSecretsStorageDriverOpts{
+			Owner: f.StorageOwner,
+			Config: storage.ChunkedConfig{
+				ChunkSize:         f.StorageChunkSize,
+				Codec:             codec,
+				AdaptiveChunkSize: f.StorageAdaptiveChunks,
+				MaxObjectSize:     f.StorageMaxObjectSize,
+			},
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown --storage-driver %q", f.StorageDriver)
+	}
+}
+
+// newGarbageCollector returns a garbage collector for the chunked release
+// storage in the namespace of --chunk-gc-namespace, or of the operator pod.
+func newGarbageCollector(mgr manager.Manager, f *flags.Flags) (*storage.GarbageCollector, error) {
+	if f.ChunkGCNamespace == "" {
+		namespace, err := podNamespace()
+		if err != nil {
+			return nil, fmt.Errorf("--chunk-gc-namespace must be set when not running in a cluster: %w", err)
+		}
+		f.ChunkGCNamespace = namespace
+	}
+	cs, err := kubernetes.NewForConfigAndClient(mgr.GetConfig(), mgr.GetHTTPClient())
+	if err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+	var store storage.ObjectStore
+	switch f.ChunkGCStorage {
+	case "secrets":
+		store = storage.NewSecretsStore(cs.CoreV1().Secrets(f.ChunkGCNamespace))
+	case "configmaps":
+		store = storage.NewConfigMapsStore(cs.CoreV1().ConfigMaps(f.ChunkGCNamespace))
+	default:
+		return nil, fmt.Errorf("unknown --chunk-gc-storage %q", f.ChunkGCStorage)
+	}
+	return storage.NewGarbageCollector(store, storage.GarbageCollectorConfig{
+		Owners:      f.ChunkGCOwners,
+		GracePeriod: f.ChunkGCGracePeriod,
+		Interval:    f.ChunkGCInterval,
+		Log:         logf.Log.WithName("chunk-gc"),
+	}), nil
+}
+
+// podNamespace returns the namespace of the operator pod.
+func podNamespace() (string, error) {
+	data, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return "", err
 	}
+	return strings.TrimSpace(string(data)), nil
 }
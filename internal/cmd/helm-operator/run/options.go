@@ -0,0 +1,84 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/annotation"
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/maintenance"
+	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler"
+	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
+)
+
+// ReconcilerOptions returns the options of the reconciler of w that are
+// configured by the watches file, so that the render command renders
+// releases exactly like the reconcilers of the run command. remoteClusters
+// is used if w installs releases in remote clusters.
+func ReconcilerOptions(w watches.Watch, remoteClusters *helmclient.RemoteClusters) ([]reconciler.Option, error) {
+	opts := []reconciler.Option{
+		reconciler.WithChart(*w.Chart),
+		reconciler.WithGroupVersionKind(w.GroupVersionKind),
+		reconciler.WithOverrideValues(w.OverrideValues),
+		reconciler.WithSelector(*w.Selector),
+		reconciler.SkipDependentWatches(*w.WatchDependentResources),
+		reconciler.WithInstallAnnotations(annotation.DefaultInstallAnnotations...),
+		reconciler.WithUpgradeAnnotations(annotation.DefaultUpgradeAnnotations...),
+		reconciler.WithUninstallAnnotations(annotation.DefaultUninstallAnnotations...),
+		reconciler.WithValidatingWebhook(w.ValidatingWebhook),
+	}
+	if w.DefaultingWebhook != nil {
+		opts = append(opts, reconciler.WithDefaultingWebhook(w.DefaultingWebhook.AllowedPaths...))
+	}
+	if w.Impersonation != nil {
+		opts = append(opts, reconciler.WithImpersonation(helmclient.ImpersonationOpts{
+			DefaultServiceAccount:  w.Impersonation.DefaultServiceAccount,
+			AllowedServiceAccounts: w.Impersonation.AllowedServiceAccounts,
+		}))
+	}
+	if len(w.MaintenanceWindows) > 0 {
+		var windows []maintenance.Window
+		for _, s := range w.MaintenanceWindows {
+			mw, err := maintenance.Parse(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid maintenance window: %w", err)
+			}
+			windows = append(windows, mw)
+		}
+		opts = append(opts, reconciler.WithUpgradeScheduleHandler(reconciler.UpgradeInMaintenanceWindows(reconciler.MaintenanceWindowsAnnotation, windows...)))
+	}
+	if len(w.StatusMappings) > 0 {
+		opts = append(opts, reconciler.WithStatusMappings(w.StatusMappings...))
+	}
+	if w.DependentWatches != nil {
+		opts = append(opts, reconciler.WithMetadataOnlyDependentWatches(w.DependentWatches.MetadataOnly))
+		key, value, err := w.DependentWatches.ParseLabel()
+		if err != nil {
+			return nil, fmt.Errorf("invalid dependent watches label: %w", err)
+		}
+		if key != "" {
+			opts = append(opts, reconciler.WithDependentResourceLabel(key, value))
+		}
+		opts = append(opts, reconciler.WithDependentEventFilter(w.DependentWatches.Config))
+	}
+	if w.DryRunFullCheckPeriod != nil {
+		opts = append(opts, reconciler.SkipUnchangedDryRuns(w.DryRunFullCheckPeriod.Duration))
+	}
+	if w.RemoteClusters {
+		opts = append(opts, reconciler.WithRemoteClusters(remoteClusters))
+	}
+	return opts, nil
+}
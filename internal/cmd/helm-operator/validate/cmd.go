@@ -0,0 +1,84 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
+)
+
+type validateOptions struct {
+	watchesFile string
+	crdDir      string
+	printSchema bool
+}
+
+func NewCmd() *cobra.Command {
+	o := &validateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a watches file",
+		Long: `Validate a watches file and report all problems found in it with their line
+numbers. Besides the structure of the watches file, it checks that the charts
+load, that the override values render and, if --crd-dir is set, that each
+watched kind is defined by a CustomResourceDefinition in that directory.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.run(cmd)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.watchesFile, "watches-file", "./watches.yaml", "Path to the watches file to validate")
+	cmd.Flags().StringVar(&o.crdDir, "crd-dir", "", "Directory containing the CustomResourceDefinitions of the watched kinds")
+	cmd.Flags().BoolVar(&o.printSchema, "print-schema", false, "Print the JSON Schema of the watches file and exit")
+	return cmd
+}
+
+func (o *validateOptions) run(cmd *cobra.Command) error {
+	if o.printSchema {
+		_, err := cmd.OutOrStdout().Write(watches.JSONSchema)
+		return err
+	}
+
+	f, err := os.Open(o.watchesFile)
+	if err != nil {
+		return fmt.Errorf("could not open watches file: %w", err)
+	}
+	defer f.Close()
+
+	problems, err := watches.Validate(f, watches.ValidateOptions{CRDDir: o.crdDir})
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", o.watchesFile)
+		return err
+	}
+	for _, p := range problems {
+		pos := o.watchesFile
+		if p.Line > 0 {
+			pos = fmt.Sprintf("%s:%d", o.watchesFile, p.Line)
+			p.Line = 0
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s\n", pos, p.Error())
+	}
+	cmd.SilenceUsage = true
+	return fmt.Errorf("found %d problems in %s", len(problems), o.watchesFile)
+}
@@ -3,7 +3,12 @@ package cmd
 import (
 	"context"
 
+	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/gc"
+	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/generate"
+	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/releases"
+	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/render"
 	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/run"
+	"github.com/operator-framework/helm-operator-plugins/internal/cmd/helm-operator/validate"
 	"github.com/operator-framework/helm-operator-plugins/internal/version"
 
 	"github.com/spf13/cobra"
@@ -19,6 +24,11 @@ func rootCmd() *cobra.Command {
 	}
 
 	rootCmd.AddCommand(run.NewCmd())
+	rootCmd.AddCommand(gc.NewCmd())
+	rootCmd.AddCommand(render.NewCmd())
+	rootCmd.AddCommand(releases.NewCmd())
+	rootCmd.AddCommand(validate.NewCmd())
+	rootCmd.AddCommand(generate.NewCmd())
 
 	return rootCmd
 }
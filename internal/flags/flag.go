@@ -20,9 +20,13 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/sharding"
+	"github.com/operator-framework/helm-operator-plugins/pkg/storage"
 )
 
 // Flags - Options to be used by a helm operator
@@ -37,6 +41,25 @@ type Flags struct {
 	ProbeAddr               string
 	EnableHTTP2             bool
 	SecureMetrics           bool
+	WebhookHost             string
+	WebhookPort             int
+	WebhookCertDir          string
+	Sharding                bool
+	ShardGroup              string
+	ShardNamespace          string
+	ShardLeaseDuration      time.Duration
+	UpdateStrategy          string
+	ChunkGCInterval         time.Duration
+	ChunkGCNamespace        string
+	ChunkGCStorage          string
+	ChunkGCOwners           []string
+	ChunkGCGracePeriod      time.Duration
+	StorageDriver           string
+	StorageOwner            string
+	StorageCodec            string
+	StorageChunkSize        int
+	StorageAdaptiveChunks   bool
+	StorageMaxObjectSize    int
 
 	// If not nil, used to deduce which flags were set in the CLI.
 	flagSet *pflag.FlagSet
@@ -119,6 +142,123 @@ func (f *Flags) AddTo(flagSet *pflag.FlagSet) {
 		false,
 		"enables secure serving of the metrics endpoint",
 	)
+	flagSet.StringVar(&f.WebhookHost,
+		"webhook-host",
+		"",
+		"The address the webhook server binds to. All addresses are used if empty.",
+	)
+	flagSet.IntVar(&f.WebhookPort,
+		"webhook-port",
+		webhook.DefaultPort,
+		"The port the webhook server serves at. The webhook server is only"+
+			" started if a watch enables a webhook.",
+	)
+	flagSet.StringVar(&f.WebhookCertDir,
+		"webhook-cert-dir",
+		"",
+		"The directory that contains the webhook server's tls.crt and tls.key"+
+			" files. Defaults to <temp-dir>/k8s-webhook-server/serving-certs.",
+	)
+	flagSet.BoolVar(&f.Sharding,
+		"sharding",
+		false,
+		"Enable sharding of custom resources across the replicas of the operator."+
+			" Each replica reconciles the custom resources assigned to it."+
+			" Cannot be combined with leader election.",
+	)
+	flagSet.StringVar(&f.ShardGroup,
+		"shard-group",
+		sharding.DefaultGroup,
+		"Name of the group of replicas that share the custom resources. It must"+
+			" be unique among the operators that shard in the same namespace.",
+	)
+	flagSet.StringVar(&f.ShardNamespace,
+		"shard-namespace",
+		"",
+		"Namespace in which the replicas create their shard leases. Defaults to"+
+			" the namespace of the operator pod.",
+	)
+	flagSet.DurationVar(&f.ShardLeaseDuration,
+		"shard-lease-duration",
+		sharding.DefaultLeaseDuration,
+		"Duration after which a replica that stopped renewing its shard lease"+
+			" is removed from the group and its custom resources are reassigned.",
+	)
+	flagSet.StringVar(&f.UpdateStrategy,
+		"update-strategy",
+		"Update",
+		"How the finalizer and the status of custom resources are written. One"+
+			" of \"Update\", which fails on concurrent changes of custom resources,"+
+			" or \"Patch\", which only patches the fields owned by the operator.",
+	)
+	flagSet.DurationVar(&f.ChunkGCInterval,
+		"chunk-gc-interval",
+		0,
+		"Interval at which orphaned chunks of chunked release storage are"+
+			" deleted. The garbage collector is disabled if it is 0.",
+	)
+	flagSet.StringVar(&f.ChunkGCNamespace,
+		"chunk-gc-namespace",
+		"",
+		"Namespace of the chunked release storage that is garbage collected."+
+			" Defaults to the namespace of the operator pod.",
+	)
+	flagSet.StringVar(&f.ChunkGCStorage,
+		"chunk-gc-storage",
+		"secrets",
+		"Kind of objects the garbage collected release storage is backed by,"+
+			" one of \"secrets\" or \"configmaps\".",
+	)
+	flagSet.StringSliceVar(&f.ChunkGCOwners,
+		"chunk-gc-owner",
+		nil,
+		"Only garbage collect chunks of these owners. Chunks of all owners are"+
+			" garbage collected if it is not set.",
+	)
+	flagSet.DurationVar(&f.ChunkGCGracePeriod,
+		"chunk-gc-grace-period",
+		storage.DefaultGarbageCollectionGracePeriod,
+		"Minimum age of orphaned chunks that are garbage collected.",
+	)
+	flagSet.StringVar(&f.StorageDriver,
+		"storage-driver",
+		"secret",
+		"Storage driver of releases, one of \"secret\", which stores each"+
+			" release in a Secret, or \"chunked-secret\", which splits releases"+
+			" across Secrets so that they may exceed the maximum size of a Secret.",
+	)
+	flagSet.StringVar(&f.StorageOwner,
+		"storage-owner",
+		"helm",
+		"Owner label of releases stored by the \"chunked-secret\" storage driver.",
+	)
+	flagSet.StringVar(&f.StorageCodec,
+		"storage-codec",
+		storage.CodecNameGzip,
+		"Codec with which the \"chunked-secret\" storage driver compresses"+
+			" releases, one of \"gzip\" or \"zstd\". Releases are read with the"+
+			" codec they were written with.",
+	)
+	flagSet.IntVar(&f.StorageChunkSize,
+		"storage-chunk-size",
+		storage.DefaultChunkSize,
+		"Size of the chunks of the \"chunked-secret\" storage driver in bytes.",
+	)
+	flagSet.BoolVar(&f.StorageAdaptiveChunks,
+		"storage-adaptive-chunk-size",
+		false,
+		"Make the \"chunked-secret\" storage driver ignore --storage-chunk-size"+
+			" and split releases into as few chunks as --storage-max-object-size"+
+			" allows.",
+	)
+	flagSet.IntVar(&f.StorageMaxObjectSize,
+		"storage-max-object-size",
+		corev1.MaxSecretSize,
+		"Maximum size of the data of a Secret in bytes, which is used by"+
+			" --storage-adaptive-chunk-size. It is not discovered from the"+
+			" cluster and must not exceed the --max-request-bytes of its API"+
+			" server and etcd.",
+	)
 }
 
 // ToManagerOptions uses the flag set in f to configure options.
@@ -157,12 +297,18 @@ func (f *Flags) ToManagerOptions(options manager.Options) manager.Options {
 	disableHTTP2 := func(c *tls.Config) {
 		c.NextProtos = []string{"http/1.1"}
 	}
+	webhookOptions := webhook.Options{
+		Host:    f.WebhookHost,
+		Port:    f.WebhookPort,
+		CertDir: f.WebhookCertDir,
+	}
 	if !f.EnableHTTP2 {
-		options.WebhookServer = webhook.NewServer(webhook.Options{
-			TLSOpts: []func(*tls.Config){disableHTTP2},
-		})
+		webhookOptions.TLSOpts = []func(*tls.Config){disableHTTP2}
 		options.Metrics.TLSOpts = append(options.Metrics.TLSOpts, disableHTTP2)
 	}
+	if changed("webhook-host") || changed("webhook-port") || changed("webhook-cert-dir") || options.WebhookServer == nil {
+		options.WebhookServer = webhook.NewServer(webhookOptions)
+	}
 	options.Metrics.SecureServing = f.SecureMetrics
 	return options
 }
@@ -15,12 +15,18 @@
 package flags_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/operator-framework/helm-operator-plugins/internal/flags"
+	"github.com/operator-framework/helm-operator-plugins/pkg/sharding"
+	"github.com/operator-framework/helm-operator-plugins/pkg/storage"
 )
 
 var _ = Describe("Flags", func() {
@@ -64,6 +70,139 @@ var _ = Describe("Flags", func() {
 				Expect(f.ToManagerOptions(options).Metrics.BindAddress).To(Equal(expOptionValue))
 			})
 		})
+
+		Describe("webhook server", func() {
+			BeforeEach(func() {
+				options.WebhookServer = nil
+			})
+
+			webhookOptions := func(options manager.Options) webhook.Options {
+				Expect(options.WebhookServer).To(BeAssignableToTypeOf(&webhook.DefaultServer{}))
+				return options.WebhookServer.(*webhook.DefaultServer).Options
+			}
+
+			It("uses the flag values", func() {
+				parseArgs(flagSet, "--webhook-host", "127.0.0.1", "--webhook-port", "9444", "--webhook-cert-dir", "/certs")
+				opts := webhookOptions(f.ToManagerOptions(options))
+				Expect(opts.Host).To(Equal("127.0.0.1"))
+				Expect(opts.Port).To(Equal(9444))
+				Expect(opts.CertDir).To(Equal("/certs"))
+				Expect(opts.TLSOpts).To(HaveLen(1))
+			})
+			It("uses the default flag values when no webhook server is set", func() {
+				parseArgs(flagSet)
+				opts := webhookOptions(f.ToManagerOptions(options))
+				Expect(opts.Port).To(Equal(webhook.DefaultPort))
+			})
+			It("keeps the configured webhook server when no flag is set", func() {
+				server := webhook.NewServer(webhook.Options{Port: 1234})
+				options.WebhookServer = server
+				parseArgs(flagSet)
+				Expect(f.ToManagerOptions(options).WebhookServer).To(BeIdenticalTo(server))
+			})
+		})
+	})
+
+	Describe("sharding", func() {
+		var f *flags.Flags
+		var flagSet *pflag.FlagSet
+		BeforeEach(func() {
+			f = &flags.Flags{}
+			flagSet = pflag.NewFlagSet("test", pflag.ExitOnError)
+			f.AddTo(flagSet)
+		})
+
+		It("is disabled by default", func() {
+			parseArgs(flagSet)
+			Expect(f.Sharding).To(BeFalse())
+			Expect(f.ShardGroup).To(Equal(sharding.DefaultGroup))
+			Expect(f.ShardNamespace).To(BeEmpty())
+			Expect(f.ShardLeaseDuration).To(Equal(sharding.DefaultLeaseDuration))
+		})
+		It("uses the flag values", func() {
+			parseArgs(flagSet, "--sharding", "--shard-group", "nginx", "--shard-namespace", "operators", "--shard-lease-duration", "30s")
+			Expect(f.Sharding).To(BeTrue())
+			Expect(f.ShardGroup).To(Equal("nginx"))
+			Expect(f.ShardNamespace).To(Equal("operators"))
+			Expect(f.ShardLeaseDuration).To(Equal(30 * time.Second))
+		})
+	})
+
+	Describe("update-strategy", func() {
+		var f *flags.Flags
+		var flagSet *pflag.FlagSet
+		BeforeEach(func() {
+			f = &flags.Flags{}
+			flagSet = pflag.NewFlagSet("test", pflag.ExitOnError)
+			f.AddTo(flagSet)
+		})
+
+		It("defaults to Update", func() {
+			parseArgs(flagSet)
+			Expect(f.UpdateStrategy).To(Equal("Update"))
+		})
+		It("uses the flag value", func() {
+			parseArgs(flagSet, "--update-strategy", "Patch")
+			Expect(f.UpdateStrategy).To(Equal("Patch"))
+		})
+	})
+
+	Describe("chunk-gc", func() {
+		var f *flags.Flags
+		var flagSet *pflag.FlagSet
+		BeforeEach(func() {
+			f = &flags.Flags{}
+			flagSet = pflag.NewFlagSet("test", pflag.ExitOnError)
+			f.AddTo(flagSet)
+		})
+
+		It("is disabled by default", func() {
+			parseArgs(flagSet)
+			Expect(f.ChunkGCInterval).To(BeZero())
+			Expect(f.ChunkGCNamespace).To(BeEmpty())
+			Expect(f.ChunkGCStorage).To(Equal("secrets"))
+			Expect(f.ChunkGCOwners).To(BeEmpty())
+			Expect(f.ChunkGCGracePeriod).To(Equal(storage.DefaultGarbageCollectionGracePeriod))
+		})
+		It("uses the flag values", func() {
+			parseArgs(flagSet, "--chunk-gc-interval", "10m", "--chunk-gc-namespace", "operators", "--chunk-gc-storage", "configmaps",
+				"--chunk-gc-owner", "nginx", "--chunk-gc-owner", "redis", "--chunk-gc-grace-period", "2h")
+			Expect(f.ChunkGCInterval).To(Equal(10 * time.Minute))
+			Expect(f.ChunkGCNamespace).To(Equal("operators"))
+			Expect(f.ChunkGCStorage).To(Equal("configmaps"))
+			Expect(f.ChunkGCOwners).To(Equal([]string{"nginx", "redis"}))
+			Expect(f.ChunkGCGracePeriod).To(Equal(2 * time.Hour))
+		})
+	})
+
+	Describe("storage", func() {
+		var f *flags.Flags
+		var flagSet *pflag.FlagSet
+		BeforeEach(func() {
+			f = &flags.Flags{}
+			flagSet = pflag.NewFlagSet("test", pflag.ExitOnError)
+			f.AddTo(flagSet)
+		})
+
+		It("stores releases in Secrets by default", func() {
+			parseArgs(flagSet)
+			Expect(f.StorageDriver).To(Equal("secret"))
+			Expect(f.StorageOwner).To(Equal("helm"))
+			Expect(f.StorageCodec).To(Equal(storage.CodecNameGzip))
+			Expect(f.StorageChunkSize).To(Equal(storage.DefaultChunkSize))
+			Expect(f.StorageAdaptiveChunks).To(BeFalse())
+			Expect(f.StorageMaxObjectSize).To(Equal(corev1.MaxSecretSize))
+		})
+		It("uses the flag values", func() {
+			parseArgs(flagSet, "--storage-driver", "chunked-secret", "--storage-owner", "nginx", "--storage-codec", "zstd",
+				"--storage-chunk-size", "1024", "--storage-adaptive-chunk-size", "--storage-max-object-size", "2048")
+			Expect(f.StorageDriver).To(Equal("chunked-secret"))
+			Expect(f.StorageOwner).To(Equal("nginx"))
+			Expect(f.StorageCodec).To(Equal(storage.CodecNameZstd))
+			Expect(f.StorageChunkSize).To(Equal(1024))
+			Expect(f.StorageAdaptiveChunks).To(BeTrue())
+			Expect(f.StorageMaxObjectSize).To(Equal(2048))
+		})
 	})
 })
 
@@ -15,6 +15,8 @@
 package metrics
 
 import (
+	"errors"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	helmVersion "github.com/operator-framework/helm-operator-plugins/internal/version"
@@ -43,3 +45,46 @@ func RegisterBuildInfo(r prometheus.Registerer) {
 	buildInfo.Set(1)
 	r.MustRegister(buildInfo)
 }
+
+var (
+	watchedNamespaces = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "watched_namespace",
+			Help:      "Namespaces watched by the helm-operator when namespaces are selected by labels, with a value of 1 for each watched namespace",
+		},
+		[]string{"namespace"},
+	)
+	watchedNamespacesTotal = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "watched_namespaces",
+			Help:      "Number of namespaces watched by the helm-operator when namespaces are selected by labels",
+		},
+	)
+)
+
+// RegisterWatchedNamespaces registers the watched namespaces Collectors to be
+// included in metrics collection. It may be called more than once.
+func RegisterWatchedNamespaces(r prometheus.Registerer) {
+	for _, c := range []prometheus.Collector{watchedNamespaces, watchedNamespacesTotal} {
+		if err := r.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				panic(err)
+			}
+		}
+	}
+}
+
+// SetWatchedNamespaces records the namespaces that are added to or removed
+// from the set of watched namespaces, and the size of the set.
+func SetWatchedNamespaces(added, removed []string, total int) {
+	for _, ns := range added {
+		watchedNamespaces.WithLabelValues(ns).Set(1)
+	}
+	for _, ns := range removed {
+		watchedNamespaces.DeleteLabelValues(ns)
+	}
+	watchedNamespacesTotal.Set(float64(total))
+}
@@ -177,7 +177,11 @@ func (hcg *actionClientGetter) ActionClientFor(ctx context.Context, obj client.O
 	for _, provider := range hcg.postRendererProviders {
 		cpr = append(cpr, provider(rm, actionConfig.KubeClient, obj))
 	}
-	cpr = append(cpr, DefaultPostRendererFunc(rm, actionConfig.KubeClient, obj))
+	if isRemote(actionConfig.RESTClientGetter) {
+		cpr = append(cpr, &ownerPostRenderer{rm: rm, kubeClient: actionConfig.KubeClient, owner: obj, annotationsOnly: true})
+	} else {
+		cpr = append(cpr, DefaultPostRendererFunc(rm, actionConfig.KubeClient, obj))
+	}
 
 	return &actionClient{
 		conf: actionConfig,
@@ -258,7 +262,9 @@ func (c *actionClient) Install(name, namespace string, chrt *chart.Chart, vals m
 	rel, err := install.Run(chrt, vals)
 	if err != nil {
 		c.conf.Log("Install failed")
-		if c.enableFailureRollbacks && rel != nil {
+		// Dry runs never record a release, so a release with the same name
+		// that already exists must not be uninstalled.
+		if c.enableFailureRollbacks && rel != nil && !install.DryRun {
 			// Uninstall the failed release installation so that we can retry
 			// the installation again during the next reconciliation. In many
 			// cases, the issue is unresolvable without a change to the CR, but
@@ -292,7 +298,9 @@ func (c *actionClient) Upgrade(name, namespace string, chrt *chart.Chart, vals m
 	upgrade.Namespace = namespace
 	rel, err := upgrade.Run(name, chrt, vals)
 	if err != nil {
-		if c.enableFailureRollbacks && rel != nil {
+		// Dry runs never record a release, so the deployed release must not
+		// be rolled back.
+		if c.enableFailureRollbacks && rel != nil && !upgrade.DryRun {
 			rollbackOpts := append([]RollbackOption{func(rollback *action.Rollback) error {
 				rollback.Force = true
 				rollback.MaxHistory = upgrade.MaxHistory
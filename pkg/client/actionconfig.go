@@ -25,6 +25,7 @@ import (
 	"helm.sh/helm/v3/pkg/kube"
 	"helm.sh/helm/v3/pkg/storage"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
@@ -32,6 +33,8 @@ import (
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helmstorage "github.com/operator-framework/helm-operator-plugins/pkg/storage"
 )
 
 type ActionConfigGetter interface {
@@ -98,6 +101,21 @@ func ClientNamespaceMapper(m ObjectToStringMapper) ActionConfigGetterOption { //
 	}
 }
 
+// ClientClusterMapper configures the ActionConfigGetter to install the
+// releases of objects in the clusters returned by m, e.g.
+// RemoteClusters.ClusterFor. The ClientRestConfigMapper is called with the
+// rest config of the cluster instead of the base rest config. For objects for
+// which m returns nil, the base rest config, REST mapper and discovery client
+// are used.
+//
+// Objects in remote clusters cannot have owner references to the custom
+// resource, so the ActionClientGetter sets owner annotations on them instead.
+func ClientClusterMapper(m ObjectToClusterMapper) ActionConfigGetterOption {
+	return func(getter *actionConfigGetter) {
+		getter.objectToClientCluster = m
+	}
+}
+
 func StorageRestConfigMapper(f ObjectToRestConfigMapper) ActionConfigGetterOption {
 	return func(getter *actionConfigGetter) {
 		getter.objectToStorageRestConfig = f
@@ -143,6 +161,7 @@ type actionConfigGetter struct {
 
 	objectToClientRestConfig ObjectToRestConfigMapper
 	objectToClientNamespace  ObjectToStringMapper
+	objectToClientCluster    ObjectToClusterMapper
 
 	objectToStorageRestConfig ObjectToRestConfigMapper
 	objectToStorageDriver     ObjectToStorageDriverMapper
@@ -154,7 +173,27 @@ type actionConfigGetter struct {
 }
 
 func (acg *actionConfigGetter) ActionConfigFor(ctx context.Context, obj client.Object) (*action.Configuration, error) {
-	clientRestConfig, err := acg.objectToClientRestConfig(ctx, obj, acg.baseRestConfig)
+	var cluster *Cluster
+	if acg.objectToClientCluster != nil {
+		var err error
+		cluster, err = acg.objectToClientCluster(ctx, obj, acg.baseRestConfig)
+		if err != nil {
+			return nil, fmt.Errorf("get client cluster for object: %v", err)
+		}
+	}
+
+	var (
+		clientBaseRestConfig = acg.baseRestConfig
+		clientRestMapper     = acg.restMapper
+		clientDiscovery      = acg.discoveryClient
+	)
+	if cluster != nil {
+		clientBaseRestConfig = cluster.RestConfig
+		clientRestMapper = cluster.RESTMapper
+		clientDiscovery = cluster.DiscoveryClient
+	}
+
+	clientRestConfig, err := acg.objectToClientRestConfig(ctx, obj, clientBaseRestConfig)
 	if err != nil {
 		return nil, fmt.Errorf("get client rest config for object: %v", err)
 	}
@@ -164,7 +203,8 @@ func (acg *actionConfigGetter) ActionConfigFor(ctx context.Context, obj client.O
 		return nil, fmt.Errorf("get client namespace for object: %v", err)
 	}
 
-	clientRCG := newRESTClientGetter(clientRestConfig, acg.restMapper, acg.discoveryClient, clientNamespace)
+	clientRCG := newRESTClientGetter(clientRestConfig, clientRestMapper, clientDiscovery, clientNamespace)
+	clientRCG.remote = cluster != nil
 	clientKC := kube.New(clientRCG)
 	clientKC.Namespace = clientNamespace
 
@@ -231,3 +271,53 @@ func DefaultSecretsStorageDriver(opts SecretsStorageDriverOpts) ObjectToStorageD
 		return d, nil
 	}
 }
+
+type ChunkedSecretsStorageDriverOpts struct {
+	// Owner is the value of the owner label of the stored releases. It
+	// defaults to "helm".
+	Owner string
+	// Config configures the chunked driver, e.g. its codec and whether the
+	// chunk size is chosen adaptively. See helmstorage.ChunkedConfig for
+	// its defaults.
+	Config                   helmstorage.ChunkedConfig
+	DisableOwnerRefInjection bool
+	StorageNamespaceMapper   ObjectToStringMapper
+}
+
+// ChunkedSecretsStorageDriver returns a storage driver mapper that stores
+// releases in chunked Secrets, which allows releases to exceed the maximum
+// size of a single Secret.
+func ChunkedSecretsStorageDriver(opts ChunkedSecretsStorageDriverOpts) ObjectToStorageDriverMapper {
+	if opts.StorageNamespaceMapper == nil {
+		opts.StorageNamespaceMapper = getObjectNamespace
+	}
+	if opts.Owner == "" {
+		opts.Owner = "helm"
+	}
+	return func(ctx context.Context, obj client.Object, restConfig *rest.Config) (driver.Driver, error) {
+		storageNamespace, err := opts.StorageNamespaceMapper(obj)
+		if err != nil {
+			return nil, fmt.Errorf("get storage namespace for object: %v", err)
+		}
+		secretsInterface, err := v1.NewForConfig(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("create secrets client for storage: %v", err)
+		}
+
+		secretClient := secretsInterface.Secrets(storageNamespace)
+		if !opts.DisableOwnerRefInjection {
+			// Only index secrets are owned by the object. Chunk secrets are
+			// owned by their index secret, and an object may only have one
+			// controller reference.
+			ownerRef := metav1.NewControllerRef(obj, obj.GetObjectKind().GroupVersionKind())
+			secretClient = NewOwnerRefSecretClient(secretClient, []metav1.OwnerReference{*ownerRef}, func(secret *corev1.Secret) bool {
+				return secret.Type == helmstorage.SecretTypeChunkedIndex
+			})
+		}
+		config := opts.Config
+		if config.Log == nil {
+			config.Log = getDebugLogger(ctx)
+		}
+		return helmstorage.NewChunkedSecrets(secretClient, opts.Owner, config), nil
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+	helmstorage "github.com/operator-framework/helm-operator-plugins/pkg/storage"
 )
 
 var _ = Describe("ActionConfig", func() {
@@ -214,6 +215,39 @@ metadata:
 				Expect(actual).To(HaveLen(1))
 				Expect(actual[0]).To(Equal(expected))
 			})
+
+			It("should store releases in chunked secrets", func() {
+				acg, err := NewActionConfigGetter(cfg, rm, StorageDriverMapper(ChunkedSecretsStorageDriver(ChunkedSecretsStorageDriverOpts{
+					Owner: "test-owner",
+				})))
+				Expect(err).ToNot(HaveOccurred())
+
+				ac, err := acg.ActionConfigFor(context.Background(), obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ac.Releases.Name()).To(Equal("test-owner/chunkedSecrets"))
+
+				By("Installing a release")
+				i := action.NewInstall(ac)
+				i.ReleaseName = fmt.Sprintf("release-name-%s", rand.String(8))
+				i.Namespace = obj.GetNamespace()
+				_, err = i.Run(&chrt, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying only the index secret is owned by the object")
+				secretKey := types.NamespacedName{
+					Namespace: obj.GetNamespace(),
+					Name:      fmt.Sprintf("sh.helm.release.v1.%s.v1", i.ReleaseName),
+				}
+				secret := &corev1.Secret{}
+				Expect(cl.Get(context.Background(), secretKey, secret)).To(Succeed())
+				Expect(secret.Type).To(Equal(helmstorage.SecretTypeChunkedIndex))
+				Expect(secret.OwnerReferences).To(HaveLen(1))
+				Expect(secret.OwnerReferences[0].UID).To(Equal(obj.GetUID()))
+
+				By("Uninstalling the release")
+				_, err = action.NewUninstall(ac).Run(i.ReleaseName)
+				Expect(err).ToNot(HaveOccurred())
+			})
 		})
 	})
 
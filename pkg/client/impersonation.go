@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultServiceAccountPath is the path of the name of the ServiceAccount to
+// impersonate in custom resources.
+var DefaultServiceAccountPath = []string{"spec", "serviceAccountName"}
+
+// ImpersonationOpts configures ImpersonateServiceAccount.
+type ImpersonationOpts struct {
+	// ServiceAccountPath is the path of the name of the ServiceAccount in
+	// custom resources. It defaults to DefaultServiceAccountPath.
+	ServiceAccountPath []string
+	// DefaultServiceAccount is the name of the ServiceAccount that is
+	// impersonated for custom resources that do not name one, e.g. a
+	// ServiceAccount that is created in each tenant namespace. If it is
+	// empty, custom resources must name a ServiceAccount.
+	DefaultServiceAccount string
+	// AllowedServiceAccounts are the names of the ServiceAccounts that may
+	// be impersonated. Names may contain the wildcards of path.Match, e.g.
+	// "deployer-*". If it is empty, all ServiceAccounts may be impersonated.
+	AllowedServiceAccounts []string
+	// ClusterScopedNamespace is the namespace of the ServiceAccounts that
+	// are impersonated for cluster-scoped custom resources. If it is empty,
+	// cluster-scoped custom resources are rejected.
+	ClusterScopedNamespace string
+}
+
+// ImpersonateServiceAccount returns a rest config mapper that configures the
+// rest config to impersonate a ServiceAccount, so that Helm actions can only
+// manage what the RBAC of the ServiceAccount allows. It is meant to be used
+// with ClientRestConfigMapper; releases are still stored with the base rest
+// config, so that the ServiceAccount does not need access to them.
+//
+// The ServiceAccount is named in the custom resource, or defaults to
+// opts.DefaultServiceAccount, and is always in the namespace of the custom
+// resource, so that custom resources cannot use the ServiceAccounts of other
+// namespaces. ServiceAccounts that are not allowed by
+// opts.AllowedServiceAccounts are rejected.
+//
+// The identity of the base rest config must be allowed to impersonate
+// ServiceAccounts and the groups of ServiceAccounts, i.e. have the
+// "impersonate" verb on the "serviceaccounts" and "groups" resources of the
+// core API group.
+func ImpersonateServiceAccount(opts ImpersonationOpts) ObjectToRestConfigMapper {
+	if len(opts.ServiceAccountPath) == 0 {
+		opts.ServiceAccountPath = DefaultServiceAccountPath
+	}
+	return func(_ context.Context, obj client.Object, baseRestConfig *rest.Config) (*rest.Config, error) {
+		namespace, name, err := serviceAccountFor(obj, opts)
+		if err != nil {
+			return nil, err
+		}
+		cfg := rest.CopyConfig(baseRestConfig)
+		cfg.Impersonate = rest.ImpersonationConfig{
+			UserName: fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name),
+			Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"},
+		}
+		return cfg, nil
+	}
+}
+
+func serviceAccountFor(obj client.Object, opts ImpersonationOpts) (string, string, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return "", "", fmt.Errorf("convert object to unstructured: %w", err)
+		}
+		u = &unstructured.Unstructured{Object: content}
+	}
+	name, found, err := unstructured.NestedString(u.Object, opts.ServiceAccountPath...)
+	if err != nil {
+		return "", "", fmt.Errorf("get service account name: %w", err)
+	}
+	if !found || name == "" {
+		if opts.DefaultServiceAccount == "" {
+			return "", "", fmt.Errorf("%s must name the service account to impersonate", strings.Join(opts.ServiceAccountPath, "."))
+		}
+		name = opts.DefaultServiceAccount
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid service account name %q: %s", name, strings.Join(errs, ", "))
+	}
+	if !serviceAccountAllowed(name, opts.AllowedServiceAccounts) {
+		return "", "", fmt.Errorf("service account %q is not allowed to be impersonated", name)
+	}
+
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		if opts.ClusterScopedNamespace == "" {
+			return "", "", errors.New("cannot impersonate a service account for a cluster-scoped object")
+		}
+		namespace = opts.ClusterScopedNamespace
+	}
+	return namespace, name, nil
+}
+
+func serviceAccountAllowed(name string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, pattern := range allowed {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+)
+
+var _ = Describe("ImpersonateServiceAccount", func() {
+	var (
+		ctx  context.Context
+		obj  *unstructured.Unstructured
+		base *rest.Config
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = testutil.BuildTestCR(gvk)
+		obj.SetNamespace("tenant-a")
+		base = &rest.Config{Host: "https://example.com", BearerToken: "operator-token"}
+	})
+
+	It("should impersonate the service account named in the object", func() {
+		Expect(unstructured.SetNestedField(obj.Object, "deployer", "spec", "serviceAccountName")).To(Succeed())
+
+		cfg, err := ImpersonateServiceAccount(ImpersonationOpts{})(ctx, obj, base)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Host).To(Equal(base.Host))
+		Expect(cfg.BearerToken).To(Equal(base.BearerToken))
+		Expect(cfg.Impersonate).To(Equal(rest.ImpersonationConfig{
+			UserName: "system:serviceaccount:tenant-a:deployer",
+			Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:tenant-a", "system:authenticated"},
+		}))
+		Expect(base.Impersonate).To(Equal(rest.ImpersonationConfig{}))
+	})
+
+	It("should impersonate the default service account", func() {
+		cfg, err := ImpersonateServiceAccount(ImpersonationOpts{DefaultServiceAccount: "helm"})(ctx, obj, base)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Impersonate.UserName).To(Equal("system:serviceaccount:tenant-a:helm"))
+	})
+
+	It("should read the service account name from the configured path", func() {
+		Expect(unstructured.SetNestedField(obj.Object, "deployer", "spec", "rbac", "serviceAccount")).To(Succeed())
+
+		cfg, err := ImpersonateServiceAccount(ImpersonationOpts{ServiceAccountPath: []string{"spec", "rbac", "serviceAccount"}})(ctx, obj, base)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Impersonate.UserName).To(Equal("system:serviceaccount:tenant-a:deployer"))
+	})
+
+	It("should work with typed objects", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-b", Name: "pod"},
+			Spec:       corev1.PodSpec{ServiceAccountName: "deployer"},
+		}
+		cfg, err := ImpersonateServiceAccount(ImpersonationOpts{})(ctx, pod, base)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Impersonate.UserName).To(Equal("system:serviceaccount:tenant-b:deployer"))
+	})
+
+	It("should use the configured namespace for cluster-scoped objects", func() {
+		obj.SetNamespace("")
+		opts := ImpersonationOpts{DefaultServiceAccount: "helm"}
+		_, err := ImpersonateServiceAccount(opts)(ctx, obj, base)
+		Expect(err).To(MatchError(ContainSubstring("cluster-scoped object")))
+
+		opts.ClusterScopedNamespace = "operators"
+		cfg, err := ImpersonateServiceAccount(opts)(ctx, obj, base)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Impersonate.UserName).To(Equal("system:serviceaccount:operators:helm"))
+	})
+
+	DescribeTable("should enforce the allowlist",
+		func(name string, allowed []string, errMsg string) {
+			Expect(unstructured.SetNestedField(obj.Object, name, "spec", "serviceAccountName")).To(Succeed())
+			_, err := ImpersonateServiceAccount(ImpersonationOpts{AllowedServiceAccounts: allowed})(ctx, obj, base)
+			if errMsg == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(errMsg)))
+			}
+		},
+		Entry("exact name", "deployer", []string{"deployer"}, ""),
+		Entry("wildcard", "deployer-a", []string{"admin", "deployer-*"}, ""),
+		Entry("not allowed", "admin", []string{"deployer-*"}, `service account "admin" is not allowed`),
+		Entry("invalid name", "tenant-b:admin", nil, "invalid service account name"),
+	)
+
+	It("should fail without a service account name", func() {
+		_, err := ImpersonateServiceAccount(ImpersonationOpts{})(ctx, obj, base)
+		Expect(err).To(MatchError("spec.serviceAccountName must name the service account to impersonate"))
+	})
+
+	It("should be used by the ActionConfigGetter with ClientRestConfigMapper", func() {
+		Expect(unstructured.SetNestedField(obj.Object, "deployer", "spec", "serviceAccountName")).To(Succeed())
+		acg, err := NewActionConfigGetter(cfg, nil, ClientRestConfigMapper(ImpersonateServiceAccount(ImpersonationOpts{})))
+		Expect(err).NotTo(HaveOccurred())
+
+		ac, err := acg.ActionConfigFor(ctx, obj)
+		Expect(err).NotTo(HaveOccurred())
+		restConfig, err := ac.RESTClientGetter.ToRESTConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restConfig.Impersonate.UserName).To(Equal("system:serviceaccount:tenant-a:deployer"))
+
+		unstructured.RemoveNestedField(obj.Object, "spec", "serviceAccountName")
+		_, err = acg.ActionConfigFor(ctx, obj)
+		Expect(err).To(MatchError(ContainSubstring("must name the service account")))
+	})
+})
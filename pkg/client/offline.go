@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// offlineHost is the host of the rest config of offline action clients. They
+// never send requests, so it only needs to be a valid URL.
+const offlineHost = "http://localhost:0"
+
+// NewOfflineActionClientGetter returns an ActionClientGetter for rendering
+// charts without a cluster. Its action clients store releases in memory and
+// use a RESTMapper returned by NewOfflineRESTMapper. They only support
+// client-only dry-run installs, for which they apply the same post-renderers
+// as the action clients returned by NewActionClientGetter.
+func NewOfflineActionClientGetter(opts ...ActionClientGetterOption) (ActionClientGetter, error) {
+	acg, err := NewActionConfigGetter(&rest.Config{Host: offlineHost}, NewOfflineRESTMapper(),
+		StorageDriverMapper(func(_ context.Context, obj client.Object, _ *rest.Config) (driver.Driver, error) {
+			d := driver.NewMemory()
+			d.SetNamespace(obj.GetNamespace())
+			return d, nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create offline action config getter: %w", err)
+	}
+	return NewActionClientGetter(acg, opts...)
+}
+
+// clusterScopedKinds are the built-in kinds that are not namespaced.
+var clusterScopedKinds = sets.New[schema.GroupKind](
+	schema.GroupKind{Kind: "Namespace"},
+	schema.GroupKind{Kind: "Node"},
+	schema.GroupKind{Kind: "PersistentVolume"},
+	schema.GroupKind{Kind: "ComponentStatus"},
+	schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+	schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+	schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"},
+	schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"},
+	schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+	schema.GroupKind{Group: "apiregistration.k8s.io", Kind: "APIService"},
+	schema.GroupKind{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"},
+	schema.GroupKind{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"},
+	schema.GroupKind{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"},
+	schema.GroupKind{Group: "networking.k8s.io", Kind: "IngressClass"},
+	schema.GroupKind{Group: "node.k8s.io", Kind: "RuntimeClass"},
+	schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+	schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+	schema.GroupKind{Group: "scheduling.k8s.io", Kind: "PriorityClass"},
+	schema.GroupKind{Group: "storage.k8s.io", Kind: "CSIDriver"},
+	schema.GroupKind{Group: "storage.k8s.io", Kind: "CSINode"},
+	schema.GroupKind{Group: "storage.k8s.io", Kind: "StorageClass"},
+	schema.GroupKind{Group: "storage.k8s.io", Kind: "VolumeAttachment"},
+)
+
+// NewOfflineRESTMapper returns a RESTMapper that maps any kind without
+// discovery. Built-in cluster-scoped kinds are mapped as cluster-scoped, all
+// other kinds are assumed to be namespaced. Resource names are guessed from
+// the kind.
+func NewOfflineRESTMapper() meta.RESTMapper {
+	return &offlineRESTMapper{DefaultRESTMapper: meta.NewDefaultRESTMapper(nil)}
+}
+
+type offlineRESTMapper struct {
+	mu sync.RWMutex
+	*meta.DefaultRESTMapper
+}
+
+func (m *offlineRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.DefaultRESTMapper.KindFor(resource)
+}
+
+func (m *offlineRESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.DefaultRESTMapper.KindsFor(resource)
+}
+
+func (m *offlineRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.DefaultRESTMapper.ResourceFor(input)
+}
+
+func (m *offlineRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.DefaultRESTMapper.ResourcesFor(input)
+}
+
+func (m *offlineRESTMapper) ResourceSingularizer(resource string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.DefaultRESTMapper.ResourceSingularizer(resource)
+}
+
+func (m *offlineRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addKind(gk, versions...)
+	return m.DefaultRESTMapper.RESTMapping(gk, versions...)
+}
+
+func (m *offlineRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addKind(gk, versions...)
+	return m.DefaultRESTMapper.RESTMappings(gk, versions...)
+}
+
+// addKind adds the given versions of gk to the mapper, unless they are
+// already known. It must be called with mu held.
+func (m *offlineRESTMapper) addKind(gk schema.GroupKind, versions ...string) {
+	scope := meta.RESTScopeNamespace
+	if clusterScopedKinds.Has(gk) {
+		scope = meta.RESTScopeRoot
+	}
+	for _, v := range versions {
+		if v == "" {
+			continue
+		}
+		if _, err := m.DefaultRESTMapper.RESTMapping(gk, v); err == nil {
+			continue
+		}
+		m.Add(gk.WithVersion(v), scope)
+	}
+}
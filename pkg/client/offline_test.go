@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+)
+
+var _ = Describe("NewOfflineRESTMapper", func() {
+	var rm meta.RESTMapper
+
+	BeforeEach(func() {
+		rm = NewOfflineRESTMapper()
+	})
+
+	It("maps built-in cluster-scoped kinds as cluster-scoped", func() {
+		mapping, err := rm.RESTMapping(schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}, "v1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mapping.Scope.Name()).To(Equal(meta.RESTScopeNameRoot))
+		Expect(mapping.Resource).To(Equal(schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}))
+	})
+
+	It("maps other kinds as namespaced", func() {
+		mapping, err := rm.RESTMapping(schema.GroupKind{Group: "example.com", Kind: "TestApp"}, "v1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mapping.Scope.Name()).To(Equal(meta.RESTScopeNameNamespace))
+		Expect(mapping.Resource.Resource).To(Equal("testapps"))
+
+		gvk, err := rm.KindFor(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "testapps"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gvk).To(Equal(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "TestApp"}))
+	})
+
+	It("fails for kinds without a version", func() {
+		_, err := rm.RESTMapping(schema.GroupKind{Group: "example.com", Kind: "TestApp"})
+		Expect(meta.IsNoMatchError(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("NewOfflineActionClientGetter", func() {
+	It("renders and post-renders a release without a cluster", func() {
+		acg, err := NewOfflineActionClientGetter()
+		Expect(err).ToNot(HaveOccurred())
+
+		obj := testutil.BuildTestCR(gvk)
+		ac, err := acg.ActionClientFor(context.Background(), obj)
+		Expect(err).ToNot(HaveOccurred())
+
+		rel, err := ac.Install(obj.GetName(), obj.GetNamespace(), &chrt, nil, func(i *action.Install) error {
+			i.DryRun = true
+			i.ClientOnly = true
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rel.Manifest).ToNot(BeEmpty())
+
+		docs := bytes.Split([]byte(rel.Manifest), []byte("---\n"))
+		for _, doc := range docs {
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+			u := &unstructured.Unstructured{}
+			Expect(yaml.Unmarshal(doc, &u.Object)).To(Succeed())
+			Expect(u.GetOwnerReferences()).To(HaveLen(1))
+			Expect(u.GetOwnerReferences()[0].UID).To(Equal(obj.GetUID()))
+		}
+	})
+})
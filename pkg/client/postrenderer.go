@@ -2,7 +2,10 @@ package client
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 
 	sdkhandler "github.com/operator-framework/operator-lib/handler"
 	"helm.sh/helm/v3/pkg/action"
@@ -12,6 +15,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/cli-runtime/pkg/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
@@ -89,7 +94,16 @@ func (f PostRendererFunc) Run(buffer *bytes.Buffer) (*bytes.Buffer, error) {
 // in a helm release manifest. This is the default post-renderer used by ActionClients created with
 // NewActionClientGetter.
 var DefaultPostRendererFunc = func(rm meta.RESTMapper, kubeClient kube.Interface, owner client.Object) postrender.PostRenderer {
-	return &ownerPostRenderer{rm, kubeClient, owner}
+	return &ownerPostRenderer{rm: rm, kubeClient: kubeClient, owner: owner}
+}
+
+// LabelPostRendererFunc returns a post-renderer provider whose post-renderers
+// add the given labels to all objects in a helm release manifest, e.g. to
+// watch them with a cache that only contains objects with these labels.
+func LabelPostRendererFunc(labels map[string]string) PostRendererProvider {
+	return func(meta.RESTMapper, kube.Interface, client.Object) postrender.PostRenderer {
+		return labelPostRenderer(labels)
+	}
 }
 
 type chainedPostRenderer []postrender.PostRenderer
@@ -113,6 +127,10 @@ type ownerPostRenderer struct {
 	rm         meta.RESTMapper
 	kubeClient kube.Interface
 	owner      client.Object
+
+	// annotationsOnly configures the post-renderer to set owner annotations
+	// on all objects, e.g. because they are installed in a remote cluster.
+	annotationsOnly bool
 }
 
 func (pr *ownerPostRenderer) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
@@ -131,9 +149,12 @@ func (pr *ownerPostRenderer) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
 			return err
 		}
 		u := &unstructured.Unstructured{Object: objMap}
-		useOwnerRef, err := controllerutil.SupportsOwnerReference(pr.rm, pr.owner, u)
-		if err != nil {
-			return err
+		useOwnerRef := false
+		if !pr.annotationsOnly {
+			useOwnerRef, err = controllerutil.SupportsOwnerReference(pr.rm, pr.owner, u)
+			if err != nil {
+				return err
+			}
 		}
 		if useOwnerRef && !manifestutil.HasResourcePolicyKeep(u.GetAnnotations()) {
 			ownerRef := metav1.NewControllerRef(pr.owner, pr.owner.GetObjectKind().GroupVersionKind())
@@ -158,3 +179,59 @@ func (pr *ownerPostRenderer) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
 	}
 	return &out, nil
 }
+
+type labelPostRenderer map[string]string
+
+func (pr labelPostRenderer) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
+	out := bytes.Buffer{}
+	dec := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(in.Bytes()), 4096)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		u := &unstructured.Unstructured{}
+		if err := utiljson.Unmarshal(raw, &u.Object); err != nil {
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		if u.IsList() {
+			if err := u.EachListItem(func(o runtime.Object) error {
+				pr.addTo(o.(*unstructured.Unstructured))
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		} else {
+			pr.addTo(u)
+		}
+		outData, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := out.WriteString("---\n" + string(outData)); err != nil {
+			return nil, err
+		}
+	}
+	return &out, nil
+}
+
+func (pr labelPostRenderer) addTo(u *unstructured.Unstructured) {
+	labels := u.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range pr {
+		labels[k] = v
+	}
+	u.SetLabels(labels)
+}
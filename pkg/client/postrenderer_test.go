@@ -70,6 +70,54 @@ var _ = Describe("chainedPostRenderer", func() {
 	})
 })
 
+var _ = Describe("labelPostRenderer", func() {
+	It("adds the labels to all objects", func() {
+		pr := LabelPostRendererFunc(map[string]string{"app.example.com/managed": "true"})(nil, nil, nil)
+		out, err := pr.Run(bytes.NewBufferString(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  labels:
+    app: test
+data:
+  count: "1"
+---
+# empty
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ServiceAccount
+  metadata:
+    name: test
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.String()).To(Equal(`---
+apiVersion: v1
+data:
+  count: "1"
+kind: ConfigMap
+metadata:
+  labels:
+    app: test
+    app.example.com/managed: "true"
+  name: test
+---
+apiVersion: v1
+items:
+- apiVersion: v1
+  kind: ServiceAccount
+  metadata:
+    labels:
+      app.example.com/managed: "true"
+    name: test
+kind: List
+`))
+	})
+})
+
 var _ = Describe("PostRender install options", func() {
 	var (
 		install *action.Install
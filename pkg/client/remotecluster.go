@@ -0,0 +1,419 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// DefaultKubeConfigSecretKey is the key of the kubeconfig in the Secrets
+// referenced by custom resources, unless the reference specifies another key.
+const DefaultKubeConfigSecretKey = "kubeconfig"
+
+// DefaultKubeConfigSecretRefPath is the path of the reference to the
+// kubeconfig Secret in custom resources.
+var DefaultKubeConfigSecretRefPath = []string{"spec", "kubeConfigSecretRef"}
+
+// KubeConfigSecretRef references the Secret that contains the kubeconfig of
+// the cluster that the release of a custom resource is installed in.
+type KubeConfigSecretRef struct {
+	// Name is the name of the Secret.
+	Name string `json:"name"`
+	// Namespace is the namespace of the Secret. It is only allowed for
+	// cluster-scoped custom resources, for which it is required. The Secret
+	// of a namespaced custom resource must be in its namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Key is the key of the kubeconfig in the Secret. It defaults to
+	// DefaultKubeConfigSecretKey.
+	Key string `json:"key,omitempty"`
+}
+
+// Cluster contains the clients for a remote cluster. Clusters are created and
+// cached by RemoteClusters.
+type Cluster struct {
+	// Secret is the Secret that contains the kubeconfig of the cluster.
+	Secret types.NamespacedName
+
+	RestConfig      *rest.Config
+	RESTMapper      meta.RESTMapper
+	DiscoveryClient discovery.CachedDiscoveryInterface
+
+	httpClient *http.Client
+	hash       [sha256.Size]byte
+	clusters   *RemoteClusters
+
+	mu      sync.Mutex
+	cache   cache.Cache
+	cancel  context.CancelFunc
+	stopped bool
+}
+
+// ObjectToClusterMapper returns the cluster that the release of an object is
+// installed in. It returns nil if the release is installed in the cluster of
+// the base rest config.
+type ObjectToClusterMapper func(context.Context, client.Object, *rest.Config) (*Cluster, error)
+
+// RemoteClustersOption configures RemoteClusters.
+type RemoteClustersOption func(*RemoteClusters)
+
+// KubeConfigSecretRefPath sets the path of the KubeConfigSecretRef in custom
+// resources. It defaults to DefaultKubeConfigSecretRefPath.
+func KubeConfigSecretRefPath(fields ...string) RemoteClustersOption {
+	return func(rc *RemoteClusters) {
+		rc.refPath = fields
+	}
+}
+
+// RemoteClusters manages the clients for remote clusters whose kubeconfigs
+// are stored in Secrets that are referenced by custom resources, e.g.
+//
+//	spec:
+//	  kubeConfigSecretRef:
+//	    name: spoke-1
+//
+// Custom resources without a reference are installed in the local cluster.
+//
+// The clients of a cluster are cached per Secret and rebuilt when the
+// kubeconfig in the Secret changes. The caches that back the dependent
+// watches of remote clusters are started on demand and stopped when the
+// kubeconfig changes or RemoteClusters is stopped, so RemoteClusters must be
+// added to the manager.
+//
+// Kubeconfigs that execute commands, use auth provider plugins or reference
+// files are rejected, since they would allow anybody who can create Secrets
+// to run commands or read files in the operator's container.
+type RemoteClusters struct {
+	reader  client.Reader
+	refPath []string
+
+	mu       sync.Mutex
+	ctx      context.Context
+	clusters map[types.NamespacedName]*Cluster
+	objects  map[types.UID]remoteClusterObject
+}
+
+type remoteClusterObject struct {
+	gvk    schema.GroupVersionKind
+	key    types.NamespacedName
+	secret types.NamespacedName
+}
+
+// NewRemoteClusters returns RemoteClusters that read the kubeconfig Secrets
+// with reader, e.g. the client of a manager.
+func NewRemoteClusters(reader client.Reader, opts ...RemoteClustersOption) *RemoteClusters {
+	rc := &RemoteClusters{
+		reader:   reader,
+		refPath:  DefaultKubeConfigSecretRefPath,
+		clusters: map[types.NamespacedName]*Cluster{},
+		objects:  map[types.UID]remoteClusterObject{},
+	}
+	for _, o := range opts {
+		o(rc)
+	}
+	return rc
+}
+
+// KubeConfigSecretRefPath returns the path of the KubeConfigSecretRef in
+// custom resources.
+func (rc *RemoteClusters) KubeConfigSecretRefPath() []string {
+	return rc.refPath
+}
+
+// Start implements manager.Runnable. It enables the caches of remote
+// clusters until ctx is done, and stops all caches afterwards. Since
+// RemoteClusters may be shared by several reconcilers that each add it to the
+// manager, only its first start has an effect, and later starts just wait
+// for their ctx to be done.
+func (rc *RemoteClusters) Start(ctx context.Context) error {
+	rc.mu.Lock()
+	if rc.ctx != nil {
+		rc.mu.Unlock()
+		<-ctx.Done()
+		return nil
+	}
+	rc.ctx = ctx
+	rc.mu.Unlock()
+
+	<-ctx.Done()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, c := range rc.clusters {
+		c.stop()
+	}
+	return nil
+}
+
+// ClusterFor returns the cluster that the release of obj is installed in, or
+// nil if obj does not reference a kubeconfig Secret. It implements
+// ObjectToClusterMapper.
+func (rc *RemoteClusters) ClusterFor(ctx context.Context, obj client.Object, _ *rest.Config) (*Cluster, error) {
+	ref, err := rc.secretRef(obj)
+	if err != nil {
+		return nil, err
+	}
+	if ref == nil {
+		rc.Forget(obj)
+		return nil, nil
+	}
+
+	secretKey := types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}
+	switch {
+	case obj.GetNamespace() == "" && ref.Namespace == "":
+		return nil, errors.New("kubeconfig secret reference of cluster-scoped object must have a namespace")
+	case obj.GetNamespace() == "":
+		secretKey.Namespace = ref.Namespace
+	case ref.Namespace != "" && ref.Namespace != obj.GetNamespace():
+		return nil, fmt.Errorf("kubeconfig secret must be in namespace %q of the object", obj.GetNamespace())
+	}
+	key := ref.Key
+	if key == "" {
+		key = DefaultKubeConfigSecretKey
+	}
+
+	secret := &corev1.Secret{}
+	if err := rc.reader.Get(ctx, secretKey, secret); err != nil {
+		return nil, fmt.Errorf("get kubeconfig secret %s: %w", secretKey, err)
+	}
+	kubeConfig, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret %s has no key %q", secretKey, key)
+	}
+	hash := sha256.Sum256(kubeConfig)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	c, ok := rc.clusters[secretKey]
+	if !ok || c.hash != hash {
+		newCluster, err := rc.newCluster(secretKey, kubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("create clients for kubeconfig secret %s: %w", secretKey, err)
+		}
+		if c != nil {
+			c.stop()
+		}
+		c = newCluster
+		rc.clusters[secretKey] = c
+	}
+	previous, ok := rc.objects[obj.GetUID()]
+	rc.objects[obj.GetUID()] = remoteClusterObject{
+		gvk:    obj.GetObjectKind().GroupVersionKind(),
+		key:    client.ObjectKeyFromObject(obj),
+		secret: secretKey,
+	}
+	if ok && previous.secret != secretKey {
+		rc.stopUnreferenced(previous.secret)
+	}
+	return c, nil
+}
+
+// Lookup returns the cluster that obj was last mapped to by ClusterFor, or
+// nil if it was mapped to the local cluster or not mapped yet.
+func (rc *RemoteClusters) Lookup(obj client.Object) *Cluster {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	o, ok := rc.objects[obj.GetUID()]
+	if !ok {
+		return nil
+	}
+	return rc.clusters[o.secret]
+}
+
+// ObjectsFor returns the keys of the objects of kind gvk that were mapped to
+// the cluster of the given kubeconfig Secret by ClusterFor.
+func (rc *RemoteClusters) ObjectsFor(secret types.NamespacedName, gvk schema.GroupVersionKind) []types.NamespacedName {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var keys []types.NamespacedName
+	for _, o := range rc.objects {
+		if o.secret == secret && o.gvk == gvk {
+			keys = append(keys, o.key)
+		}
+	}
+	return keys
+}
+
+// Forget forgets the cluster that obj was mapped to by ClusterFor, e.g. after
+// the release of obj was uninstalled. The cluster is stopped if no other
+// object is mapped to it anymore.
+func (rc *RemoteClusters) Forget(obj client.Object) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	o, ok := rc.objects[obj.GetUID()]
+	if !ok {
+		return
+	}
+	delete(rc.objects, obj.GetUID())
+	rc.stopUnreferenced(o.secret)
+}
+
+// stopUnreferenced stops and removes the cluster of the given kubeconfig
+// Secret if no object is mapped to it anymore. rc.mu must be held.
+func (rc *RemoteClusters) stopUnreferenced(secret types.NamespacedName) {
+	for _, o := range rc.objects {
+		if o.secret == secret {
+			return
+		}
+	}
+	if c, ok := rc.clusters[secret]; ok {
+		c.stop()
+		delete(rc.clusters, secret)
+	}
+}
+
+func (rc *RemoteClusters) secretRef(obj client.Object) (*KubeConfigSecretRef, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("convert object to unstructured: %w", err)
+		}
+		u = &unstructured.Unstructured{Object: content}
+	}
+	refMap, found, err := unstructured.NestedMap(u.Object, rc.refPath...)
+	if err != nil {
+		return nil, fmt.Errorf("get kubeconfig secret reference: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	ref := &KubeConfigSecretRef{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(refMap, ref); err != nil {
+		return nil, fmt.Errorf("parse kubeconfig secret reference: %w", err)
+	}
+	if ref.Name == "" {
+		return nil, errors.New("kubeconfig secret reference must have a name")
+	}
+	return ref, nil
+}
+
+func (rc *RemoteClusters) newCluster(secret types.NamespacedName, kubeConfig []byte) (*Cluster, error) {
+	cfg, err := restConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create http client: %w", err)
+	}
+	rm, err := apiutil.NewDynamicRESTMapper(cfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("create rest mapper: %w", err)
+	}
+	dc, err := discovery.NewDiscoveryClientForConfigAndClient(cfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("create discovery client: %w", err)
+	}
+	return &Cluster{
+		Secret:          secret,
+		RestConfig:      cfg,
+		RESTMapper:      rm,
+		DiscoveryClient: memory.NewMemCacheClient(dc),
+		httpClient:      httpClient,
+		hash:            sha256.Sum256(kubeConfig),
+		clusters:        rc,
+	}, nil
+}
+
+// restConfigFromKubeConfig returns the rest config for the current context of
+// kubeConfig. It rejects kubeconfigs that execute commands, use auth provider
+// plugins or reference files.
+func restConfigFromKubeConfig(kubeConfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return nil, fmt.Errorf("user %q: exec credential plugins are not supported", name)
+		case authInfo.AuthProvider != nil:
+			return nil, fmt.Errorf("user %q: auth provider plugins are not supported", name)
+		case authInfo.ClientCertificate != "" || authInfo.ClientKey != "" || authInfo.TokenFile != "":
+			return nil, fmt.Errorf("user %q: file references are not supported", name)
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return nil, fmt.Errorf("cluster %q: file references are not supported", name)
+		}
+	}
+	cfg, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("create rest config from kubeconfig: %w", err)
+	}
+	return cfg, nil
+}
+
+// Cache returns the cache of the cluster, starting it on first use. The
+// cache is stopped when the kubeconfig of the cluster changes, or when
+// the RemoteClusters that created the cluster are stopped.
+func (c *Cluster) Cache() (cache.Cache, error) {
+	c.clusters.mu.Lock()
+	parent := c.clusters.ctx
+	c.clusters.mu.Unlock()
+	if parent == nil {
+		return nil, errors.New("remote clusters have not been started")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return nil, fmt.Errorf("cluster of kubeconfig secret %s has been stopped", c.Secret)
+	}
+	if c.cache != nil {
+		return c.cache, nil
+	}
+	ca, err := cache.New(c.RestConfig, cache.Options{HTTPClient: c.httpClient, Mapper: c.RESTMapper})
+	if err != nil {
+		return nil, fmt.Errorf("create cache: %w", err)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		_ = ca.Start(ctx)
+	}()
+	c.cache, c.cancel = ca, cancel
+	return ca, nil
+}
+
+func (c *Cluster) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+)
+
+var _ = Describe("RemoteClusters", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		cl     client.Client
+		rc     *RemoteClusters
+		obj    *unstructured.Unstructured
+		secret *corev1.Secret
+	)
+
+	kubeConfig := func(mutate ...func(*clientcmdapi.Config)) []byte {
+		GinkgoHelper()
+		config := clientcmdapi.NewConfig()
+		config.Clusters["spoke"] = &clientcmdapi.Cluster{Server: "https://spoke.example.com:6443"}
+		config.AuthInfos["operator"] = &clientcmdapi.AuthInfo{Token: "secret-token"}
+		config.Contexts["spoke"] = &clientcmdapi.Context{Cluster: "spoke", AuthInfo: "operator"}
+		config.CurrentContext = "spoke"
+		for _, m := range mutate {
+			m(config)
+		}
+		data, err := clientcmd.Write(*config)
+		Expect(err).NotTo(HaveOccurred())
+		return data
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(func() { cancel() })
+
+		obj = testutil.BuildTestCR(gvk)
+		obj.SetUID("test-uid")
+		Expect(unstructured.SetNestedField(obj.Object, map[string]interface{}{"name": "spoke"}, "spec", "kubeConfigSecretRef")).To(Succeed())
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: obj.GetNamespace(), Name: "spoke"},
+			Data:       map[string][]byte{DefaultKubeConfigSecretKey: kubeConfig()},
+		}
+		cl = fake.NewClientBuilder().WithObjects(secret).Build()
+		rc = NewRemoteClusters(cl)
+	})
+
+	It("should return nil for objects without a kubeconfig secret reference", func() {
+		unstructured.RemoveNestedField(obj.Object, "spec", "kubeConfigSecretRef")
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(BeNil())
+		Expect(rc.Lookup(obj)).To(BeNil())
+	})
+
+	It("should create and cache the clients of the referenced cluster", func() {
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).NotTo(BeNil())
+		Expect(c.Secret).To(Equal(client.ObjectKeyFromObject(secret)))
+		Expect(c.RestConfig.Host).To(Equal("https://spoke.example.com:6443"))
+		Expect(c.RestConfig.BearerToken).To(Equal("secret-token"))
+		Expect(c.RESTMapper).NotTo(BeNil())
+		Expect(c.DiscoveryClient).NotTo(BeNil())
+
+		Expect(rc.ClusterFor(ctx, obj, cfg)).To(BeIdenticalTo(c))
+		Expect(rc.Lookup(obj)).To(BeIdenticalTo(c))
+		Expect(rc.ObjectsFor(c.Secret, gvk)).To(ConsistOf(client.ObjectKeyFromObject(obj)))
+	})
+
+	It("should forget objects whose reference was removed", func() {
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		unstructured.RemoveNestedField(obj.Object, "spec", "kubeConfigSecretRef")
+		Expect(rc.ClusterFor(ctx, obj, cfg)).To(BeNil())
+		Expect(rc.Lookup(obj)).To(BeNil())
+		Expect(rc.ObjectsFor(c.Secret, gvk)).To(BeEmpty())
+	})
+
+	It("should recreate the clients and stop the cache when the kubeconfig rotates", func() {
+		go func() {
+			defer GinkgoRecover()
+			Expect(rc.Start(ctx)).To(Succeed())
+		}()
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() error {
+			_, err := c.Cache()
+			return err
+		}).Should(Succeed())
+
+		secret.Data[DefaultKubeConfigSecretKey] = kubeConfig(func(config *clientcmdapi.Config) {
+			config.AuthInfos["operator"].Token = "rotated-token"
+		})
+		Expect(cl.Update(ctx, secret)).To(Succeed())
+
+		rotated, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated).NotTo(BeIdenticalTo(c))
+		Expect(rotated.RestConfig.BearerToken).To(Equal("rotated-token"))
+		Expect(rc.Lookup(obj)).To(BeIdenticalTo(rotated))
+
+		_, err = c.Cache()
+		Expect(err).To(MatchError(ContainSubstring("has been stopped")))
+	})
+
+	It("should stop the cluster when no object references its kubeconfig secret anymore", func() {
+		go func() {
+			defer GinkgoRecover()
+			Expect(rc.Start(ctx)).To(Succeed())
+		}()
+		other := testutil.BuildTestCR(gvk)
+		other.SetName("other")
+		other.SetUID("other-uid")
+		Expect(unstructured.SetNestedField(other.Object, map[string]interface{}{"name": "spoke"}, "spec", "kubeConfigSecretRef")).To(Succeed())
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rc.ClusterFor(ctx, other, cfg)).To(BeIdenticalTo(c))
+		Eventually(func() error {
+			_, err := c.Cache()
+			return err
+		}).Should(Succeed())
+
+		By("keeping the cluster while another object references it")
+		rc.Forget(obj)
+		Expect(rc.Lookup(obj)).To(BeNil())
+		Expect(rc.Lookup(other)).To(BeIdenticalTo(c))
+		_, err = c.Cache()
+		Expect(err).NotTo(HaveOccurred())
+
+		By("stopping the cluster when the last object is forgotten")
+		rc.Forget(other)
+		_, err = c.Cache()
+		Expect(err).To(MatchError(ContainSubstring("has been stopped")))
+		recreated, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recreated).NotTo(BeIdenticalTo(c))
+	})
+
+	It("should stop the cluster of the previous kubeconfig secret when the reference changes", func() {
+		go func() {
+			defer GinkgoRecover()
+			Expect(rc.Start(ctx)).To(Succeed())
+		}()
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() error {
+			_, err := c.Cache()
+			return err
+		}).Should(Succeed())
+
+		moved := secret.DeepCopy()
+		moved.Name = "spoke-2"
+		moved.ResourceVersion = ""
+		Expect(cl.Create(ctx, moved)).To(Succeed())
+		Expect(unstructured.SetNestedField(obj.Object, "spoke-2", "spec", "kubeConfigSecretRef", "name")).To(Succeed())
+		other, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other.Secret).To(Equal(client.ObjectKeyFromObject(moved)))
+		Expect(rc.ObjectsFor(c.Secret, gvk)).To(BeEmpty())
+		_, err = c.Cache()
+		Expect(err).To(MatchError(ContainSubstring("has been stopped")))
+	})
+
+	It("should only be started once", func() {
+		firstCtx, cancelFirst := context.WithCancel(ctx)
+		defer cancelFirst()
+		go func() {
+			defer GinkgoRecover()
+			Expect(rc.Start(firstCtx)).To(Succeed())
+		}()
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() error {
+			_, err := c.Cache()
+			return err
+		}).Should(Succeed())
+
+		secondCtx, cancelSecond := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(rc.Start(secondCtx)).To(Succeed())
+		}()
+		cancelSecond()
+		Eventually(done).Should(BeClosed())
+
+		_, err = c.Cache()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fail to start caches before it is started", func() {
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.Cache()
+		Expect(err).To(MatchError("remote clusters have not been started"))
+	})
+
+	It("should use the key and path of the reference", func() {
+		secret.Data["other"] = secret.Data[DefaultKubeConfigSecretKey]
+		delete(secret.Data, DefaultKubeConfigSecretKey)
+		Expect(cl.Update(ctx, secret)).To(Succeed())
+		unstructured.RemoveNestedField(obj.Object, "spec", "kubeConfigSecretRef")
+		Expect(unstructured.SetNestedField(obj.Object, map[string]interface{}{"name": "spoke", "key": "other"}, "spec", "cluster", "kubeConfig")).To(Succeed())
+
+		rc = NewRemoteClusters(cl, KubeConfigSecretRefPath("spec", "cluster", "kubeConfig"))
+		c, err := rc.ClusterFor(ctx, obj, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.RestConfig.Host).To(Equal("https://spoke.example.com:6443"))
+	})
+
+	DescribeTable("should reject invalid references and kubeconfigs",
+		func(mutate func(obj *unstructured.Unstructured, secret *corev1.Secret), errMsg string) {
+			mutate(obj, secret)
+			Expect(cl.Update(ctx, secret)).To(Succeed())
+			_, err := rc.ClusterFor(ctx, obj, cfg)
+			Expect(err).To(MatchError(ContainSubstring(errMsg)))
+		},
+		Entry("reference without name", func(obj *unstructured.Unstructured, _ *corev1.Secret) {
+			Expect(unstructured.SetNestedField(obj.Object, map[string]interface{}{"key": "kubeconfig"}, "spec", "kubeConfigSecretRef")).To(Succeed())
+		}, "must have a name"),
+		Entry("reference to another namespace", func(obj *unstructured.Unstructured, _ *corev1.Secret) {
+			Expect(unstructured.SetNestedField(obj.Object, "other", "spec", "kubeConfigSecretRef", "namespace")).To(Succeed())
+		}, "must be in namespace"),
+		Entry("cluster-scoped object without namespace", func(obj *unstructured.Unstructured, _ *corev1.Secret) {
+			obj.SetNamespace("")
+		}, "must have a namespace"),
+		Entry("missing key", func(_ *unstructured.Unstructured, secret *corev1.Secret) {
+			secret.Data = map[string][]byte{"other": nil}
+		}, `has no key "kubeconfig"`),
+		Entry("exec plugin", func(_ *unstructured.Unstructured, secret *corev1.Secret) {
+			secret.Data[DefaultKubeConfigSecretKey] = kubeConfig(func(config *clientcmdapi.Config) {
+				config.AuthInfos["operator"].Exec = &clientcmdapi.ExecConfig{Command: "sh", APIVersion: "client.authentication.k8s.io/v1"}
+			})
+		}, "exec credential plugins are not supported"),
+		Entry("auth provider", func(_ *unstructured.Unstructured, secret *corev1.Secret) {
+			secret.Data[DefaultKubeConfigSecretKey] = kubeConfig(func(config *clientcmdapi.Config) {
+				config.AuthInfos["operator"].AuthProvider = &clientcmdapi.AuthProviderConfig{Name: "oidc"}
+			})
+		}, "auth provider plugins are not supported"),
+		Entry("token file", func(_ *unstructured.Unstructured, secret *corev1.Secret) {
+			secret.Data[DefaultKubeConfigSecretKey] = kubeConfig(func(config *clientcmdapi.Config) {
+				config.AuthInfos["operator"].TokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+			})
+		}, "file references are not supported"),
+		Entry("certificate authority file", func(_ *unstructured.Unstructured, secret *corev1.Secret) {
+			secret.Data[DefaultKubeConfigSecretKey] = kubeConfig(func(config *clientcmdapi.Config) {
+				config.Clusters["spoke"].CertificateAuthority = "/etc/ssl/ca.crt"
+			})
+		}, "file references are not supported"),
+	)
+
+	It("should be used by the ActionConfigGetter with ClientClusterMapper", func() {
+		acg, err := NewActionConfigGetter(cfg, nil, ClientClusterMapper(rc.ClusterFor))
+		Expect(err).NotTo(HaveOccurred())
+
+		ac, err := acg.ActionConfigFor(ctx, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isRemote(ac.RESTClientGetter)).To(BeTrue())
+		restConfig, err := ac.RESTClientGetter.ToRESTConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restConfig.Host).To(Equal("https://spoke.example.com:6443"))
+		rm, err := ac.RESTClientGetter.ToRESTMapper()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rm).To(BeIdenticalTo(rc.Lookup(obj).RESTMapper))
+
+		local := testutil.BuildTestCR(gvk)
+		ac, err = acg.ActionConfigFor(ctx, local)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isRemote(ac.RESTClientGetter)).To(BeFalse())
+		restConfig, err = ac.RESTClientGetter.ToRESTConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restConfig.Host).To(Equal(cfg.Host))
+	})
+
+	It("should apply the ClientRestConfigMapper to the rest config of the cluster", func() {
+		Expect(unstructured.SetNestedField(obj.Object, "deployer", "spec", "serviceAccountName")).To(Succeed())
+		acg, err := NewActionConfigGetter(cfg, nil,
+			ClientClusterMapper(rc.ClusterFor),
+			ClientRestConfigMapper(ImpersonateServiceAccount(ImpersonationOpts{})),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		ac, err := acg.ActionConfigFor(ctx, obj)
+		Expect(err).NotTo(HaveOccurred())
+		restConfig, err := ac.RESTClientGetter.ToRESTConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restConfig.Host).To(Equal("https://spoke.example.com:6443"))
+		Expect(restConfig.Impersonate.UserName).To(Equal("system:serviceaccount:" + obj.GetNamespace() + ":deployer"))
+	})
+})
+
+var _ = Describe("restConfigFromKubeConfig", func() {
+	It("should fail for invalid kubeconfigs", func() {
+		_, err := restConfigFromKubeConfig([]byte("{"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should use the current context", func() {
+		config := clientcmdapi.NewConfig()
+		config.Clusters["a"] = &clientcmdapi.Cluster{Server: "https://a.example.com"}
+		config.Clusters["b"] = &clientcmdapi.Cluster{Server: "https://b.example.com"}
+		config.Contexts["a"] = &clientcmdapi.Context{Cluster: "a"}
+		config.Contexts["b"] = &clientcmdapi.Context{Cluster: "b"}
+		config.CurrentContext = "b"
+		data, err := clientcmd.Write(*config)
+		Expect(err).NotTo(HaveOccurred())
+
+		restConfig, err := restConfigFromKubeConfig(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restConfig).To(WithTransform(func(c *rest.Config) string { return c.Host }, Equal("https://b.example.com")))
+	})
+})
@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"helm.sh/helm/v3/pkg/action"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
@@ -40,6 +41,10 @@ type restClientGetter struct {
 	restConfig            *rest.Config
 	restMapper            meta.RESTMapper
 	cachedDiscoveryClient discovery.CachedDiscoveryInterface
+
+	// remote is true if the rest config is for a remote cluster, in which
+	// objects cannot be owned by the custom resource.
+	remote bool
 }
 
 func (c *restClientGetter) ToRESTConfig() (*rest.Config, error) {
@@ -93,3 +98,10 @@ func (c namespaceClientConfig) Namespace() (string, bool, error) {
 func (c namespaceClientConfig) ConfigAccess() clientcmd.ConfigAccess {
 	return nil
 }
+
+// isRemote returns whether rcg was created by an ActionConfigGetter for a
+// remote cluster.
+func isRemote(rcg action.RESTClientGetter) bool {
+	nrcg, ok := rcg.(*namespacedRCG)
+	return ok && nrcg.remote
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crd generates CustomResourceDefinitions for the custom resources
+// of Helm operators from the charts they install.
+package crd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
+)
+
+// Option configures the CustomResourceDefinition generated by Generate.
+type Option func(*generator)
+
+// WithPlural sets the plural resource name of the generated
+// CustomResourceDefinition. By default, it is derived from the kind.
+func WithPlural(plural string) Option {
+	return func(g *generator) {
+		g.plural = plural
+	}
+}
+
+// WithScope sets the scope of the generated CustomResourceDefinition. It
+// defaults to apiextensionsv1.NamespaceScoped.
+func WithScope(scope apiextensionsv1.ResourceScope) Option {
+	return func(g *generator) {
+		g.scope = scope
+	}
+}
+
+// WithShortNames sets the short names of the generated
+// CustomResourceDefinition.
+func WithShortNames(shortNames ...string) Option {
+	return func(g *generator) {
+		g.shortNames = shortNames
+	}
+}
+
+type generator struct {
+	plural     string
+	scope      apiextensionsv1.ResourceScope
+	shortNames []string
+}
+
+// Generate generates a CustomResourceDefinition for the kind of w.
+//
+// The schema of the spec is converted from the chart's values.schema.json
+// with SchemaFromJSONSchema. If the chart has no schema, it is inferred from
+// the chart's default values with SchemaFromValues. The
+// CustomResourceDefinition enables the status subresource and has printer
+// columns for the conditions that the reconciler sets.
+//
+// w.Chart must be set, as it is by watches.Load.
+func Generate(w watches.Watch, opts ...Option) (*apiextensionsv1.CustomResourceDefinition, error) {
+	if w.Chart == nil {
+		return nil, errors.New("watch has no chart")
+	}
+	if w.Group == "" {
+		return nil, fmt.Errorf("cannot generate CustomResourceDefinition for %s without group", w.GroupVersionKind)
+	}
+
+	g := &generator{scope: apiextensionsv1.NamespaceScoped}
+	for _, o := range opts {
+		o(g)
+	}
+	singular := strings.ToLower(w.Kind)
+	if g.plural == "" {
+		g.plural = Pluralize(singular)
+	}
+
+	var (
+		specSchema *apiextensionsv1.JSONSchemaProps
+		err        error
+	)
+	if len(w.Chart.Schema) > 0 {
+		specSchema, err = SchemaFromJSONSchema(w.Chart.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("convert schema of chart %s: %w", w.Chart.Name(), err)
+		}
+	} else {
+		vals, err := chartutil.CoalesceValues(w.Chart, map[string]interface{}{})
+		if err != nil {
+			return nil, fmt.Errorf("coalesce values of chart %s: %w", w.Chart.Name(), err)
+		}
+		specSchema = SchemaFromValues(vals)
+	}
+	if specSchema.Type == "" {
+		specSchema.Type = "object"
+	}
+	if specSchema.Description == "" {
+		specSchema.Description = fmt.Sprintf("Spec contains the values of the %s chart.", w.Chart.Name())
+	}
+
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Description: fmt.Sprintf("%s is the Schema for the %s API.", w.Kind, g.plural),
+		Type:        "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"apiVersion": {Type: "string"},
+			"kind":       {Type: "string"},
+			"metadata":   {Type: "object"},
+			"spec":       *specSchema,
+			"status":     statusSchema(),
+		},
+	}
+	if err := validateStructural(schema); err != nil {
+		return nil, err
+	}
+
+	return &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s.%s", g.plural, w.Group),
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: w.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:       w.Kind,
+				ListKind:   w.Kind + "List",
+				Singular:   singular,
+				Plural:     g.plural,
+				ShortNames: g.shortNames,
+			},
+			Scope: g.scope,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    w.Version,
+				Served:  true,
+				Storage: true,
+				Schema:  &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: schema},
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				},
+				AdditionalPrinterColumns: printerColumns(),
+			}},
+		},
+	}, nil
+}
+
+// Pluralize returns the plural of the lower case kind, following the rules
+// of the English language that most kinds follow.
+func Pluralize(singular string) string {
+	switch {
+	case strings.HasSuffix(singular, "s"), strings.HasSuffix(singular, "x"), strings.HasSuffix(singular, "z"),
+		strings.HasSuffix(singular, "ch"), strings.HasSuffix(singular, "sh"):
+		return singular + "es"
+	case strings.HasSuffix(singular, "y") && len(singular) > 1 && !strings.ContainsAny(singular[len(singular)-2:len(singular)-1], "aeiou"):
+		return singular[:len(singular)-1] + "ies"
+	}
+	return singular + "s"
+}
+
+// statusSchema returns the schema of the status that the reconciler writes.
+// Unknown fields are preserved, so that hooks can add their own fields.
+func statusSchema() apiextensionsv1.JSONSchemaProps {
+	return apiextensionsv1.JSONSchemaProps{
+		Description:            "Status is the status of the Helm release of the custom resource.",
+		Type:                   "object",
+		XPreserveUnknownFields: ptr.To(true),
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"conditions": {
+				Type: "array",
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"status", "type"},
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"type":               {Type: "string"},
+						"status":             {Type: "string"},
+						"reason":             {Type: "string"},
+						"message":            {Type: "string"},
+						"lastTransitionTime": {Type: "string", Format: "date-time"},
+					},
+				}},
+				XListType:    ptr.To("map"),
+				XListMapKeys: []string{"type"},
+			},
+			"deployedRelease": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"name":     {Type: "string"},
+					"manifest": {Type: "string"},
+				},
+			},
+			"custom": {
+				Description:            "Custom holds the fields that are set by hooks.",
+				Type:                   "object",
+				XPreserveUnknownFields: ptr.To(true),
+			},
+		},
+	}
+}
+
+// printerColumns returns the printer columns for the conditions that the
+// reconciler sets.
+func printerColumns() []apiextensionsv1.CustomResourceColumnDefinition {
+	condition := func(conditionType, field string) string {
+		return fmt.Sprintf(`.status.conditions[?(@.type=="%s")].%s`, conditionType, field)
+	}
+	return []apiextensionsv1.CustomResourceColumnDefinition{
+		{Name: "Deployed", Type: "string", JSONPath: condition("Deployed", "status"), Description: "Whether the release is deployed"},
+		{Name: "Reason", Type: "string", JSONPath: condition("Deployed", "reason"), Description: "Reason of the last deployment"},
+		{Name: "Release", Type: "string", JSONPath: ".status.deployedRelease.name", Description: "Name of the deployed release", Priority: 1},
+		{Name: "Failed", Type: "string", JSONPath: condition("ReleaseFailed", "status"), Description: "Whether the last release failed", Priority: 1},
+		{Name: "Irreconcilable", Type: "string", JSONPath: condition("Irreconcilable", "status"), Description: "Whether the custom resource is irreconcilable", Priority: 1},
+		{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+	}
+}
+
+// validateStructural returns an error if schema is not a structural schema.
+func validateStructural(schema *apiextensionsv1.JSONSchemaProps) error {
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internal, nil); err != nil {
+		return fmt.Errorf("convert schema: %w", err)
+	}
+	s, err := structuralschema.NewStructural(internal)
+	if err != nil {
+		return fmt.Errorf("generated schema is not structural: %w", err)
+	}
+	if errs := structuralschema.ValidateStructural(field.NewPath("openAPIV3Schema"), s); len(errs) > 0 {
+		return fmt.Errorf("generated schema is not structural: %w", errs.ToAggregate())
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/crd"
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+	"github.com/operator-framework/helm-operator-plugins/pkg/watches"
+)
+
+var _ = Describe("Generate", func() {
+	var w watches.Watch
+
+	BeforeEach(func() {
+		chrt := testutil.MustLoadChart("../../pkg/internal/testdata/test-chart")
+		w = watches.Watch{
+			GroupVersionKind: schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Nginx"},
+			Chart:            &chrt,
+		}
+	})
+
+	expectValid := func(c *apiextensionsv1.CustomResourceDefinition) {
+		GinkgoHelper()
+		internal := &apiextensions.CustomResourceDefinition{}
+		Expect(apiextensionsv1.Convert_v1_CustomResourceDefinition_To_apiextensions_CustomResourceDefinition(c, internal, nil)).To(Succeed())
+		// The stored versions are set by the API server.
+		internal.Status.StoredVersions = []string{c.Spec.Versions[0].Name}
+		Expect(validation.ValidateCustomResourceDefinition(context.Background(), internal)).To(BeEmpty())
+	}
+
+	It("should generate a valid CRD from the chart's values", func() {
+		c, err := crd.Generate(w)
+		Expect(err).ToNot(HaveOccurred())
+		expectValid(c)
+
+		Expect(c.Name).To(Equal("nginxes.example.com"))
+		Expect(c.Spec.Names).To(Equal(apiextensionsv1.CustomResourceDefinitionNames{
+			Kind:     "Nginx",
+			ListKind: "NginxList",
+			Singular: "nginx",
+			Plural:   "nginxes",
+		}))
+		Expect(c.Spec.Scope).To(Equal(apiextensionsv1.NamespaceScoped))
+		Expect(c.Spec.Versions).To(HaveLen(1))
+
+		v := c.Spec.Versions[0]
+		Expect(v.Name).To(Equal("v1alpha1"))
+		Expect(v.Served).To(BeTrue())
+		Expect(v.Storage).To(BeTrue())
+		Expect(v.Subresources.Status).ToNot(BeNil())
+		Expect(v.AdditionalPrinterColumns).To(ContainElement(HaveField("JSONPath", `.status.conditions[?(@.type=="Deployed")].status`)))
+
+		spec := v.Schema.OpenAPIV3Schema.Properties["spec"]
+		Expect(spec.Type).To(Equal("object"))
+		Expect(spec.Properties["replicaCount"].Type).To(Equal("integer"))
+		Expect(spec.Properties["image"].Properties["repository"].Type).To(Equal("string"))
+		Expect(v.Schema.OpenAPIV3Schema.Properties).To(HaveKey("status"))
+	})
+
+	It("should convert the chart's values schema", func() {
+		w.Chart.Schema = []byte(`{
+  "type": "object",
+  "required": ["replicaCount"],
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1, "description": "Number of replicas"}
+  },
+  "additionalProperties": false
+}`)
+		c, err := crd.Generate(w, crd.WithPlural("nginxen"), crd.WithScope(apiextensionsv1.ClusterScoped), crd.WithShortNames("ngx"))
+		Expect(err).ToNot(HaveOccurred())
+		expectValid(c)
+
+		Expect(c.Name).To(Equal("nginxen.example.com"))
+		Expect(c.Spec.Scope).To(Equal(apiextensionsv1.ClusterScoped))
+		Expect(c.Spec.Names.ShortNames).To(Equal([]string{"ngx"}))
+
+		spec := c.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+		Expect(spec.Required).To(Equal([]string{"replicaCount"}))
+		Expect(spec.XPreserveUnknownFields).To(BeNil())
+		Expect(spec.Properties).To(HaveLen(1))
+		Expect(*spec.Properties["replicaCount"].Minimum).To(Equal(1.0))
+	})
+
+	It("should fail for a watch without group", func() {
+		w.Group = ""
+		_, err := crd.Generate(w)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail for a watch without chart", func() {
+		w.Chart = nil
+		_, err := crd.Generate(w)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should include the defaults of dependencies in the inferred schema", func() {
+		dep := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "dep", Version: "0.1.0", APIVersion: chart.APIVersionV2},
+			Values:   map[string]interface{}{"enabled": true},
+		}
+		w.Chart.AddDependency(dep)
+		w.Chart.Metadata.Dependencies = append(w.Chart.Metadata.Dependencies, &chart.Dependency{Name: "dep", Version: "0.1.0"})
+
+		c, err := crd.Generate(w)
+		Expect(err).ToNot(HaveOccurred())
+		spec := c.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+		Expect(spec.Properties["dep"].Properties["enabled"].Type).To(Equal("boolean"))
+	})
+})
+
+var _ = DescribeTable("Pluralize",
+	func(singular, plural string) {
+		Expect(crd.Pluralize(singular)).To(Equal(plural))
+	},
+	Entry(nil, "nginx", "nginxes"),
+	Entry(nil, "policy", "policies"),
+	Entry(nil, "gateway", "gateways"),
+	Entry(nil, "ingress", "ingresses"),
+	Entry(nil, "mesh", "meshes"),
+	Entry(nil, "app", "apps"),
+)
@@ -0,0 +1,347 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+)
+
+// SchemaFromJSONSchema converts the JSON Schema of a chart's values, i.e. the
+// contents of its values.schema.json, into a structural OpenAPI v3 schema
+// that can be used in a CustomResourceDefinition.
+//
+// The conversion supports local references and the keywords that have an
+// equivalent in structural schemas. Objects that do not forbid additional
+// properties preserve unknown fields, so that the API server does not prune
+// values that the chart accepts. Keywords that are not allowed in
+// structural schemas, e.g. allOf, anyOf, oneOf, not and uniqueItems, are
+// dropped; Helm still validates them when the release is rendered. Defaults
+// are dropped, too, since Helm does not apply them either.
+func SchemaFromJSONSchema(data []byte) (*apiextensionsv1.JSONSchemaProps, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	c := &converter{root: root, resolving: sets.New[string]()}
+	props, err := c.convert(root, "#")
+	if err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// SchemaFromValues infers a structural OpenAPI v3 schema from a chart's
+// default values. The type of each value is inferred from its default; all
+// objects preserve unknown fields, and all values below the root are
+// nullable, so that values can be removed as with `helm --set key=null`.
+func SchemaFromValues(values map[string]interface{}) *apiextensionsv1.JSONSchemaProps {
+	props := inferSchema(values)
+	props.Nullable = false
+	return &props
+}
+
+type converter struct {
+	root      interface{}
+	resolving sets.Set[string]
+}
+
+func (c *converter) convert(s interface{}, path string) (apiextensionsv1.JSONSchemaProps, error) {
+	m, ok := s.(map[string]interface{})
+	if !ok {
+		// Boolean schemas and anything else we do not understand accept any
+		// value.
+		return preserveUnknown(), nil
+	}
+
+	if ref, ok := m["$ref"].(string); ok {
+		if c.resolving.Has(ref) {
+			// Recursive schemas cannot be expressed in a CRD.
+			return preserveUnknown(), nil
+		}
+		resolved, err := c.resolve(ref)
+		if err != nil {
+			return apiextensionsv1.JSONSchemaProps{}, fmt.Errorf("%s: %w", path, err)
+		}
+		c.resolving.Insert(ref)
+		defer c.resolving.Delete(ref)
+		props, err := c.convert(resolved, ref)
+		if err != nil {
+			return props, err
+		}
+		if desc, ok := m["description"].(string); ok {
+			props.Description = desc
+		}
+		return props, nil
+	}
+
+	var props apiextensionsv1.JSONSchemaProps
+	props.Description, _ = m["description"].(string)
+	props.Title, _ = m["title"].(string)
+
+	types, nullable := schemaTypes(m)
+	props.Nullable = nullable
+	switch {
+	case len(types) == 1:
+		props.Type = types[0]
+	case len(types) == 2 && sets.New(types...).Equal(sets.New("integer", "string")):
+		props.XIntOrString = true
+		return props, nil
+	default:
+		props.XPreserveUnknownFields = ptr.To(true)
+		return props, nil
+	}
+
+	if enum, ok := m["enum"].([]interface{}); ok {
+		for _, v := range enum {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return props, fmt.Errorf("%s: %w", path, err)
+			}
+			props.Enum = append(props.Enum, apiextensionsv1.JSON{Raw: raw})
+		}
+	} else if v, ok := m["const"]; ok {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return props, fmt.Errorf("%s: %w", path, err)
+		}
+		props.Enum = []apiextensionsv1.JSON{{Raw: raw}}
+	}
+
+	switch props.Type {
+	case "object":
+		if err := c.convertObject(m, path, &props); err != nil {
+			return props, err
+		}
+	case "array":
+		if items, ok := m["items"].(map[string]interface{}); ok {
+			itemProps, err := c.convert(items, path+"/items")
+			if err != nil {
+				return props, err
+			}
+			props.Items = &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &itemProps}
+		} else {
+			itemProps := preserveUnknown()
+			props.Items = &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &itemProps}
+		}
+		props.MinItems = int64Keyword(m, "minItems")
+		props.MaxItems = int64Keyword(m, "maxItems")
+	case "string":
+		props.MinLength = int64Keyword(m, "minLength")
+		props.MaxLength = int64Keyword(m, "maxLength")
+		props.Pattern, _ = m["pattern"].(string)
+		props.Format, _ = m["format"].(string)
+	case "integer", "number":
+		props.Minimum = float64Keyword(m, "minimum")
+		props.Maximum = float64Keyword(m, "maximum")
+		props.MultipleOf = float64Keyword(m, "multipleOf")
+		// Draft 4 uses booleans for exclusive bounds, later drafts numbers.
+		switch v := m["exclusiveMinimum"].(type) {
+		case bool:
+			props.ExclusiveMinimum = v && props.Minimum != nil
+		case float64:
+			props.Minimum, props.ExclusiveMinimum = &v, true
+		}
+		switch v := m["exclusiveMaximum"].(type) {
+		case bool:
+			props.ExclusiveMaximum = v && props.Maximum != nil
+		case float64:
+			props.Maximum, props.ExclusiveMaximum = &v, true
+		}
+	}
+	return props, nil
+}
+
+func (c *converter) convertObject(m map[string]interface{}, path string, props *apiextensionsv1.JSONSchemaProps) error {
+	if properties, ok := m["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+		props.Properties = make(map[string]apiextensionsv1.JSONSchemaProps, len(properties))
+		for name, p := range properties {
+			propProps, err := c.convert(p, path+"/properties/"+name)
+			if err != nil {
+				return err
+			}
+			props.Properties[name] = propProps
+		}
+	}
+	if required, ok := m["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := props.Properties[name]; ok {
+					props.Required = append(props.Required, name)
+				}
+			}
+		}
+		sort.Strings(props.Required)
+	}
+	props.MinProperties = int64Keyword(m, "minProperties")
+	props.MaxProperties = int64Keyword(m, "maxProperties")
+
+	switch additional := m["additionalProperties"].(type) {
+	case bool:
+		if additional {
+			props.XPreserveUnknownFields = ptr.To(true)
+		}
+	case map[string]interface{}:
+		if len(props.Properties) > 0 {
+			// Structural schemas do not allow properties and
+			// additionalProperties at the same time.
+			props.XPreserveUnknownFields = ptr.To(true)
+			break
+		}
+		additionalProps, err := c.convert(additional, path+"/additionalProperties")
+		if err != nil {
+			return err
+		}
+		props.AdditionalProperties = &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: &additionalProps}
+	default:
+		props.XPreserveUnknownFields = ptr.To(true)
+	}
+	return nil
+}
+
+// resolve resolves a local reference, i.e. a JSON pointer into the root
+// schema like "#/definitions/image".
+func (c *converter) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported reference %q: only local references are supported", ref)
+	}
+	pointer, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %q: %w", ref, err)
+	}
+	cur := c.root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+		if cur, ok = m[token]; !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+	}
+	return cur, nil
+}
+
+// schemaTypes returns the non-null types of the schema m, and whether it
+// allows null. If m has no type, it is inferred from its keywords.
+func schemaTypes(m map[string]interface{}) ([]string, bool) {
+	var types []string
+	switch t := m["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		switch {
+		case m["properties"] != nil || m["additionalProperties"] != nil:
+			types = []string{"object"}
+		case m["items"] != nil:
+			types = []string{"array"}
+		}
+	}
+
+	nullable := false
+	nonNull := types[:0]
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		nonNull = append(nonNull, t)
+	}
+	return nonNull, nullable
+}
+
+func inferSchema(v interface{}) apiextensionsv1.JSONSchemaProps {
+	props := apiextensionsv1.JSONSchemaProps{Nullable: true}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		props.Type = "object"
+		props.XPreserveUnknownFields = ptr.To(true)
+		if len(v) > 0 {
+			props.Properties = make(map[string]apiextensionsv1.JSONSchemaProps, len(v))
+			for k, vv := range v {
+				props.Properties[k] = inferSchema(vv)
+			}
+		}
+	case []interface{}:
+		props.Type = "array"
+		items := preserveUnknown()
+		if len(v) > 0 {
+			items = inferSchema(v[0])
+			for _, vv := range v[1:] {
+				if inferSchema(vv).Type != items.Type {
+					items = preserveUnknown()
+					break
+				}
+			}
+			if items.Type == "object" {
+				// Items of the same type can still have different fields.
+				items.Properties = nil
+			}
+			items.Nullable = false
+		}
+		props.Items = &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &items}
+	case string:
+		props.Type = "string"
+	case bool:
+		props.Type = "boolean"
+	case int, int32, int64:
+		props.Type = "integer"
+	case float64:
+		props.Type = "number"
+		if v == math.Trunc(v) {
+			props.Type = "integer"
+		}
+	default:
+		props = preserveUnknown()
+		props.Nullable = true
+	}
+	return props
+}
+
+func preserveUnknown() apiextensionsv1.JSONSchemaProps {
+	return apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptr.To(true)}
+}
+
+func int64Keyword(m map[string]interface{}, key string) *int64 {
+	if v, ok := m[key].(float64); ok {
+		return ptr.To(int64(v))
+	}
+	return nil
+}
+
+func float64Keyword(m map[string]interface{}, key string) *float64 {
+	if v, ok := m[key].(float64); ok {
+		return &v
+	}
+	return nil
+}
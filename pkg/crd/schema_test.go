@@ -0,0 +1,159 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/crd"
+)
+
+var _ = Describe("SchemaFromJSONSchema", func() {
+	convert := func(s string) *apiextensionsv1.JSONSchemaProps {
+		GinkgoHelper()
+		props, err := crd.SchemaFromJSONSchema([]byte(s))
+		Expect(err).ToNot(HaveOccurred())
+		return props
+	}
+
+	It("should convert types and validations", func() {
+		props := convert(`{
+  "type": "object",
+  "properties": {
+    "name": {"type": "string", "minLength": 1, "maxLength": 63, "pattern": "^[a-z]+$", "title": "Name"},
+    "port": {"type": "integer", "minimum": 1, "exclusiveMaximum": 65536},
+    "ratio": {"type": "number", "maximum": 1, "exclusiveMaximum": true},
+    "mode": {"type": "string", "enum": ["a", "b"]},
+    "fixed": {"const": 42, "type": "integer"},
+    "tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "uniqueItems": true},
+    "any": {}
+  }
+}`)
+		Expect(props.Type).To(Equal("object"))
+		Expect(props.XPreserveUnknownFields).To(Equal(ptr.To(true)))
+
+		Expect(props.Properties["name"]).To(Equal(apiextensionsv1.JSONSchemaProps{
+			Type: "string", Title: "Name", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](63), Pattern: "^[a-z]+$",
+		}))
+		Expect(props.Properties["port"]).To(Equal(apiextensionsv1.JSONSchemaProps{
+			Type: "integer", Minimum: ptr.To(1.0), Maximum: ptr.To(65536.0), ExclusiveMaximum: true,
+		}))
+		Expect(props.Properties["ratio"]).To(Equal(apiextensionsv1.JSONSchemaProps{
+			Type: "number", Maximum: ptr.To(1.0), ExclusiveMaximum: true,
+		}))
+		Expect(props.Properties["mode"].Enum).To(Equal([]apiextensionsv1.JSON{{Raw: []byte(`"a"`)}, {Raw: []byte(`"b"`)}}))
+		Expect(props.Properties["fixed"].Enum).To(Equal([]apiextensionsv1.JSON{{Raw: []byte(`42`)}}))
+		Expect(props.Properties["tags"]).To(Equal(apiextensionsv1.JSONSchemaProps{
+			Type:     "array",
+			Items:    &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+			MinItems: ptr.To[int64](1),
+		}))
+		Expect(props.Properties["any"]).To(Equal(apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptr.To(true)}))
+	})
+
+	It("should convert nullable and multiple types", func() {
+		props := convert(`{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "optional": {"type": ["string", "null"]},
+    "quantity": {"type": ["integer", "string"]},
+    "mixed": {"type": ["boolean", "string"]}
+  }
+}`)
+		Expect(props.XPreserveUnknownFields).To(BeNil())
+		Expect(props.Properties["optional"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "string", Nullable: true}))
+		Expect(props.Properties["quantity"]).To(Equal(apiextensionsv1.JSONSchemaProps{XIntOrString: true}))
+		Expect(props.Properties["mixed"]).To(Equal(apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptr.To(true)}))
+	})
+
+	It("should resolve local references and stop at recursive ones", func() {
+		props := convert(`{
+  "type": "object",
+  "additionalProperties": false,
+  "definitions": {
+    "image": {"type": "object", "additionalProperties": false, "properties": {"tag": {"type": "string"}}},
+    "node": {"type": "object", "additionalProperties": false, "properties": {"child": {"$ref": "#/definitions/node"}}}
+  },
+  "properties": {
+    "image": {"$ref": "#/definitions/image", "description": "The image"},
+    "tree": {"$ref": "#/definitions/node"}
+  }
+}`)
+		Expect(props.Properties["image"]).To(Equal(apiextensionsv1.JSONSchemaProps{
+			Type:        "object",
+			Description: "The image",
+			Properties:  map[string]apiextensionsv1.JSONSchemaProps{"tag": {Type: "string"}},
+		}))
+		Expect(props.Properties["tree"].Properties["child"]).To(Equal(apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptr.To(true)}))
+	})
+
+	It("should convert maps with additional properties", func() {
+		props := convert(`{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "mixed": {"type": "object", "properties": {"a": {"type": "string"}}, "additionalProperties": {"type": "string"}}
+  }
+}`)
+		Expect(props.Properties["labels"]).To(Equal(apiextensionsv1.JSONSchemaProps{
+			Type:                 "object",
+			AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+		}))
+		Expect(props.Properties["mixed"].AdditionalProperties).To(BeNil())
+		Expect(props.Properties["mixed"].XPreserveUnknownFields).To(Equal(ptr.To(true)))
+	})
+
+	It("should fail for unresolvable references", func() {
+		_, err := crd.SchemaFromJSONSchema([]byte(`{"properties": {"a": {"$ref": "#/definitions/missing"}}}`))
+		Expect(err).To(MatchError(ContainSubstring("unresolvable reference")))
+		_, err = crd.SchemaFromJSONSchema([]byte(`{"properties": {"a": {"$ref": "https://example.com/schema.json"}}}`))
+		Expect(err).To(MatchError(ContainSubstring("only local references")))
+	})
+})
+
+var _ = Describe("SchemaFromValues", func() {
+	It("should infer types from the default values", func() {
+		props := crd.SchemaFromValues(map[string]interface{}{
+			"replicaCount": float64(1),
+			"ratio":        0.5,
+			"name":         "test",
+			"enabled":      true,
+			"unset":        nil,
+			"ports":        []interface{}{float64(80), float64(443)},
+			"mixed":        []interface{}{"a", float64(1)},
+			"empty":        []interface{}{},
+			"resources":    map[string]interface{}{},
+		})
+
+		Expect(props.Type).To(Equal("object"))
+		Expect(props.Nullable).To(BeFalse())
+		Expect(props.XPreserveUnknownFields).To(Equal(ptr.To(true)))
+
+		Expect(props.Properties["replicaCount"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "integer", Nullable: true}))
+		Expect(props.Properties["ratio"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "number", Nullable: true}))
+		Expect(props.Properties["name"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "string", Nullable: true}))
+		Expect(props.Properties["enabled"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "boolean", Nullable: true}))
+		Expect(props.Properties["unset"]).To(Equal(apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptr.To(true), Nullable: true}))
+		Expect(props.Properties["ports"].Items.Schema).To(Equal(&apiextensionsv1.JSONSchemaProps{Type: "integer"}))
+		Expect(props.Properties["mixed"].Items.Schema).To(Equal(&apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptr.To(true)}))
+		Expect(props.Properties["empty"].Items.Schema).To(Equal(&apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptr.To(true)}))
+		Expect(props.Properties["resources"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "object", Nullable: true, XPreserveUnknownFields: ptr.To(true)}))
+	})
+})
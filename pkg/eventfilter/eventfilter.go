@@ -0,0 +1,234 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventfilter configures which events of the dependent resources of a
+// release trigger a reconciliation of its custom resource.
+package eventfilter
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Config configures which events of dependent resources trigger a
+// reconciliation. By default, creations are ignored, since dependent resources
+// are only created by reconciliations, deletions always trigger one, and
+// updates trigger one if they change anything but the status.
+type Config struct {
+	// IgnoreFields are fields of dependent resources whose changes do not
+	// trigger a reconciliation, e.g. annotations that other controllers set.
+	IgnoreFields []IgnoreField `json:"ignoreFields,omitempty"`
+	// ReconcileOnCreate configures that creations of dependent resources
+	// trigger a reconciliation as well.
+	ReconcileOnCreate bool `json:"reconcileOnCreate,omitempty"`
+	// UpdateDebounce delays reconciliations triggered by updates of
+	// dependent resources, so that all updates of the dependent resources of
+	// a custom resource within this period trigger a single reconciliation.
+	UpdateDebounce metav1.Duration `json:"updateDebounce,omitempty"`
+}
+
+// IgnoreField is a field of dependent resources whose changes do not trigger a
+// reconciliation.
+type IgnoreField struct {
+	// Kind restricts the field to dependent resources of this kind, which may
+	// be qualified with its group, e.g. "Deployment.apps". The field is
+	// ignored for all kinds if it is empty.
+	Kind string `json:"kind,omitempty"`
+	// Path is the dot-separated path of the field, e.g. "spec.replicas".
+	// Segments in square brackets may contain dots, e.g.
+	// "metadata.annotations[deployment.kubernetes.io/revision]". Segments
+	// may contain wildcards, e.g. "metadata.annotations[*.example.com/*]",
+	// and match the indices of lists, e.g.
+	// "spec.template.spec.containers.*.image". Items of lists themselves
+	// cannot be ignored.
+	Path string `json:"path"`
+}
+
+// Filter is a compiled Config. A nil Filter applies the default rules.
+type Filter struct {
+	fields            []compiledField
+	reconcileOnCreate bool
+	updateDebounce    time.Duration
+}
+
+type compiledField struct {
+	gk       schema.GroupKind
+	anyGroup bool
+	path     []string
+}
+
+// New compiles the config. It returns the errors of all invalid fields.
+func New(c Config) (*Filter, error) {
+	if c.UpdateDebounce.Duration < 0 {
+		return nil, fmt.Errorf("invalid updateDebounce %s: must not be negative", c.UpdateDebounce.Duration)
+	}
+	f := &Filter{
+		reconcileOnCreate: c.ReconcileOnCreate,
+		updateDebounce:    c.UpdateDebounce.Duration,
+	}
+	var errs []error
+	for i, field := range c.IgnoreFields {
+		cf, err := compile(field)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid ignored field [%d]: %w", i, err))
+			continue
+		}
+		f.fields = append(f.fields, cf)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	_, err := New(c)
+	return err
+}
+
+func compile(field IgnoreField) (compiledField, error) {
+	var c compiledField
+	if field.Kind != "" {
+		c.gk = schema.ParseGroupKind(field.Kind)
+		c.anyGroup = !strings.Contains(field.Kind, ".")
+		if c.gk.Kind == "" {
+			return c, fmt.Errorf("invalid kind %q", field.Kind)
+		}
+	}
+	p, err := parsePath(field.Path)
+	if err != nil {
+		return c, err
+	}
+	c.path = p
+	return c, nil
+}
+
+func parsePath(p string) ([]string, error) {
+	var (
+		segments []string
+		rest     = p
+	)
+	for rest != "" {
+		var seg string
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unterminated bracket", p)
+			}
+			seg, rest = rest[1:end], rest[end+1:]
+			if rest != "" && !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "[") {
+				return nil, fmt.Errorf("invalid path %q", p)
+			}
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			seg, rest = rest[:end], rest[end:]
+		}
+		if seg == "" {
+			return nil, fmt.Errorf("invalid path %q", p)
+		}
+		if _, err := path.Match(seg, ""); err != nil {
+			return nil, fmt.Errorf("invalid path %q: invalid pattern %q", p, seg)
+		}
+		segments = append(segments, seg)
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("invalid path %q", p)
+			}
+		}
+	}
+	if len(segments) == 0 {
+		return nil, errors.New("path must not be empty")
+	}
+	return segments, nil
+}
+
+// ReconcileOnCreate returns whether creations of dependent resources trigger a
+// reconciliation.
+func (f *Filter) ReconcileOnCreate() bool {
+	return f != nil && f.reconcileOnCreate
+}
+
+// UpdateDebounce returns the period by which reconciliations triggered by
+// updates of dependent resources are delayed.
+func (f *Filter) UpdateDebounce() time.Duration {
+	if f == nil {
+		return 0
+	}
+	return f.updateDebounce
+}
+
+// RemoveIgnoredFields removes the ignored fields of the given kind from obj,
+// which is the content of an unstructured object.
+func (f *Filter) RemoveIgnoredFields(gk schema.GroupKind, obj map[string]interface{}) {
+	if f == nil {
+		return
+	}
+	for _, field := range f.fields {
+		if field.matches(gk) {
+			remove(obj, field.path)
+		}
+	}
+}
+
+func (c compiledField) matches(gk schema.GroupKind) bool {
+	switch {
+	case c.gk.Kind == "":
+		return true
+	case c.anyGroup:
+		return c.gk.Kind == gk.Kind
+	default:
+		return c.gk == gk
+	}
+}
+
+func remove(obj interface{}, p []string) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range o {
+			if ok, _ := path.Match(p[0], k); !ok {
+				continue
+			}
+			if len(p) == 1 {
+				delete(o, k)
+			} else {
+				remove(v, p[1:])
+			}
+		}
+	case []interface{}:
+		// Items of lists cannot be removed without shifting the indices of
+		// the following items, so only fields of items are removed.
+		if len(p) == 1 {
+			return
+		}
+		for i, v := range o {
+			if ok, _ := path.Match(p[0], strconv.Itoa(i)); ok {
+				remove(v, p[1:])
+			}
+		}
+	}
+}
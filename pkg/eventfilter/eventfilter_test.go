@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventfilter_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
+)
+
+var _ = Describe("Filter", func() {
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+
+	newObject := func() map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "test",
+				"annotations": map[string]interface{}{
+					"deployment.kubernetes.io/revision": "2",
+					"a.example.com/seen":                "true",
+					"b.example.com/seen":                "true",
+					"keep":                              "true",
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "a", "image": "a:1"},
+							map[string]interface{}{"name": "b", "image": "b:1"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("should apply the default rules if it is nil", func() {
+		var f *eventfilter.Filter
+		Expect(f.ReconcileOnCreate()).To(BeFalse())
+		Expect(f.UpdateDebounce()).To(BeZero())
+		obj := newObject()
+		f.RemoveIgnoredFields(deployment, obj)
+		Expect(obj).To(Equal(newObject()))
+	})
+
+	It("should return the configured rules", func() {
+		f, err := eventfilter.New(eventfilter.Config{ReconcileOnCreate: true, UpdateDebounce: metav1.Duration{Duration: time.Second}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.ReconcileOnCreate()).To(BeTrue())
+		Expect(f.UpdateDebounce()).To(Equal(time.Second))
+	})
+
+	It("should remove ignored fields", func() {
+		f, err := eventfilter.New(eventfilter.Config{IgnoreFields: []eventfilter.IgnoreField{
+			{Path: "metadata.annotations[deployment.kubernetes.io/revision]"},
+			{Path: "metadata.annotations[*.example.com/*]"},
+			{Path: "spec.template.spec.containers.*.image"},
+			{Path: "spec.nonexistent.field"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		obj := newObject()
+		f.RemoveIgnoredFields(deployment, obj)
+		Expect(obj).To(Equal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":        "test",
+				"annotations": map[string]interface{}{"keep": "true"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "a"},
+							map[string]interface{}{"name": "b"},
+						},
+					},
+				},
+			},
+		}))
+	})
+
+	It("should only remove fields of matching kinds", func() {
+		f, err := eventfilter.New(eventfilter.Config{IgnoreFields: []eventfilter.IgnoreField{
+			{Kind: "Deployment.apps", Path: "spec.replicas"},
+			{Kind: "Deployment", Path: "metadata.name"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		obj := newObject()
+		f.RemoveIgnoredFields(schema.GroupKind{Group: "example.com", Kind: "Deployment"}, obj)
+		Expect(obj).To(HaveKeyWithValue("spec", HaveKey("replicas")))
+		Expect(obj).To(HaveKeyWithValue("metadata", Not(HaveKey("name"))))
+
+		obj = newObject()
+		f.RemoveIgnoredFields(deployment, obj)
+		Expect(obj).To(HaveKeyWithValue("spec", Not(HaveKey("replicas"))))
+
+		obj = newObject()
+		f.RemoveIgnoredFields(schema.GroupKind{Kind: "ConfigMap"}, obj)
+		Expect(obj).To(Equal(newObject()))
+	})
+
+	It("should report all invalid fields", func() {
+		_, err := eventfilter.New(eventfilter.Config{IgnoreFields: []eventfilter.IgnoreField{
+			{Path: "spec.replicas"},
+			{Path: ""},
+			{Path: "spec..replicas"},
+			{Path: "metadata.annotations[example.com"},
+			{Path: "metadata.annotations[example.com]x"},
+			{Path: "spec.[a-"},
+			{Kind: ".apps", Path: "spec.replicas"},
+		}})
+		Expect(err).To(HaveOccurred())
+		for i := 1; i <= 6; i++ {
+			Expect(err.Error()).To(ContainSubstring("invalid ignored field [%d]", i))
+		}
+		Expect(err.Error()).NotTo(ContainSubstring("[0]"))
+	})
+
+	It("should reject negative debounce periods", func() {
+		Expect(eventfilter.Config{UpdateDebounce: metav1.Duration{Duration: -time.Second}}.Validate()).To(HaveOccurred())
+	})
+})
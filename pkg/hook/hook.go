@@ -17,9 +17,14 @@ limitations under the License.
 package hook
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/go-logr/logr"
 	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -42,3 +47,179 @@ type PostHookFunc func(*unstructured.Unstructured, release.Release, logr.Logger)
 func (f PostHookFunc) Exec(obj *unstructured.Unstructured, rel release.Release, log logr.Logger) error {
 	return f(obj, rel, log)
 }
+
+// FailurePolicy defines how the reconciler handles the failure of a hook.
+type FailurePolicy string
+
+const (
+	// FailurePolicyAdvisory logs the error of a hook and continues the
+	// reconciliation.
+	FailurePolicyAdvisory FailurePolicy = "Advisory"
+	// FailurePolicyFatal stops the reconciliation when a hook fails, marks
+	// the custom resource Irreconcilable and retries with backoff.
+	FailurePolicyFatal FailurePolicy = "Fatal"
+)
+
+// PreHookResult is the result of a PreHookV2.
+type PreHookResult struct {
+	// Values replace the values passed to subsequent hooks and to Helm if
+	// they are not nil.
+	Values chartutil.Values
+	// RequeueAfter requeues the custom resource after the duration if it
+	// is positive and shorter than the reconcile period.
+	RequeueAfter time.Duration
+}
+
+// PreHookV2 is a PreHook that receives a context, can modify the values
+// passed to Helm and can abort the reconciliation by returning an
+// AbortError. Its other errors are handled according to its FailurePolicy.
+type PreHookV2 interface {
+	Exec(context.Context, *unstructured.Unstructured, chartutil.Values, logr.Logger) (PreHookResult, error)
+	FailurePolicy() FailurePolicy
+}
+
+// PreHookV2Func is an advisory PreHookV2.
+type PreHookV2Func func(context.Context, *unstructured.Unstructured, chartutil.Values, logr.Logger) (PreHookResult, error)
+
+func (f PreHookV2Func) Exec(ctx context.Context, obj *unstructured.Unstructured, vals chartutil.Values, log logr.Logger) (PreHookResult, error) {
+	return f(ctx, obj, vals, log)
+}
+
+func (f PreHookV2Func) FailurePolicy() FailurePolicy {
+	return FailurePolicyAdvisory
+}
+
+// FatalPreHook returns a PreHookV2 whose errors stop the reconciliation.
+func FatalPreHook(f PreHookV2Func) PreHookV2 {
+	return fatalPreHook{f}
+}
+
+type fatalPreHook struct {
+	PreHookV2Func
+}
+
+func (fatalPreHook) FailurePolicy() FailurePolicy {
+	return FailurePolicyFatal
+}
+
+// AdaptPreHook returns an advisory PreHookV2 that runs the given PreHook
+// and does not modify the values.
+func AdaptPreHook(h PreHook) PreHookV2 {
+	return PreHookV2Func(func(_ context.Context, obj *unstructured.Unstructured, vals chartutil.Values, log logr.Logger) (PreHookResult, error) {
+		return PreHookResult{}, h.Exec(obj, vals, log)
+	})
+}
+
+// AbortError aborts the reconciliation regardless of the FailurePolicy of the
+// hook that returns it. The custom resource is marked Irreconcilable with the
+// reason and message of the error, and requeued after RequeueAfter or the
+// reconcile period instead of with backoff.
+type AbortError struct {
+	// Reason is the reason of the Irreconcilable condition, which defaults
+	// to "PreHookAborted".
+	Reason string
+	// Message is the message of the Irreconcilable condition.
+	Message string
+	// RequeueAfter is the duration after which the custom resource is
+	// requeued if it is positive.
+	RequeueAfter time.Duration
+}
+
+// Abort returns an AbortError with the given reason and message.
+func Abort(reason, message string) *AbortError {
+	return &AbortError{Reason: reason, Message: message}
+}
+
+func (e *AbortError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("reconciliation aborted: %s", e.Message)
+	}
+	return fmt.Sprintf("reconciliation aborted: %s: %s", e.Reason, e.Message)
+}
+
+// UninstallHookResult is the result of an UninstallHook.
+type UninstallHookResult struct {
+	// RequeueAfter delays the uninstall, or the removal of the finalizer
+	// after it, and requeues the custom resource after the duration if it
+	// is positive.
+	RequeueAfter time.Duration
+	// Message explains why the hook delays the uninstall. It is shown in
+	// the UninstallBlocked condition.
+	Message string
+}
+
+// UninstallHook runs before or after the release of a custom resource that is
+// being deleted is uninstalled. The release is nil if it is not found, e.g.
+// because a post-uninstall hook delayed the removal of the finalizer.
+type UninstallHook interface {
+	Exec(context.Context, *unstructured.Unstructured, *release.Release, logr.Logger) (UninstallHookResult, error)
+}
+
+type UninstallHookFunc func(context.Context, *unstructured.Unstructured, *release.Release, logr.Logger) (UninstallHookResult, error)
+
+func (f UninstallHookFunc) Exec(ctx context.Context, obj *unstructured.Unstructured, rel *release.Release, log logr.Logger) (UninstallHookResult, error) {
+	return f(ctx, obj, rel, log)
+}
+
+// PostHookV2 is a PostHook that receives a context, e.g. to write the status
+// of the custom resource with the StatusWriter in it.
+type PostHookV2 interface {
+	Exec(context.Context, *unstructured.Unstructured, release.Release, logr.Logger) error
+}
+
+type PostHookV2Func func(context.Context, *unstructured.Unstructured, release.Release, logr.Logger) error
+
+func (f PostHookV2Func) Exec(ctx context.Context, obj *unstructured.Unstructured, rel release.Release, log logr.Logger) error {
+	return f(ctx, obj, rel, log)
+}
+
+// AdaptPostHook returns a PostHookV2 that runs the given PostHook.
+func AdaptPostHook(h PostHook) PostHookV2 {
+	return PostHookV2Func(func(_ context.Context, obj *unstructured.Unstructured, rel release.Release, log logr.Logger) error {
+		return h.Exec(obj, rel, log)
+	})
+}
+
+// StatusWriter records changes to the status of a custom resource, which the
+// reconciler applies together with its own changes at the end of the
+// reconciliation.
+type StatusWriter interface {
+	// SetCondition sets a condition of the given type. It fails for the
+	// types of the conditions that are set by the reconciler, e.g.
+	// "Deployed".
+	SetCondition(conditionType string, status corev1.ConditionStatus, reason, message string) error
+	// SetField sets the field at the given path under status.custom to a
+	// copy of the value, which must be serializable to JSON.
+	SetField(value interface{}, fields ...string) error
+	// RemoveField removes the field at the given path under status.custom.
+	RemoveField(fields ...string)
+}
+
+type statusWriterKey struct{}
+
+// WithStatusWriter returns a copy of ctx that carries the StatusWriter.
+func WithStatusWriter(ctx context.Context, w StatusWriter) context.Context {
+	return context.WithValue(ctx, statusWriterKey{}, w)
+}
+
+// StatusWriterFrom returns the StatusWriter in ctx, which the reconciler
+// provides to instances of PreHookV2, PostHookV2 and UninstallHook. It returns a StatusWriter
+// that discards all changes if there is none.
+func StatusWriterFrom(ctx context.Context) StatusWriter {
+	if w, ok := ctx.Value(statusWriterKey{}).(StatusWriter); ok {
+		return w
+	}
+	return discardStatusWriter{}
+}
+
+type discardStatusWriter struct{}
+
+func (discardStatusWriter) SetCondition(string, corev1.ConditionStatus, string, string) error {
+	return nil
+}
+
+func (discardStatusWriter) SetField(interface{}, ...string) error {
+	return nil
+}
+
+func (discardStatusWriter) RemoveField(...string) {}
@@ -17,11 +17,16 @@ limitations under the License.
 package hook_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	. "github.com/operator-framework/helm-operator-plugins/pkg/hook"
@@ -50,4 +55,89 @@ var _ = Describe("Hook", func() {
 			Expect(called).To(BeTrue())
 		})
 	})
+	var _ = Describe("PreHookV2Func", func() {
+		It("should implement an advisory PreHookV2", func() {
+			var h PreHookV2 = PreHookV2Func(func(_ context.Context, _ *unstructured.Unstructured, vals chartutil.Values, _ logr.Logger) (PreHookResult, error) {
+				return PreHookResult{Values: chartutil.Values{"a": vals["a"]}}, nil
+			})
+			res, err := h.Exec(context.Background(), nil, chartutil.Values{"a": 1, "b": 2}, logr.Discard())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Values).To(Equal(chartutil.Values{"a": 1}))
+			Expect(h.FailurePolicy()).To(Equal(FailurePolicyAdvisory))
+		})
+	})
+	var _ = Describe("FatalPreHook", func() {
+		It("should return a fatal PreHookV2", func() {
+			h := FatalPreHook(func(context.Context, *unstructured.Unstructured, chartutil.Values, logr.Logger) (PreHookResult, error) {
+				return PreHookResult{}, errors.New("foobar")
+			})
+			_, err := h.Exec(context.Background(), nil, nil, logr.Discard())
+			Expect(err).To(MatchError("foobar"))
+			Expect(h.FailurePolicy()).To(Equal(FailurePolicyFatal))
+		})
+	})
+	var _ = Describe("AdaptPreHook", func() {
+		It("should run the PreHook as an advisory PreHookV2", func() {
+			h := AdaptPreHook(PreHookFunc(func(*unstructured.Unstructured, chartutil.Values, logr.Logger) error {
+				return errors.New("foobar")
+			}))
+			res, err := h.Exec(context.Background(), nil, chartutil.Values{"a": 1}, logr.Discard())
+			Expect(err).To(MatchError("foobar"))
+			Expect(res).To(Equal(PreHookResult{}))
+			Expect(h.FailurePolicy()).To(Equal(FailurePolicyAdvisory))
+		})
+	})
+	var _ = Describe("UninstallHookFunc", func() {
+		It("should implement the UninstallHook interface", func() {
+			var h UninstallHook = UninstallHookFunc(func(context.Context, *unstructured.Unstructured, *release.Release, logr.Logger) (UninstallHookResult, error) {
+				return UninstallHookResult{Message: "called"}, nil
+			})
+			res, err := h.Exec(context.Background(), nil, nil, logr.Discard())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Message).To(Equal("called"))
+		})
+	})
+	var _ = Describe("AdaptPostHook", func() {
+		It("should run the PostHook as a PostHookV2", func() {
+			called := false
+			h := AdaptPostHook(PostHookFunc(func(*unstructured.Unstructured, release.Release, logr.Logger) error {
+				called = true
+				return nil
+			}))
+			Expect(h.Exec(context.Background(), nil, release.Release{}, logr.Discard())).To(Succeed())
+			Expect(called).To(BeTrue())
+		})
+	})
+	var _ = Describe("StatusWriterFrom", func() {
+		It("should return the StatusWriter in the context", func() {
+			w := &fakeStatusWriter{}
+			Expect(StatusWriterFrom(WithStatusWriter(context.Background(), w))).To(BeIdenticalTo(w))
+		})
+		It("should return a discarding StatusWriter without one", func() {
+			w := StatusWriterFrom(context.Background())
+			Expect(w).NotTo(BeNil())
+			Expect(w.SetField("value", "field")).To(Succeed())
+		})
+	})
+	var _ = Describe("AbortError", func() {
+		It("should be matched through wrapping", func() {
+			err := fmt.Errorf("check failed: %w", Abort("LicenseMissing", "no license"))
+			var abort *AbortError
+			Expect(errors.As(err, &abort)).To(BeTrue())
+			Expect(abort.Reason).To(Equal("LicenseMissing"))
+			Expect(err).To(MatchError("check failed: reconciliation aborted: LicenseMissing: no license"))
+		})
+	})
 })
+
+type fakeStatusWriter struct{}
+
+func (*fakeStatusWriter) SetCondition(string, corev1.ConditionStatus, string, string) error {
+	return nil
+}
+
+func (*fakeStatusWriter) SetField(interface{}, ...string) error {
+	return nil
+}
+
+func (*fakeStatusWriter) RemoveField(...string) {}
@@ -22,23 +22,34 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	crtpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
 )
 
 var log = logf.Log.WithName("predicate")
 
 type GenerationChangedPredicate = crtpredicate.GenerationChangedPredicate
 
-// DependentPredicateFuncs returns functions defined for filtering events
-func DependentPredicateFuncs() crtpredicate.TypedFuncs[*unstructured.Unstructured] {
+// DependentPredicateFuncs returns functions defined for filtering events. The
+// filter may ignore changes of fields and enable creation events; a nil filter
+// applies the default rules.
+func DependentPredicateFuncs(f *eventfilter.Filter) crtpredicate.TypedFuncs[*unstructured.Unstructured] {
 	dependentPredicate := crtpredicate.TypedFuncs[*unstructured.Unstructured]{
 		// We don't need to reconcile dependent resource creation events
 		// because dependent resources are only ever created during
-		// reconciliation. Another reconcile would be redundant.
+		// reconciliation. Another reconcile would be redundant, unless the
+		// filter asks for it.
 		CreateFunc: func(e event.TypedCreateEvent[*unstructured.Unstructured]) bool {
 			o := e.Object
+			if f.ReconcileOnCreate() {
+				log.V(1).Info("Reconciling due to dependent resource creation", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
+				return true
+			}
 			log.V(1).Info("Skipping reconciliation for dependent resource creation", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
 			return false
 		},
@@ -60,8 +71,8 @@ func DependentPredicateFuncs() crtpredicate.TypedFuncs[*unstructured.Unstructure
 
 		// Reconcile when a dependent resource is updated, so that it can
 		// be patched back to the resource managed by the CR, if
-		// necessary. Ignore updates that only change the status and
-		// resourceVersion.
+		// necessary. Ignore updates that only change the status,
+		// resourceVersion and the fields that the filter ignores.
 		UpdateFunc: func(e event.TypedUpdateEvent[*unstructured.Unstructured]) bool {
 			old := e.ObjectOld.DeepCopy()
 			updated := e.ObjectNew.DeepCopy()
@@ -74,6 +85,10 @@ func DependentPredicateFuncs() crtpredicate.TypedFuncs[*unstructured.Unstructure
 			removeStatusManagedField(old)
 			removeStatusManagedField(updated)
 
+			gk := updated.GroupVersionKind().GroupKind()
+			f.RemoveIgnoredFields(gk, old.Object)
+			f.RemoveIgnoredFields(gk, updated.Object)
+
 			if reflect.DeepEqual(old.Object, updated.Object) {
 				return false
 			}
@@ -85,6 +100,100 @@ func DependentPredicateFuncs() crtpredicate.TypedFuncs[*unstructured.Unstructure
 	return dependentPredicate
 }
 
+// DependentStatusPredicateFuncs returns predicates that only match updates of
+// dependent resources that change their status, e.g. to map values of the
+// status into the status of the owner.
+func DependentStatusPredicateFuncs() crtpredicate.TypedFuncs[*unstructured.Unstructured] {
+	return crtpredicate.TypedFuncs[*unstructured.Unstructured]{
+		CreateFunc:  func(event.TypedCreateEvent[*unstructured.Unstructured]) bool { return false },
+		DeleteFunc:  func(event.TypedDeleteEvent[*unstructured.Unstructured]) bool { return false },
+		GenericFunc: func(event.TypedGenericEvent[*unstructured.Unstructured]) bool { return false },
+		UpdateFunc: func(e event.TypedUpdateEvent[*unstructured.Unstructured]) bool {
+			if reflect.DeepEqual(e.ObjectOld.Object["status"], e.ObjectNew.Object["status"]) {
+				return false
+			}
+			o := e.ObjectNew
+			log.V(1).Info("Reconciling due to dependent resource status update", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
+			return true
+		},
+	}
+}
+
+// DependentMetadataPredicateFuncs returns functions defined for filtering
+// events of dependent resources that are watched with metadata-only
+// informers. Since only the metadata of these objects is known, updates of
+// objects with a generation are detected by changes of their metadata, and
+// every update of an object without a generation, e.g. a ConfigMap, is
+// considered a change. Fields that the filter ignores are only ignored in the
+// metadata of objects with a generation.
+func DependentMetadataPredicateFuncs(f *eventfilter.Filter) crtpredicate.TypedFuncs[*metav1.PartialObjectMetadata] {
+	return crtpredicate.TypedFuncs[*metav1.PartialObjectMetadata]{
+		CreateFunc: func(e event.TypedCreateEvent[*metav1.PartialObjectMetadata]) bool {
+			o := e.Object
+			if f.ReconcileOnCreate() {
+				log.V(1).Info("Reconciling due to dependent resource creation", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
+				return true
+			}
+			log.V(1).Info("Skipping reconciliation for dependent resource creation", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
+			return false
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*metav1.PartialObjectMetadata]) bool {
+			o := e.Object
+			log.V(1).Info("Reconciling due to dependent resource deletion", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
+			return true
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*metav1.PartialObjectMetadata]) bool {
+			o := e.Object
+			log.V(1).Info("Skipping reconcile due to generic event", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*metav1.PartialObjectMetadata]) bool {
+			old := e.ObjectOld.ObjectMeta.DeepCopy()
+			updated := e.ObjectNew.ObjectMeta.DeepCopy()
+
+			if updated.Generation == 0 {
+				if old.ResourceVersion == updated.ResourceVersion {
+					return false
+				}
+			} else {
+				old.ResourceVersion = ""
+				updated.ResourceVersion = ""
+				old.ManagedFields = slices.DeleteFunc(old.ManagedFields, isStatusSubresource)
+				updated.ManagedFields = slices.DeleteFunc(updated.ManagedFields, isStatusSubresource)
+				gk := e.ObjectNew.GroupVersionKind().GroupKind()
+				oldMeta, err := metadataContent(f, gk, old)
+				if err != nil {
+					log.Error(err, "Failed to convert metadata of dependent resource")
+					return true
+				}
+				updatedMeta, err := metadataContent(f, gk, updated)
+				if err != nil {
+					log.Error(err, "Failed to convert metadata of dependent resource")
+					return true
+				}
+				if reflect.DeepEqual(oldMeta, updatedMeta) {
+					return false
+				}
+			}
+			o := e.ObjectNew
+			log.V(1).Info("Reconciling due to dependent resource update", "name", o.GetName(), "namespace", o.GetNamespace(), "apiVersion", o.GroupVersionKind().GroupVersion(), "kind", o.GroupVersionKind().Kind)
+			return true
+		},
+	}
+}
+
+// metadataContent returns the content of an unstructured object with the given
+// metadata, without the fields that the filter ignores.
+func metadataContent(f *eventfilter.Filter, gk schema.GroupKind, m *metav1.ObjectMeta) (map[string]interface{}, error) {
+	meta, err := runtime.DefaultUnstructuredConverter.ToUnstructured(m)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{"metadata": meta}
+	f.RemoveIgnoredFields(gk, obj)
+	return obj, nil
+}
+
 func removeStatusManagedField(obj *unstructured.Unstructured) {
 	obj.SetManagedFields(slices.DeleteFunc(obj.GetManagedFields(), isStatusSubresource))
 }
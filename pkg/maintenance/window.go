@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance parses recurring maintenance windows, which restrict
+// when releases may be upgraded.
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Window is a recurring maintenance window that starts at the times of a cron
+// schedule and lasts for a duration.
+type Window struct {
+	spec     string
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// Parse parses a maintenance window of the form "<schedule> <duration>", where
+// schedule is a cron expression with five fields or a descriptor like @daily,
+// optionally prefixed with CRON_TZ=<time zone>, and duration is a Go
+// duration, e.g. "0 2 * * SAT 4h" or "CRON_TZ=Europe/Berlin 30 22 * * MON-FRI 90m".
+// Schedules without a time zone use the time zone of the operator.
+func Parse(s string) (Window, error) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexAny(s, " \t")
+	if i < 0 {
+		return Window{}, fmt.Errorf("invalid maintenance window %q: expected a schedule and a duration", s)
+	}
+	duration, err := time.ParseDuration(s[i+1:])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid maintenance window %q: %w", s, err)
+	}
+	if duration <= 0 {
+		return Window{}, fmt.Errorf("invalid maintenance window %q: duration must be positive", s)
+	}
+	spec := strings.TrimSpace(s[:i])
+	if strings.HasPrefix(spec, "@every") {
+		return Window{}, fmt.Errorf("invalid maintenance window %q: @every is relative to the start of the operator", s)
+	}
+	schedule, err := parser.Parse(spec)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid maintenance window %q: %w", s, err)
+	}
+	return Window{spec: s, schedule: schedule, duration: duration}, nil
+}
+
+// ParseList parses maintenance windows that are separated by semicolons, e.g.
+// "0 2 * * SAT 4h; 0 2 * * SUN 2h".
+func ParseList(s string) ([]Window, error) {
+	var windows []Window
+	var errs []error
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		w, err := Parse(part)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		windows = append(windows, w)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// String returns the maintenance window in the form accepted by Parse.
+func (w Window) String() string {
+	return w.spec
+}
+
+// Open returns whether the window is open at t and, if it is, when it closes.
+// Otherwise it returns when the window opens next.
+func (w Window) Open(t time.Time) (bool, time.Time) {
+	// The first start after t-duration is either the start of the window
+	// that is open at t or the start of the next window.
+	start := w.schedule.Next(t.Add(-w.duration))
+	if start.IsZero() {
+		return false, time.Time{}
+	}
+	if !start.After(t) {
+		return true, start.Add(w.duration)
+	}
+	return false, start
+}
+
+// Allowed returns whether t is inside any of the windows. If it is not, it
+// also returns the start of the next window, which is zero if no window
+// opens again. Any time is allowed if there are no windows.
+func Allowed(windows []Window, t time.Time) (bool, time.Time) {
+	if len(windows) == 0 {
+		return true, time.Time{}
+	}
+	var next time.Time
+	for _, w := range windows {
+		open, at := w.Open(t)
+		if open {
+			return true, time.Time{}
+		}
+		if !at.IsZero() && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	return false, next
+}
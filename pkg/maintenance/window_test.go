@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/maintenance"
+)
+
+func mustParse(s string) maintenance.Window {
+	w, err := maintenance.Parse(s)
+	Expect(err).ToNot(HaveOccurred())
+	return w
+}
+
+// saturday is a Saturday in UTC.
+var saturday = time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+
+var _ = Describe("Parse", func() {
+	It("parses a schedule and a duration", func() {
+		w := mustParse("  0 2 * * SAT 4h ")
+		Expect(w.String()).To(Equal("0 2 * * SAT 4h"))
+	})
+
+	It("parses descriptors and time zones", func() {
+		mustParse("@daily 1h")
+		mustParse("CRON_TZ=Europe/Berlin 30 22 * * MON-FRI 90m")
+	})
+
+	DescribeTable("rejects invalid windows",
+		func(s, message string) {
+			_, err := maintenance.Parse(s)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("without a duration", "0 2 * * SAT", "invalid maintenance window"),
+		Entry("without a schedule", "4h", "expected a schedule and a duration"),
+		Entry("with an invalid duration", "0 2 * * SAT 4", "missing unit in duration"),
+		Entry("with a negative duration", "0 2 * * SAT -4h", "duration must be positive"),
+		Entry("with an invalid schedule", "0 25 * * SAT 4h", "end of range (25) above maximum (23)"),
+		Entry("with a schedule with seconds", "0 0 2 * * SAT 4h", "expected exactly 5 fields"),
+		Entry("with @every", "@every 1h 30m", "@every is relative"),
+	)
+})
+
+var _ = Describe("ParseList", func() {
+	It("parses windows separated by semicolons", func() {
+		windows, err := maintenance.ParseList("0 2 * * SAT 4h; 0 2 * * SUN 2h;")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(windows).To(HaveLen(2))
+		Expect(windows[1].String()).To(Equal("0 2 * * SUN 2h"))
+	})
+
+	It("returns nothing for an empty list", func() {
+		windows, err := maintenance.ParseList(" ")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(windows).To(BeEmpty())
+	})
+
+	It("reports all invalid windows", func() {
+		_, err := maintenance.ParseList("0 2 * * SAT; 0 2 * * SUN 2h; 0 25 * * * 1h")
+		Expect(err).To(MatchError(ContainSubstring(`"0 2 * * SAT"`)))
+		Expect(err).To(MatchError(ContainSubstring(`"0 25 * * * 1h"`)))
+	})
+})
+
+var _ = Describe("Window", func() {
+	w := mustParse("CRON_TZ=UTC 0 2 * * SAT 4h")
+
+	It("is closed before it starts", func() {
+		open, next := w.Open(saturday.Add(time.Hour))
+		Expect(open).To(BeFalse())
+		Expect(next).To(BeTemporally("==", saturday.Add(2*time.Hour)))
+	})
+
+	It("is open from its start until it ends", func() {
+		open, end := w.Open(saturday.Add(2 * time.Hour))
+		Expect(open).To(BeTrue())
+		Expect(end).To(BeTemporally("==", saturday.Add(6*time.Hour)))
+
+		open, _ = w.Open(saturday.Add(6*time.Hour - time.Second))
+		Expect(open).To(BeTrue())
+	})
+
+	It("is closed after it ends until it starts again", func() {
+		open, next := w.Open(saturday.Add(6 * time.Hour))
+		Expect(open).To(BeFalse())
+		Expect(next).To(BeTemporally("==", saturday.Add(7*24*time.Hour+2*time.Hour)))
+	})
+
+	It("never opens for impossible schedules", func() {
+		open, next := mustParse("0 0 30 2 * 1h").Open(saturday)
+		Expect(open).To(BeFalse())
+		Expect(next).To(BeZero())
+	})
+})
+
+var _ = Describe("Allowed", func() {
+	windows := []maintenance.Window{
+		mustParse("CRON_TZ=UTC 0 2 * * SAT 4h"),
+		mustParse("CRON_TZ=UTC 0 22 * * FRI 1h"),
+	}
+
+	It("allows any time without windows", func() {
+		allowed, next := maintenance.Allowed(nil, saturday)
+		Expect(allowed).To(BeTrue())
+		Expect(next).To(BeZero())
+	})
+
+	It("allows times inside any window", func() {
+		allowed, _ := maintenance.Allowed(windows, saturday.Add(3*time.Hour))
+		Expect(allowed).To(BeTrue())
+		allowed, _ = maintenance.Allowed(windows, saturday.Add(-90*time.Minute))
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("returns the earliest next window outside of the windows", func() {
+		allowed, next := maintenance.Allowed(windows, saturday.Add(12*time.Hour))
+		Expect(allowed).To(BeFalse())
+		Expect(next).To(BeTemporally("==", saturday.Add(6*24*time.Hour+22*time.Hour)))
+	})
+})
@@ -0,0 +1,689 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/operator-framework/helm-operator-plugins/internal/metrics"
+)
+
+var registerMetricsOnce sync.Once
+
+// NewDynamicNamespaceCacheFunc returns a function that creates caches which
+// watch the namespaces that match selector and are not excluded. Namespaces
+// are watched and unwatched as they gain or lose matching labels, without
+// restarting the manager, and the set of watched namespaces is logged and
+// exposed by the helm_operator_watched_namespace metric.
+//
+// Namespaced objects are cached by a cache per watched namespace, and
+// cluster-scoped objects by a cache for the whole cluster. Event handlers,
+// indexers and indexes that are added to the cache are applied to the caches
+// of namespaces that are watched later. When a namespace is no longer
+// watched, its cache is stopped without delivering delete events for its
+// objects.
+//
+// The caches need to list and watch namespaces.
+func NewDynamicNamespaceCacheFunc(selector labels.Selector, exclude []string, log logr.Logger) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		if err := defaultCacheOptions(config, &opts); err != nil {
+			return nil, err
+		}
+		registerMetricsOnce.Do(func() {
+			metrics.RegisterWatchedNamespaces(crmetrics.Registry)
+		})
+
+		clusterOpts := opts
+		clusterOpts.DefaultNamespaces = nil
+		clusterCache, err := cache.New(config, clusterOpts)
+		if err != nil {
+			return nil, fmt.Errorf("create cluster-scoped cache: %w", err)
+		}
+		newNamespaceCache := func(namespace string) (cache.Cache, error) {
+			namespaceOpts := opts
+			namespaceOpts.DefaultNamespaces = map[string]cache.Config{namespace: {}}
+			return cache.New(config, namespaceOpts)
+		}
+		return newDynamicNamespaceCache(clusterCache, newNamespaceCache, opts, selector, exclude, log)
+	}
+}
+
+func defaultCacheOptions(config *rest.Config, opts *cache.Options) error {
+	if opts.Scheme == nil {
+		opts.Scheme = scheme.Scheme
+	}
+	if opts.HTTPClient == nil {
+		httpClient, err := rest.HTTPClientFor(config)
+		if err != nil {
+			return fmt.Errorf("create http client: %w", err)
+		}
+		opts.HTTPClient = httpClient
+	}
+	if opts.Mapper == nil {
+		mapper, err := apiutil.NewDynamicRESTMapper(config, opts.HTTPClient)
+		if err != nil {
+			return fmt.Errorf("create rest mapper: %w", err)
+		}
+		opts.Mapper = mapper
+	}
+	return nil
+}
+
+type dynamicNamespaceCache struct {
+	clusterCache      cache.Cache
+	newNamespaceCache func(namespace string) (cache.Cache, error)
+	scheme            *runtime.Scheme
+	mapper            apimeta.RESTMapper
+	selector          labels.Selector
+	exclude           sets.Set[string]
+	log               logr.Logger
+
+	namespaceRegistration toolscache.ResourceEventHandlerRegistration
+
+	mu         sync.RWMutex
+	ctx        context.Context
+	namespaces map[string]*namespaceCache
+	informers  map[string]*dynamicInformer
+	indexes    []fieldIndex
+}
+
+type namespaceCache struct {
+	cache.Cache
+	cancel context.CancelFunc
+}
+
+type fieldIndex struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+var _ cache.Cache = &dynamicNamespaceCache{}
+
+func newDynamicNamespaceCache(clusterCache cache.Cache, newNamespaceCache func(string) (cache.Cache, error), opts cache.Options, selector labels.Selector, exclude []string, log logr.Logger) (*dynamicNamespaceCache, error) {
+	c := &dynamicNamespaceCache{
+		clusterCache:      clusterCache,
+		newNamespaceCache: newNamespaceCache,
+		scheme:            opts.Scheme,
+		mapper:            opts.Mapper,
+		selector:          selector,
+		exclude:           sets.New(exclude...),
+		log:               log,
+		namespaces:        map[string]*namespaceCache{},
+		informers:         map[string]*dynamicInformer{},
+	}
+
+	// The namespace informer is registered before the cluster-scoped cache is
+	// started, so that it is started and waited for with the cache.
+	namespaceInformer, err := clusterCache.GetInformer(context.Background(), namespaceMetadata(), cache.BlockUntilSynced(false))
+	if err != nil {
+		return nil, fmt.Errorf("get namespace informer: %w", err)
+	}
+	c.namespaceRegistration, err = namespaceInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.syncNamespace(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.syncNamespace(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if ns, ok := obj.(client.Object); ok {
+				c.removeNamespace(ns.GetName())
+			}
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("add namespace event handler: %w", err)
+	}
+	return c, nil
+}
+
+func namespaceMetadata() *metav1.PartialObjectMetadata {
+	ns := &metav1.PartialObjectMetadata{}
+	ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	return ns
+}
+
+// watches returns whether the namespace should be watched.
+func (c *dynamicNamespaceCache) watches(ns client.Object) bool {
+	return !c.exclude.Has(ns.GetName()) && c.selector.Matches(labels.Set(ns.GetLabels()))
+}
+
+func (c *dynamicNamespaceCache) syncNamespace(obj interface{}) {
+	ns, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	if c.watches(ns) {
+		c.addNamespace(ns.GetName())
+	} else {
+		c.removeNamespace(ns.GetName())
+	}
+}
+
+func (c *dynamicNamespaceCache) addNamespace(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.namespaces[namespace]; ok || c.ctx == nil {
+		return
+	}
+	log := c.log.WithValues("namespace", namespace)
+
+	nc, err := c.newNamespaceCache(namespace)
+	if err != nil {
+		log.Error(err, "failed to create cache for namespace")
+		return
+	}
+	if err := c.prepareNamespaceCache(nc, namespace); err != nil {
+		for _, i := range c.informers {
+			i.removeNamespace(namespace)
+		}
+		log.Error(err, "failed to prepare cache for namespace")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.namespaces[namespace] = &namespaceCache{Cache: nc, cancel: cancel}
+	go func() {
+		if err := nc.Start(ctx); err != nil {
+			log.Error(err, "failed to start cache for namespace")
+		}
+	}()
+	log.Info("started watching namespace", "watchedNamespaces", len(c.namespaces))
+	metrics.SetWatchedNamespaces([]string{namespace}, nil, len(c.namespaces))
+}
+
+// prepareNamespaceCache adds the indexes, informers, indexers and event
+// handlers of the cache to the cache of a namespace before it is started.
+func (c *dynamicNamespaceCache) prepareNamespaceCache(nc cache.Cache, namespace string) error {
+	ctx := context.Background()
+	for _, index := range c.indexes {
+		if err := nc.IndexField(ctx, index.obj, index.field, index.extractValue); err != nil {
+			return fmt.Errorf("index field %q: %w", index.field, err)
+		}
+	}
+	for _, i := range c.informers {
+		if err := i.addNamespace(ctx, nc, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *dynamicNamespaceCache) removeNamespace(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	nc, ok := c.namespaces[namespace]
+	if !ok {
+		return
+	}
+	nc.cancel()
+	delete(c.namespaces, namespace)
+	for _, i := range c.informers {
+		i.removeNamespace(namespace)
+	}
+	c.log.Info("stopped watching namespace", "namespace", namespace, "watchedNamespaces", len(c.namespaces))
+	metrics.SetWatchedNamespaces(nil, []string{namespace}, len(c.namespaces))
+}
+
+// WatchedNamespaces returns the sorted names of the watched namespaces.
+func (c *dynamicNamespaceCache) WatchedNamespaces() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	namespaces := make([]string, 0, len(c.namespaces))
+	for ns := range c.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func (c *dynamicNamespaceCache) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.ctx != nil {
+		c.mu.Unlock()
+		return errors.New("dynamic namespace cache has already been started")
+	}
+	c.ctx = ctx
+	c.mu.Unlock()
+
+	c.log.Info("watching namespaces matching label selector", "selector", c.selector.String(), "excluded", sets.List(c.exclude))
+	err := c.clusterCache.Start(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := make([]string, 0, len(c.namespaces))
+	for ns, nc := range c.namespaces {
+		nc.cancel()
+		removed = append(removed, ns)
+	}
+	c.namespaces = map[string]*namespaceCache{}
+	metrics.SetWatchedNamespaces(nil, removed, 0)
+	if err != nil {
+		return fmt.Errorf("failed to start cluster-scoped cache: %w", err)
+	}
+	return nil
+}
+
+func (c *dynamicNamespaceCache) WaitForCacheSync(ctx context.Context) bool {
+	if !c.clusterCache.WaitForCacheSync(ctx) {
+		return false
+	}
+	// Once the namespace event handler has seen all existing namespaces, the
+	// caches of the namespaces to watch have been created.
+	if !toolscache.WaitForCacheSync(ctx.Done(), c.namespaceRegistration.HasSynced) {
+		return false
+	}
+
+	c.mu.RLock()
+	caches := make([]cache.Cache, 0, len(c.namespaces))
+	for _, nc := range c.namespaces {
+		caches = append(caches, nc)
+	}
+	c.mu.RUnlock()
+
+	synced := true
+	for _, nc := range caches {
+		if !nc.WaitForCacheSync(ctx) {
+			synced = false
+		}
+	}
+	return synced
+}
+
+func (c *dynamicNamespaceCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	isNamespaced, err := apiutil.IsObjectNamespaced(obj, c.scheme, c.mapper)
+	if err != nil {
+		return nil, err
+	}
+	if !isNamespaced {
+		return c.clusterCache.GetInformer(ctx, obj, opts...)
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%T/%s", obj, gvk)
+	return c.getInformer(ctx, key, func(ctx context.Context, nc cache.Cache) (cache.Informer, error) {
+		return nc.GetInformer(ctx, obj, cache.BlockUntilSynced(false))
+	}, opts)
+}
+
+func (c *dynamicNamespaceCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	isNamespaced, err := apiutil.IsGVKNamespaced(gvk, c.mapper)
+	if err != nil {
+		return nil, err
+	}
+	if !isNamespaced {
+		return c.clusterCache.GetInformerForKind(ctx, gvk, opts...)
+	}
+	key := fmt.Sprintf("kind/%s", gvk)
+	return c.getInformer(ctx, key, func(ctx context.Context, nc cache.Cache) (cache.Informer, error) {
+		return nc.GetInformerForKind(ctx, gvk, cache.BlockUntilSynced(false))
+	}, opts)
+}
+
+func (c *dynamicNamespaceCache) getInformer(ctx context.Context, key string, get func(context.Context, cache.Cache) (cache.Informer, error), opts []cache.InformerGetOption) (cache.Informer, error) {
+	var getOpts cache.InformerGetOptions
+	for _, opt := range opts {
+		opt(&getOpts)
+	}
+
+	c.mu.Lock()
+	i, ok := c.informers[key]
+	if !ok {
+		i = &dynamicInformer{
+			cache:     c,
+			get:       get,
+			informers: map[string]cache.Informer{},
+		}
+		for ns, nc := range c.namespaces {
+			if err := i.addNamespace(ctx, nc, ns); err != nil {
+				c.mu.Unlock()
+				return nil, err
+			}
+		}
+		c.informers[key] = i
+	}
+	started := c.ctx != nil
+	c.mu.Unlock()
+
+	if started && (getOpts.BlockUntilSynced == nil || *getOpts.BlockUntilSynced) {
+		if !toolscache.WaitForCacheSync(ctx.Done(), i.HasSynced) {
+			return nil, fmt.Errorf("failed waiting for informer to sync: %w", ctx.Err())
+		}
+	}
+	return i, nil
+}
+
+func (c *dynamicNamespaceCache) RemoveInformer(ctx context.Context, obj client.Object) error {
+	isNamespaced, err := apiutil.IsObjectNamespaced(obj, c.scheme, c.mapper)
+	if err != nil {
+		return err
+	}
+	if !isNamespaced {
+		return c.clusterCache.RemoveInformer(ctx, obj)
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.informers, fmt.Sprintf("%T/%s", obj, gvk))
+	for _, nc := range c.namespaces {
+		if err := nc.RemoveInformer(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *dynamicNamespaceCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	isNamespaced, err := apiutil.IsObjectNamespaced(obj, c.scheme, c.mapper)
+	if err != nil {
+		return err
+	}
+	if !isNamespaced {
+		return c.clusterCache.IndexField(ctx, obj, field, extractValue)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, nc := range c.namespaces {
+		if err := nc.IndexField(ctx, obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	c.indexes = append(c.indexes, fieldIndex{obj: obj, field: field, extractValue: extractValue})
+	return nil
+}
+
+// Get returns a NotFound error for objects of namespaces that are not watched.
+func (c *dynamicNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	isNamespaced, err := apiutil.IsObjectNamespaced(obj, c.scheme, c.mapper)
+	if err != nil {
+		return err
+	}
+	if !isNamespaced {
+		return c.clusterCache.Get(ctx, key, obj, opts...)
+	}
+
+	c.mu.RLock()
+	nc, ok := c.namespaces[key.Namespace]
+	c.mu.RUnlock()
+	if !ok {
+		gvk, err := apiutil.GVKForObject(obj, c.scheme)
+		if err != nil {
+			return err
+		}
+		mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return err
+		}
+		return apierrors.NewNotFound(mapping.Resource.GroupResource(), key.Name)
+	}
+	return nc.Get(ctx, key, obj, opts...)
+}
+
+// List lists the objects of all watched namespaces if no namespace is given,
+// and no objects if the given namespace is not watched.
+func (c *dynamicNamespaceCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.Continue != "" {
+		return errors.New("continue list option is not supported by the cache")
+	}
+
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	isNamespaced, err := apiutil.IsGVKNamespaced(gvk, c.mapper)
+	if err != nil {
+		return err
+	}
+	if !isNamespaced {
+		return c.clusterCache.List(ctx, list, opts...)
+	}
+
+	if listOpts.Namespace != metav1.NamespaceAll {
+		c.mu.RLock()
+		nc, ok := c.namespaces[listOpts.Namespace]
+		c.mu.RUnlock()
+		if !ok {
+			// Namespaces that are not watched hold no objects.
+			return apimeta.SetList(list, nil)
+		}
+		return nc.List(ctx, list, opts...)
+	}
+
+	c.mu.RLock()
+	caches := make([]cache.Cache, 0, len(c.namespaces))
+	for _, nc := range c.namespaces {
+		caches = append(caches, nc)
+	}
+	c.mu.RUnlock()
+
+	var (
+		allItems        []runtime.Object
+		resourceVersion string
+		limitSet        = listOpts.Limit > 0
+	)
+	for _, nc := range caches {
+		listObj := list.DeepCopyObject().(client.ObjectList)
+		if err := nc.List(ctx, listObj, &listOpts); err != nil {
+			return err
+		}
+		items, err := apimeta.ExtractList(listObj)
+		if err != nil {
+			return err
+		}
+		allItems = append(allItems, items...)
+		resourceVersion = listObj.GetResourceVersion()
+		if limitSet {
+			listOpts.Limit -= int64(len(items))
+			if listOpts.Limit <= 0 {
+				break
+			}
+		}
+	}
+	list.SetResourceVersion(resourceVersion)
+	if err := apimeta.SetList(list, allItems); err != nil {
+		return err
+	}
+	list.SetContinue("continue-not-supported")
+	return nil
+}
+
+// dynamicInformer is the informer of a namespaced kind across the watched
+// namespaces. It remembers its event handlers and indexers, so that they are
+// added to the informers of namespaces that are watched later.
+type dynamicInformer struct {
+	cache *dynamicNamespaceCache
+	get   func(context.Context, cache.Cache) (cache.Informer, error)
+
+	// The fields below are guarded by the mutex of the cache.
+	informers     map[string]cache.Informer
+	registrations []*dynamicRegistration
+	indexers      toolscache.Indexers
+}
+
+type dynamicRegistration struct {
+	informer *dynamicInformer
+	handler  toolscache.ResourceEventHandler
+	options  toolscache.HandlerOptions
+	handles  map[string]toolscache.ResourceEventHandlerRegistration
+}
+
+var _ cache.Informer = &dynamicInformer{}
+
+func (i *dynamicInformer) addNamespace(ctx context.Context, nc cache.Cache, namespace string) error {
+	informer, err := i.get(ctx, nc)
+	if err != nil {
+		return fmt.Errorf("get informer for namespace %q: %w", namespace, err)
+	}
+	if len(i.indexers) > 0 {
+		if err := informer.AddIndexers(i.indexers); err != nil {
+			return fmt.Errorf("add indexers for namespace %q: %w", namespace, err)
+		}
+	}
+	for _, r := range i.registrations {
+		handle, err := informer.AddEventHandlerWithOptions(r.handler, r.options)
+		if err != nil {
+			return fmt.Errorf("add event handler for namespace %q: %w", namespace, err)
+		}
+		r.handles[namespace] = handle
+	}
+	i.informers[namespace] = informer
+	return nil
+}
+
+func (i *dynamicInformer) removeNamespace(namespace string) {
+	delete(i.informers, namespace)
+	for _, r := range i.registrations {
+		delete(r.handles, namespace)
+	}
+}
+
+func (i *dynamicInformer) AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.AddEventHandlerWithOptions(handler, toolscache.HandlerOptions{})
+}
+
+func (i *dynamicInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.AddEventHandlerWithOptions(handler, toolscache.HandlerOptions{ResyncPeriod: &resyncPeriod})
+}
+
+func (i *dynamicInformer) AddEventHandlerWithOptions(handler toolscache.ResourceEventHandler, options toolscache.HandlerOptions) (toolscache.ResourceEventHandlerRegistration, error) {
+	i.cache.mu.Lock()
+	defer i.cache.mu.Unlock()
+	r := &dynamicRegistration{
+		informer: i,
+		handler:  handler,
+		options:  options,
+		handles:  make(map[string]toolscache.ResourceEventHandlerRegistration, len(i.informers)),
+	}
+	for ns, informer := range i.informers {
+		handle, err := informer.AddEventHandlerWithOptions(handler, options)
+		if err != nil {
+			return nil, err
+		}
+		r.handles[ns] = handle
+	}
+	i.registrations = append(i.registrations, r)
+	return r, nil
+}
+
+func (i *dynamicInformer) RemoveEventHandler(handle toolscache.ResourceEventHandlerRegistration) error {
+	r, ok := handle.(*dynamicRegistration)
+	if !ok || r.informer != i {
+		return errors.New("registration is not a registration returned by this informer")
+	}
+	i.cache.mu.Lock()
+	defer i.cache.mu.Unlock()
+	for ns, informer := range i.informers {
+		if h, ok := r.handles[ns]; ok {
+			if err := informer.RemoveEventHandler(h); err != nil {
+				return err
+			}
+		}
+	}
+	for idx := range i.registrations {
+		if i.registrations[idx] == r {
+			i.registrations = append(i.registrations[:idx], i.registrations[idx+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (i *dynamicInformer) AddIndexers(indexers toolscache.Indexers) error {
+	i.cache.mu.Lock()
+	defer i.cache.mu.Unlock()
+	for _, informer := range i.informers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	if i.indexers == nil {
+		i.indexers = toolscache.Indexers{}
+	}
+	for name, indexFunc := range indexers {
+		i.indexers[name] = indexFunc
+	}
+	return nil
+}
+
+// HasSynced checks if the informers of all watched namespaces have synced.
+func (i *dynamicInformer) HasSynced() bool {
+	i.cache.mu.RLock()
+	defer i.cache.mu.RUnlock()
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsStopped returns true if the cache has been stopped.
+func (i *dynamicInformer) IsStopped() bool {
+	i.cache.mu.RLock()
+	defer i.cache.mu.RUnlock()
+	return i.cache.ctx != nil && i.cache.ctx.Err() != nil
+}
+
+// HasSynced checks if the handler has been called for the initial state of
+// the informers of all watched namespaces.
+func (r *dynamicRegistration) HasSynced() bool {
+	r.informer.cache.mu.RLock()
+	defer r.informer.cache.mu.RUnlock()
+	for _, h := range r.handles {
+		if !h.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+)
+
+var _ = Describe("dynamicNamespaceCache", func() {
+	var (
+		ctx             context.Context
+		cancel          context.CancelFunc
+		clusterCache    *fakeCache
+		namespaceCaches map[string]*fakeCache
+		c               *dynamicNamespaceCache
+		started         sync.WaitGroup
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		clusterCache = newFakeCache()
+		namespaceCaches = map[string]*fakeCache{}
+		newNamespaceCache := func(namespace string) (cache.Cache, error) {
+			nc := newFakeCache()
+			namespaceCaches[namespace] = nc
+			return nc, nil
+		}
+
+		mapper := apimeta.NewDefaultRESTMapper(nil)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), apimeta.RESTScopeNamespace)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), apimeta.RESTScopeRoot)
+		selector, err := labels.Parse("tenant")
+		Expect(err).ToNot(HaveOccurred())
+
+		c, err = newDynamicNamespaceCache(clusterCache, newNamespaceCache, cache.Options{Scheme: scheme.Scheme, Mapper: mapper}, selector, []string{"excluded"}, logr.Discard())
+		Expect(err).ToNot(HaveOccurred())
+
+		started.Add(1)
+		go func() {
+			defer GinkgoRecover()
+			defer started.Done()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+		Eventually(func() bool {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			return c.ctx != nil
+		}).Should(BeTrue())
+	})
+
+	AfterEach(func() {
+		cancel()
+		started.Wait()
+	})
+
+	namespace := func(name string, lbls map[string]string) *metav1.PartialObjectMetadata {
+		ns := namespaceMetadata()
+		ns.SetName(name)
+		ns.SetLabels(lbls)
+		return ns
+	}
+
+	It("watches the namespaces that match the selector and are not excluded", func() {
+		clusterCache.namespaces.Add(namespace("tenant-a", map[string]string{"tenant": "a"}))
+		clusterCache.namespaces.Add(namespace("other", nil))
+		clusterCache.namespaces.Add(namespace("excluded", map[string]string{"tenant": "x"}))
+		Expect(c.WatchedNamespaces()).To(Equal([]string{"tenant-a"}))
+		Expect(namespaceCaches).To(HaveKey("tenant-a"))
+		Eventually(func() context.Context { return namespaceCaches["tenant-a"].startedContext() }).ShouldNot(BeNil())
+	})
+
+	It("follows the labels of namespaces", func() {
+		unlabeled := namespace("tenant-b", nil)
+		labeled := namespace("tenant-b", map[string]string{"tenant": "b"})
+		clusterCache.namespaces.Add(unlabeled)
+		Expect(c.WatchedNamespaces()).To(BeEmpty())
+
+		By("adding the label")
+		clusterCache.namespaces.Update(unlabeled, labeled)
+		Expect(c.WatchedNamespaces()).To(Equal([]string{"tenant-b"}))
+		nc := namespaceCaches["tenant-b"]
+		Eventually(nc.startedContext).ShouldNot(BeNil())
+
+		By("removing the label")
+		clusterCache.namespaces.Update(labeled, unlabeled)
+		Expect(c.WatchedNamespaces()).To(BeEmpty())
+		Expect(nc.startedContext().Err()).To(HaveOccurred())
+
+		By("adding the label again and deleting the namespace")
+		clusterCache.namespaces.Update(unlabeled, labeled)
+		Expect(c.WatchedNamespaces()).To(Equal([]string{"tenant-b"}))
+		clusterCache.namespaces.Delete(labeled)
+		Expect(c.WatchedNamespaces()).To(BeEmpty())
+	})
+
+	It("stops watching namespaces when it is stopped", func() {
+		clusterCache.namespaces.Add(namespace("tenant-c", map[string]string{"tenant": "c"}))
+		nc := namespaceCaches["tenant-c"]
+		Eventually(nc.startedContext).ShouldNot(BeNil())
+		cancel()
+		started.Wait()
+		Expect(c.WatchedNamespaces()).To(BeEmpty())
+		Expect(nc.startedContext().Err()).To(HaveOccurred())
+	})
+
+	It("adds event handlers and indexers to the informers of namespaces watched later", func() {
+		informer, err := c.GetInformer(ctx, &corev1.Pod{})
+		Expect(err).ToNot(HaveOccurred())
+		var added []string
+		_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				added = append(added, obj.(client.Object).GetNamespace())
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.IndexField(ctx, &corev1.Pod{}, "spec.nodeName", func(client.Object) []string { return nil })).To(Succeed())
+
+		clusterCache.namespaces.Add(namespace("tenant-d", map[string]string{"tenant": "d"}))
+		nc := namespaceCaches["tenant-d"]
+		Expect(nc.indexedFields).To(ConsistOf("spec.nodeName"))
+		podInformer, err := nc.FakeInformerFor(ctx, &corev1.Pod{})
+		Expect(err).ToNot(HaveOccurred())
+		podInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-d", Name: "pod"}})
+		Expect(added).To(Equal([]string{"tenant-d"}))
+	})
+
+	It("reads namespaced objects from the cache of their namespace", func() {
+		clusterCache.namespaces.Add(namespace("tenant-e", map[string]string{"tenant": "e"}))
+
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "tenant-e", Name: "pod"}, &corev1.Pod{})).To(Succeed())
+		Expect(namespaceCaches["tenant-e"].gets).To(Equal(1))
+		err := c.Get(ctx, types.NamespacedName{Namespace: "other", Name: "pod"}, &corev1.Pod{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(err).To(MatchError(`pods "pod" not found`))
+		pods := &corev1.PodList{Items: []corev1.Pod{{}}}
+		Expect(c.List(ctx, pods, client.InNamespace("other"))).To(Succeed())
+		Expect(pods.Items).To(BeEmpty())
+		Expect(c.List(ctx, &corev1.PodList{})).To(Succeed())
+
+		Expect(c.Get(ctx, types.NamespacedName{Name: "tenant-e"}, &corev1.Namespace{})).To(Succeed())
+		Expect(clusterCache.gets).To(Equal(1))
+	})
+})
+
+// fakeCache is a cache with fake informers that records its use and blocks in
+// Start until it is stopped.
+type fakeCache struct {
+	*informertest.FakeInformers
+	namespaces *controllertest.FakeInformer
+
+	mu            sync.Mutex
+	ctx           context.Context
+	gets          int
+	indexedFields []string
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{
+		FakeInformers: &informertest.FakeInformers{},
+		namespaces:    &controllertest.FakeInformer{Synced: true},
+	}
+}
+
+func (c *fakeCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	if _, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		return c.namespaces, nil
+	}
+	return c.FakeInformers.GetInformer(ctx, obj, opts...)
+}
+
+func (c *fakeCache) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.ctx != nil {
+		c.mu.Unlock()
+		return errors.New("already started")
+	}
+	c.ctx = ctx
+	c.mu.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+func (c *fakeCache) startedContext() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ctx
+}
+
+func (c *fakeCache) IndexField(_ context.Context, _ client.Object, field string, _ client.IndexerFunc) error {
+	c.indexedFields = append(c.indexedFields, field)
+	return nil
+}
+
+func (c *fakeCache) Get(_ context.Context, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	c.gets++
+	return nil
+}
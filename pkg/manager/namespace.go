@@ -17,20 +17,42 @@ limitations under the License.
 package manager
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 const (
 	WatchNamespaceEnvVar = "WATCH_NAMESPACE"
+
+	// WatchNamespaceSelectorEnvVar is a label selector of the namespaces to
+	// watch. When it or WatchNamespaceExcludeEnvVar is set and
+	// WatchNamespaceEnvVar is not, the watched namespaces follow the labels
+	// of the namespaces while the manager runs.
+	WatchNamespaceSelectorEnvVar = "WATCH_NAMESPACE_SELECTOR"
+	// WatchNamespaceExcludeEnvVar is a comma-separated list of namespaces
+	// that are not watched even if they match WatchNamespaceSelectorEnvVar.
+	WatchNamespaceExcludeEnvVar = "WATCH_NAMESPACE_EXCLUDE"
 )
 
 func ConfigureWatchNamespaces(options *manager.Options, log logr.Logger) {
 	namespaces := splitNamespaces(os.Getenv(WatchNamespaceEnvVar))
+	selector, selectorSet := os.LookupEnv(WatchNamespaceSelectorEnvVar)
+	exclude := splitNamespaces(os.Getenv(WatchNamespaceExcludeEnvVar))
+
+	if len(namespaces) == 0 && (selectorSet || len(exclude) != 0) {
+		configureDynamicWatchNamespaces(options, selector, exclude, log)
+		return
+	}
+	if selectorSet || len(exclude) != 0 {
+		log.Info("ignoring namespace selector and exclusions because namespaces are set", "env", WatchNamespaceEnvVar)
+	}
 
 	var namespaceConfigs map[string]cache.Config
 	if len(namespaces) != 0 {
@@ -46,6 +68,21 @@ func ConfigureWatchNamespaces(options *manager.Options, log logr.Logger) {
 	options.Cache.DefaultNamespaces = namespaceConfigs
 }
 
+func configureDynamicWatchNamespaces(options *manager.Options, selector string, exclude []string, log logr.Logger) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		// The error is returned when the manager creates its cache.
+		err = fmt.Errorf("invalid %s: %w", WatchNamespaceSelectorEnvVar, err)
+		options.NewCache = func(*rest.Config, cache.Options) (cache.Cache, error) {
+			return nil, err
+		}
+		return
+	}
+	log.Info("watching namespaces dynamically", "selector", sel.String(), "excluded", exclude)
+	options.Cache.DefaultNamespaces = nil
+	options.NewCache = NewDynamicNamespaceCacheFunc(sel, exclude, log.WithName("namespaces"))
+}
+
 func splitNamespaces(namespaces string) []string {
 	list := strings.Split(namespaces, ",")
 	var out []string
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -44,6 +45,9 @@ var _ = Describe("ConfigureWatchNamespaces", func() {
 
 	BeforeEach(func() {
 		Expect(os.Unsetenv(WatchNamespaceEnvVar)).To(Succeed())
+		Expect(os.Unsetenv(WatchNamespaceSelectorEnvVar)).To(Succeed())
+		Expect(os.Unsetenv(WatchNamespaceExcludeEnvVar)).To(Succeed())
+		opts = manager.Options{}
 	})
 
 	When("should watch all namespaces", func() {
@@ -170,8 +174,88 @@ var _ = Describe("ConfigureWatchNamespaces", func() {
 			wg.Wait()
 		})
 	})
+	When("should watch namespaces selected by labels", func() {
+		It("should fail to create the cache when WATCH_NAMESPACE_SELECTOR is invalid", func() {
+			Expect(os.Setenv(WatchNamespaceSelectorEnvVar, "tenant in (")).To(Succeed())
+			ConfigureWatchNamespaces(&opts, log)
+
+			Expect(opts.NewCache).NotTo(BeNil())
+			_, err := opts.NewCache(cfg, cache.Options{})
+			Expect(err).To(MatchError(ContainSubstring("invalid " + WatchNamespaceSelectorEnvVar)))
+		})
+
+		It("should watch namespaces as they gain and lose the selected label", func() {
+			By("creating pods in namespaces to watch")
+			pods, err := createPods(context.TODO(), 3)
+			Expect(err).ToNot(HaveOccurred())
+			label := fmt.Sprintf("tenant-%s", rand.String(5))
+			watched, excluded, unwatched := pods[0], pods[1], pods[2]
+			Expect(setNamespaceLabel(context.TODO(), watched.Namespace, label, true)).To(Succeed())
+			Expect(setNamespaceLabel(context.TODO(), excluded.Namespace, label, true)).To(Succeed())
+
+			By("configuring WATCH_NAMESPACE_SELECTOR and WATCH_NAMESPACE_EXCLUDE")
+			Expect(os.Setenv(WatchNamespaceSelectorEnvVar, label)).To(Succeed())
+			Expect(os.Setenv(WatchNamespaceExcludeEnvVar, excluded.Namespace)).To(Succeed())
+			ConfigureWatchNamespaces(&opts, log)
+
+			By("creating and starting the manager")
+			mgr, err := manager.New(cfg, opts)
+			Expect(err).ToNot(HaveOccurred())
+			ctx, cancel := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				Expect(mgr.Start(ctx)).To(Succeed())
+				wg.Done()
+			}()
+			c := mgr.GetCache()
+			Expect(c.WaitForCacheSync(ctx)).To(BeTrue())
+
+			By("getting only the pod in the selected namespace")
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(&watched), &corev1.Pod{})).To(Succeed())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(&excluded), &corev1.Pod{})).NotTo(Succeed())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(&unwatched), &corev1.Pod{})).NotTo(Succeed())
+
+			By("labeling the unwatched namespace")
+			Expect(setNamespaceLabel(ctx, unwatched.Namespace, label, true)).To(Succeed())
+			Eventually(func() error {
+				return c.Get(ctx, client.ObjectKeyFromObject(&unwatched), &corev1.Pod{})
+			}).Should(Succeed())
+
+			By("removing the label from the watched namespace")
+			Expect(setNamespaceLabel(ctx, watched.Namespace, label, false)).To(Succeed())
+			Eventually(func() error {
+				return c.Get(ctx, client.ObjectKeyFromObject(&watched), &corev1.Pod{})
+			}).ShouldNot(Succeed())
+
+			cancel()
+			wg.Wait()
+		})
+	})
 })
 
+func setNamespaceLabel(ctx context.Context, namespace, label string, set bool) error {
+	cl, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return err
+	}
+	ns := &corev1.Namespace{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return err
+	}
+	labels := ns.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if set {
+		labels[label] = ""
+	} else {
+		delete(labels, label)
+	}
+	ns.SetLabels(labels)
+	return cl.Update(ctx, ns)
+}
+
 func createPods(ctx context.Context, count int) ([]corev1.Pod, error) {
 	cl, err := client.New(cfg, client.Options{})
 	if err != nil {
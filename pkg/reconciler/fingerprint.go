@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// releaseFingerprint returns a fingerprint of the inputs that the manifest of
+// a release is rendered from: the chart, the values, the override values and
+// the configuration of the post-renderers.
+func (r *Reconciler) releaseFingerprint(vals map[string]interface{}) (string, error) {
+	r.chartDigestOnce.Do(func() {
+		r.chartDigest = chartDigest(r.chrt)
+	})
+	b, err := json.Marshal(struct {
+		Chart                  string                 `json:"chart"`
+		Values                 map[string]interface{} `json:"values"`
+		OverrideValues         map[string]string      `json:"overrideValues,omitempty"`
+		DependentResourceLabel map[string]string      `json:"dependentResourceLabel,omitempty"`
+		PostRendererConfig     []string               `json:"postRendererConfig,omitempty"`
+	}{
+		Chart:                  r.chartDigest,
+		Values:                 vals,
+		OverrideValues:         r.overrideValues,
+		DependentResourceLabel: r.dependentLabels(),
+		PostRendererConfig:     r.postRendererConfig,
+	})
+	if err != nil {
+		return "", fmt.Errorf("computing release fingerprint: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// chartDigest returns a digest of the metadata, templates, files, values and
+// schema of the chart and its dependencies.
+func chartDigest(c *chart.Chart) string {
+	h := sha256.New()
+	writeChart(h, c)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeChart(h hash.Hash, c *chart.Chart) {
+	// Errors are impossible, since the metadata and values of charts are
+	// decoded from YAML.
+	metadata, _ := json.Marshal(c.Metadata)
+	values, _ := json.Marshal(c.Values)
+	writeField(h, metadata)
+	writeField(h, values)
+	writeField(h, c.Schema)
+	for _, files := range [][]*chart.File{c.Templates, c.Files} {
+		writeField(h, []byte(fmt.Sprint(len(files))))
+		for _, f := range files {
+			writeField(h, []byte(f.Name))
+			writeField(h, f.Data)
+		}
+	}
+	deps := c.Dependencies()
+	writeField(h, []byte(fmt.Sprint(len(deps))))
+	for _, dep := range deps {
+		writeChart(h, dep)
+	}
+}
+
+// writeField writes b prefixed with its length, so that the boundaries of
+// fields are part of the digest.
+func writeField(h hash.Hash, b []byte) {
+	_, _ = fmt.Fprintf(h, "%d:", len(b))
+	_, _ = h.Write(b)
+}
+
+// fingerprintMatches returns whether the release fingerprint in the status of
+// obj was recorded for the given fingerprint and the release.
+func fingerprintMatches(obj *unstructured.Unstructured, fingerprint string, rel *release.Release) bool {
+	inputs, _, _ := unstructured.NestedString(obj.Object, "status", "releaseFingerprint", "inputs")
+	version, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "status", "releaseFingerprint", "version")
+	return inputs == fingerprint && fmt.Sprint(version) == fmt.Sprint(rel.Version)
+}
+
+// dryRunChecks records when the releases of custom resources were last
+// checked with a dry-run upgrade. Since it is not persisted, the release of
+// every custom resource is checked once after the operator starts.
+type dryRunChecks struct {
+	mu   sync.Mutex
+	last map[types.NamespacedName]time.Time
+}
+
+// due returns whether the release of the custom resource was not checked
+// within the period.
+func (c *dryRunChecks) due(key types.NamespacedName, period time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last, ok := c.last[key]
+	return !ok || time.Since(last) >= period
+}
+
+func (c *dryRunChecks) record(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last == nil {
+		c.last = make(map[types.NamespacedName]time.Time)
+	}
+	c.last[key] = time.Now()
+}
+
+func (c *dryRunChecks) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.last, key)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	reconcilertesting "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/testing"
+)
+
+var _ = Describe("Release fingerprint", func() {
+	var r *Reconciler
+
+	BeforeEach(func() {
+		c := chrt
+		r = &Reconciler{chrt: &c, dryRunFullCheckPeriod: time.Hour}
+	})
+
+	It("should change with the inputs of the release", func() {
+		base, err := r.releaseFingerprint(map[string]interface{}{"replicas": int64(1)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.releaseFingerprint(map[string]interface{}{"replicas": int64(1)})).To(Equal(base))
+		Expect(r.releaseFingerprint(map[string]interface{}{"replicas": int64(2)})).NotTo(Equal(base))
+
+		r.overrideValues = map[string]string{"image": "nginx"}
+		Expect(r.releaseFingerprint(map[string]interface{}{"replicas": int64(1)})).NotTo(Equal(base))
+		r.overrideValues = nil
+
+		r.postRendererConfig = []string{"kustomize-v1"}
+		Expect(r.releaseFingerprint(map[string]interface{}{"replicas": int64(1)})).NotTo(Equal(base))
+	})
+
+	It("should change with the templates of the chart and its dependencies", func() {
+		digest := chartDigest(r.chrt)
+		Expect(chartDigest(r.chrt)).To(Equal(digest))
+
+		changed := *r.chrt
+		changed.Templates = append([]*chart.File{{Name: "templates/extra.yaml", Data: []byte("kind: ConfigMap")}}, changed.Templates...)
+		Expect(chartDigest(&changed)).NotTo(Equal(digest))
+
+		withDep := *r.chrt
+		withDep.AddDependency(&chart.Chart{Metadata: &chart.Metadata{Name: "dep", Version: "0.1.0"}})
+		Expect(chartDigest(&withDep)).NotTo(Equal(digest))
+	})
+
+	Describe("getReleaseState", func() {
+		var (
+			ac          *reconcilertesting.ActionClient
+			obj         *unstructured.Unstructured
+			current     *release.Release
+			fingerprint string
+		)
+
+		BeforeEach(func() {
+			one := 1
+			r.maxReleaseHistory = &one
+			current = &release.Release{Name: "test", Version: 3, Manifest: "manifest", Info: &release.Info{Status: release.StatusDeployed}}
+			ac = reconcilertesting.NewActionClient()
+			ac.HandleGet = reconcilertesting.Return(current, nil)
+			ac.HandleUpgrade = reconcilertesting.Return(&release.Release{Name: "test", Manifest: "manifest"}, nil)
+
+			var err error
+			fingerprint, err = r.releaseFingerprint(map[string]interface{}{})
+			Expect(err).NotTo(HaveOccurred())
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "test", "namespace": "default"},
+				"status": map[string]interface{}{
+					"releaseFingerprint": map[string]interface{}{"inputs": fingerprint, "version": int64(3)},
+				},
+			}}
+		})
+
+		It("should check the release with a dry-run once after starting", func() {
+			_, state, err := r.getReleaseState(ac, obj, map[string]interface{}{}, fingerprint, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(stateUnchanged))
+			Expect(ac.Upgrades).To(HaveLen(1))
+
+			_, state, err = r.getReleaseState(ac, obj, map[string]interface{}{}, fingerprint, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(stateUnchanged))
+			Expect(ac.Upgrades).To(HaveLen(1))
+		})
+
+		It("should check the release with a dry-run if the fingerprint or version differs", func() {
+			r.dryRunChecks.record(types.NamespacedName{Namespace: "default", Name: "test"})
+
+			_, _, err := r.getReleaseState(ac, obj, map[string]interface{}{}, "other", logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ac.Upgrades).To(HaveLen(1))
+
+			current.Version = 4
+			_, _, err = r.getReleaseState(ac, obj, map[string]interface{}{}, fingerprint, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ac.Upgrades).To(HaveLen(2))
+		})
+
+		It("should check the release with a dry-run if it is not deployed", func() {
+			r.dryRunChecks.record(types.NamespacedName{Namespace: "default", Name: "test"})
+			current.Info.Status = release.StatusFailed
+
+			_, state, err := r.getReleaseState(ac, obj, map[string]interface{}{}, fingerprint, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(stateNeedsUpgrade))
+			Expect(ac.Upgrades).To(HaveLen(1))
+		})
+
+		It("should check the release with a dry-run when a full check is due", func() {
+			r.dryRunFullCheckPeriod = time.Nanosecond
+			r.dryRunChecks.record(types.NamespacedName{Namespace: "default", Name: "test"})
+			time.Sleep(time.Millisecond)
+
+			_, _, err := r.getReleaseState(ac, obj, map[string]interface{}{}, fingerprint, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ac.Upgrades).To(HaveLen(1))
+		})
+	})
+})
@@ -25,16 +25,23 @@ import (
 )
 
 const (
-	TypeInitialized    = "Initialized"
-	TypeDeployed       = "Deployed"
-	TypeReleaseFailed  = "ReleaseFailed"
-	TypeIrreconcilable = "Irreconcilable"
-	TypePaused         = "Paused"
+	TypeInitialized      = "Initialized"
+	TypeDeployed         = "Deployed"
+	TypeReleaseFailed    = "ReleaseFailed"
+	TypeIrreconcilable   = "Irreconcilable"
+	TypePaused           = "Paused"
+	TypeUpgradeDeferred  = "UpgradeDeferred"
+	TypeUninstallBlocked = "UninstallBlocked"
 
 	ReasonInstallSuccessful            = status.ConditionReason("InstallSuccessful")
 	ReasonUpgradeSuccessful            = status.ConditionReason("UpgradeSuccessful")
 	ReasonUninstallSuccessful          = status.ConditionReason("UninstallSuccessful")
 	ReasonPauseReconcileAnnotationTrue = status.ConditionReason("PauseReconcileAnnotationTrue")
+	ReasonUpgradesPaused               = status.ConditionReason("UpgradesPaused")
+	ReasonDriftCorrectionPaused        = status.ConditionReason("DriftCorrectionPaused")
+	ReasonPreUninstallHookPending      = status.ConditionReason("PreUninstallHookPending")
+	ReasonPostUninstallHookPending     = status.ConditionReason("PostUninstallHookPending")
+	ReasonOutsideMaintenanceWindow     = status.ConditionReason("OutsideMaintenanceWindow")
 
 	ReasonErrorGettingClient       = status.ConditionReason("ErrorGettingClient")
 	ReasonErrorGettingValues       = status.ConditionReason("ErrorGettingValues")
@@ -43,8 +50,22 @@ const (
 	ReasonUpgradeError             = status.ConditionReason("UpgradeError")
 	ReasonReconcileError           = status.ConditionReason("ReconcileError")
 	ReasonUninstallError           = status.ConditionReason("UninstallError")
+	ReasonUpgradeScheduleError     = status.ConditionReason("UpgradeScheduleError")
+	ReasonPreHookError             = status.ConditionReason("PreHookError")
+	ReasonPreHookAborted           = status.ConditionReason("PreHookAborted")
+	ReasonUninstallHookError       = status.ConditionReason("UninstallHookError")
 )
 
+// IsReserved returns whether conditions of the given type are set by the
+// reconciler.
+func IsReserved(t status.ConditionType) bool {
+	switch t {
+	case TypeInitialized, TypeDeployed, TypeReleaseFailed, TypeIrreconcilable, TypePaused, TypeUpgradeDeferred, TypeUninstallBlocked:
+		return true
+	}
+	return false
+}
+
 func Initialized(stat corev1.ConditionStatus, reason status.ConditionReason, message interface{}) status.Condition {
 	return newCondition(TypeInitialized, stat, reason, message)
 }
@@ -65,6 +86,14 @@ func Paused(stat corev1.ConditionStatus, reason status.ConditionReason, message
 	return newCondition(TypePaused, stat, reason, message)
 }
 
+func UpgradeDeferred(stat corev1.ConditionStatus, reason status.ConditionReason, message interface{}) status.Condition {
+	return newCondition(TypeUpgradeDeferred, stat, reason, message)
+}
+
+func UninstallBlocked(stat corev1.ConditionStatus, reason status.ConditionReason, message interface{}) status.Condition {
+	return newCondition(TypeUninstallBlocked, stat, reason, message)
+}
+
 func newCondition(t status.ConditionType, s corev1.ConditionStatus, r status.ConditionReason, m interface{}) status.Condition {
 	message := fmt.Sprintf("%s", m)
 	return status.Condition{
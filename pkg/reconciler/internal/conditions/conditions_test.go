@@ -77,4 +77,36 @@ var _ = Describe("Conditions", func() {
 			Expect(Irreconcilable(e.Status, e.Reason, err)).To(Equal(e))
 		})
 	})
+
+	var _ = Describe("UpgradeDeferred", func() {
+		It("should return an UpgradeDeferred condition with the correct status, reason, and message", func() {
+			e := status.Condition{
+				Type:    TypeUpgradeDeferred,
+				Status:  corev1.ConditionTrue,
+				Reason:  ReasonOutsideMaintenanceWindow,
+				Message: "message",
+			}
+			Expect(UpgradeDeferred(e.Status, e.Reason, e.Message)).To(Equal(e))
+		})
+	})
+
+	var _ = Describe("UninstallBlocked", func() {
+		It("should return an UninstallBlocked condition with the correct status, reason, and message", func() {
+			e := status.Condition{
+				Type:    TypeUninstallBlocked,
+				Status:  corev1.ConditionTrue,
+				Reason:  ReasonPreUninstallHookPending,
+				Message: "message",
+			}
+			Expect(UninstallBlocked(e.Status, e.Reason, e.Message)).To(Equal(e))
+		})
+	})
+
+	var _ = Describe("IsReserved", func() {
+		It("should only be true for the types of the conditions of the reconciler", func() {
+			Expect(IsReserved(TypeDeployed)).To(BeTrue())
+			Expect(IsReserved(TypeUninstallBlocked)).To(BeTrue())
+			Expect(IsReserved("LicenseValid")).To(BeFalse())
+		})
+	})
 })
@@ -17,134 +17,357 @@ limitations under the License.
 package hook
 
 import (
+	"context"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	sdkhandler "github.com/operator-framework/operator-lib/handler"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/releaseutil"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crtpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sigs.k8s.io/yaml"
 
 	"github.com/operator-framework/helm-operator-plugins/internal/sdk/controllerutil"
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
 	"github.com/operator-framework/helm-operator-plugins/pkg/hook"
 	"github.com/operator-framework/helm-operator-plugins/pkg/internal/predicate"
 	"github.com/operator-framework/helm-operator-plugins/pkg/manifestutil"
 )
 
-func NewDependentResourceWatcher(c controller.Controller, rm meta.RESTMapper, cache cache.Cache, scheme *runtime.Scheme) hook.PostHook {
-	return &dependentResourceWatcher{
+// DependentResourceWatcherOption configures the dependent resource watcher.
+type DependentResourceWatcherOption func(*dependentResourceWatcher)
+
+// WithRemoteClusters configures the dependent resource watcher to watch the
+// dependent resources of owners whose releases are installed in remote
+// clusters with the caches of these clusters. Since they cannot be owned by
+// the owner, they are always watched using owner annotations.
+func WithRemoteClusters(rc *helmclient.RemoteClusters) DependentResourceWatcherOption {
+	return func(d *dependentResourceWatcher) {
+		d.remoteClusters = rc
+	}
+}
+
+// WithStatusChangesOf configures the dependent resource watcher to also
+// reconcile the owner when only the status of a dependent resource of one of
+// the given kinds changes.
+func WithStatusChangesOf(gks ...schema.GroupKind) DependentResourceWatcherOption {
+	return func(d *dependentResourceWatcher) {
+		d.statusKinds = append(d.statusKinds, gks...)
+	}
+}
+
+// WithMetadataOnly configures the dependent resource watcher to watch
+// dependent resources with metadata-only informers, which only cache the
+// metadata of the objects. Kinds whose status changes are watched are still
+// watched with informers of full objects.
+func WithMetadataOnly() DependentResourceWatcherOption {
+	return func(d *dependentResourceWatcher) {
+		d.metadataOnly = true
+	}
+}
+
+// WithEventFilter configures which events of dependent resources reconcile the
+// owner. Reconciliations triggered by updates are delayed by the debounce
+// period of the filter, so that all updates within the period reconcile the
+// owner once.
+func WithEventFilter(f *eventfilter.Filter) DependentResourceWatcherOption {
+	return func(d *dependentResourceWatcher) {
+		d.eventFilter = f
+	}
+}
+
+// DependentResourceWatcher is a post-hook that watches the kinds of the
+// objects of the releases of owners. Informers are added when the release of
+// an owner is the first to contain objects of their kind, and are removed when
+// no release contains objects of their kind any more.
+type DependentResourceWatcher interface {
+	hook.PostHookV2
+
+	// Forget removes the references of the release of owner to the kinds of
+	// its objects, e.g. after the release was uninstalled.
+	Forget(ctx context.Context, owner *unstructured.Unstructured, log logr.Logger)
+}
+
+func NewDependentResourceWatcher(c controller.Controller, rm meta.RESTMapper, cache cache.Cache, scheme *runtime.Scheme, opts ...DependentResourceWatcherOption) DependentResourceWatcher {
+	d := &dependentResourceWatcher{
 		controller: c,
 		restMapper: rm,
 		cache:      cache,
 		scheme:     scheme,
-		m:          sync.Mutex{},
-		watches:    make(map[schema.GroupVersionKind]struct{}),
+		owners:     make(map[types.NamespacedName][]informerKey),
+	}
+	for _, o := range opts {
+		o(d)
 	}
+	return d
 }
 
 type dependentResourceWatcher struct {
-	controller controller.Controller
-	restMapper meta.RESTMapper
-	cache      cache.Cache
-	scheme     *runtime.Scheme
+	controller     controller.Controller
+	restMapper     meta.RESTMapper
+	cache          cache.Cache
+	scheme         *runtime.Scheme
+	remoteClusters *helmclient.RemoteClusters
+	statusKinds    []schema.GroupKind
+	metadataOnly   bool
+	eventFilter    *eventfilter.Filter
 
-	m       sync.Mutex
-	watches map[schema.GroupVersionKind]struct{}
+	// owners contains the informers that the releases of the owners
+	// reference. It is guarded by the lock of informers.
+	owners map[types.NamespacedName][]informerKey
 }
 
-func (d *dependentResourceWatcher) Exec(owner *unstructured.Unstructured, rel release.Release, log logr.Logger) error {
-	// using predefined functions for filtering events
-	dependentPredicate := predicate.DependentPredicateFuncs()
+// informerKey identifies an informer of a cache.
+type informerKey struct {
+	cache        cache.Cache
+	gvk          schema.GroupVersionKind
+	metadataOnly bool
+}
 
-	resources := releaseutil.SplitManifests(rel.Manifest)
-	d.m.Lock()
-	defer d.m.Unlock()
-	for _, r := range resources {
-		var obj unstructured.Unstructured
-		err := yaml.Unmarshal([]byte(r), &obj)
-		if err != nil {
+func (k informerKey) object() client.Object {
+	if k.metadataOnly {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(k.gvk)
+		return obj
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(k.gvk)
+	return obj
+}
+
+// informers counts for each informer on which dependent resource watchers
+// have registered a watch how many releases of the owners of each watcher
+// reference it. Since the watchers of several reconcilers may share a cache,
+// e.g. the cache of the manager, an informer is only removed when no release
+// of any watcher references it any more.
+var informers = struct {
+	m    sync.Mutex
+	refs map[informerKey]map[*dependentResourceWatcher]int
+}{refs: make(map[informerKey]map[*dependentResourceWatcher]int)}
+
+func (d *dependentResourceWatcher) Exec(ctx context.Context, owner *unstructured.Unstructured, rel release.Release, log logr.Logger) error {
+	var cluster *helmclient.Cluster
+	if d.remoteClusters != nil {
+		cluster = d.remoteClusters.Lookup(owner)
+	}
+	watchCache := d.cache
+	if cluster != nil {
+		var err error
+		if watchCache, err = cluster.Cache(); err != nil {
 			return err
 		}
+	}
+
+	dependents, err := dependentObjects(rel.Manifest)
+	if err != nil {
+		return err
+	}
+
+	informers.m.Lock()
+	defer informers.m.Unlock()
+	keys := make([]informerKey, 0, len(dependents))
+	for _, dependent := range dependents {
+		depGVK := dependent.GroupVersionKind()
+		key := informerKey{
+			cache:        watchCache,
+			gvk:          depGVK,
+			metadataOnly: d.metadataOnly && !slices.Contains(d.statusKinds, depGVK.GroupKind()),
+		}
+		if _, ok := informers.refs[key][d]; !ok {
+			if err := d.watch(key, cluster, owner, dependent); err != nil {
+				return err
+			}
+			if informers.refs[key] == nil {
+				informers.refs[key] = make(map[*dependentResourceWatcher]int)
+			}
+			informers.refs[key][d] = 0
+
+			if cluster != nil {
+				log.V(1).Info("Watching dependent resource in remote cluster", "dependentAPIVersion", depGVK.GroupVersion(), "dependentKind", depGVK.Kind, "kubeConfigSecret", cluster.Secret)
+			} else {
+				log.V(1).Info("Watching dependent resource", "dependentAPIVersion", depGVK.GroupVersion(), "dependentKind", depGVK.Kind)
+			}
+		}
+		keys = append(keys, key)
+	}
+	d.setReferences(ctx, client.ObjectKeyFromObject(owner), keys, log)
+	return nil
+}
 
-		depGVK := obj.GroupVersionKind()
-		if depGVK.Empty() {
+func (d *dependentResourceWatcher) Forget(ctx context.Context, owner *unstructured.Unstructured, log logr.Logger) {
+	informers.m.Lock()
+	defer informers.m.Unlock()
+	d.setReferences(ctx, client.ObjectKeyFromObject(owner), nil, log)
+}
+
+// setReferences replaces the informers that the release of owner references
+// with keys and removes the informers that are not referenced any more.
+func (d *dependentResourceWatcher) setReferences(ctx context.Context, owner types.NamespacedName, keys []informerKey, log logr.Logger) {
+	old := d.owners[owner]
+	for _, key := range keys {
+		if !slices.Contains(old, key) {
+			informers.refs[key][d]++
+		}
+	}
+	for _, key := range old {
+		if slices.Contains(keys, key) {
 			continue
 		}
+		informers.refs[key][d]--
+		if referenced(informers.refs[key]) {
+			continue
+		}
+		// Removing the informer also removes the event handlers of the
+		// watches of all watchers on it.
+		if err := key.cache.RemoveInformer(ctx, key.object()); err != nil {
+			log.Error(err, "Failed to remove informer of dependent resources", "dependentAPIVersion", key.gvk.GroupVersion(), "dependentKind", key.gvk.Kind)
+			continue
+		}
+		delete(informers.refs, key)
+		log.V(1).Info("Stopped watching dependent resource", "dependentAPIVersion", key.gvk.GroupVersion(), "dependentKind", key.gvk.Kind)
+	}
+	if len(keys) == 0 {
+		delete(d.owners, owner)
+		return
+	}
+	d.owners[owner] = keys
+}
 
-		setWatchOnResource := func(dependent runtime.Object) error {
-			unstructuredObj := dependent.(*unstructured.Unstructured)
-			gvkDependent := unstructuredObj.GroupVersionKind()
+func referenced(refs map[*dependentResourceWatcher]int) bool {
+	for _, n := range refs {
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
 
-			if gvkDependent.Empty() {
-				return nil
-			}
+// watch registers a watch on the informer of the given key, which reconciles
+// owner when a dependent resource of its kind changes.
+func (d *dependentResourceWatcher) watch(key informerKey, cluster *helmclient.Cluster, owner, dependent *unstructured.Unstructured) error {
+	useOwnerRef := false
+	if cluster == nil {
+		var err error
+		useOwnerRef, err = controllerutil.SupportsOwnerReference(d.restMapper, owner, dependent)
+		if err != nil {
+			return err
+		}
+	}
+	// Setup watch using owner references if possible, otherwise using
+	// annotations.
+	useOwnerRef = useOwnerRef && !manifestutil.HasResourcePolicyKeep(dependent.GetAnnotations())
 
-			_, ok := d.watches[gvkDependent]
-			if ok {
-				return nil
-			}
+	if key.metadataOnly {
+		return d.controller.Watch(kindSource(d, key.cache, key.object().(*metav1.PartialObjectMetadata), owner, useOwnerRef,
+			crtpredicate.TypedPredicate[*metav1.PartialObjectMetadata](predicate.DependentMetadataPredicateFuncs(d.eventFilter))))
+	}
 
-			useOwnerRef, err := controllerutil.SupportsOwnerReference(d.restMapper, owner, unstructuredObj)
-			if err != nil {
-				return err
-			}
+	// using predefined functions for filtering events
+	var p crtpredicate.TypedPredicate[*unstructured.Unstructured] = predicate.DependentPredicateFuncs(d.eventFilter)
+	if slices.Contains(d.statusKinds, key.gvk.GroupKind()) {
+		p = crtpredicate.Or[*unstructured.Unstructured](p, predicate.DependentStatusPredicateFuncs())
+	}
+	return d.controller.Watch(kindSource(d, key.cache, key.object().(*unstructured.Unstructured), owner, useOwnerRef, p))
+}
 
-			if useOwnerRef && !manifestutil.HasResourcePolicyKeep(unstructuredObj.GetAnnotations()) { // Setup watch using owner references.
-				if err := d.controller.Watch(
-					source.Kind(
-						d.cache,
-						unstructuredObj,
-						handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](d.scheme, d.restMapper, owner, handler.OnlyControllerOwner()),
-						dependentPredicate,
-					),
-				); err != nil {
-					return err
-				}
-			} else { // Setup watch using annotations.
-				if err := d.controller.Watch(
-					source.Kind(
-						d.cache,
-						unstructuredObj,
-						&sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{
-							Type: owner.GetObjectKind().GroupVersionKind().GroupKind(),
-						},
-						dependentPredicate,
-					),
-				); err != nil {
-					return err
-				}
-			}
+func kindSource[T client.Object](d *dependentResourceWatcher, c cache.Cache, obj T, owner *unstructured.Unstructured, useOwnerRef bool, p crtpredicate.TypedPredicate[T]) source.Source {
+	var h handler.TypedEventHandler[T, reconcile.Request]
+	if useOwnerRef {
+		h = handler.TypedEnqueueRequestForOwner[T](d.scheme, d.restMapper, owner, handler.OnlyControllerOwner())
+	} else {
+		h = &sdkhandler.EnqueueRequestForAnnotation[T]{
+			Type: owner.GetObjectKind().GroupVersionKind().GroupKind(),
+		}
+	}
+	if period := d.eventFilter.UpdateDebounce(); period > 0 {
+		h = debouncedHandler[T]{TypedEventHandler: h, period: period}
+	}
+	return source.Kind(c, obj, h, p)
+}
+
+// debouncedHandler delays the requests that updates enqueue by period. Since
+// the queue only keeps the earliest time at which a request that is waiting
+// is added, all updates within the period enqueue the request once.
+type debouncedHandler[T client.Object] struct {
+	handler.TypedEventHandler[T, reconcile.Request]
+	period time.Duration
+}
+
+func (h debouncedHandler[T]) Update(ctx context.Context, e event.TypedUpdateEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.TypedEventHandler.Update(ctx, e, delayingQueue{TypedRateLimitingInterface: q, delay: h.period})
+}
+
+// delayingQueue adds requests to the queue after a delay.
+type delayingQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	delay time.Duration
+}
+
+func (q delayingQueue) Add(req reconcile.Request) {
+	q.AddAfter(req, q.delay)
+}
 
-			d.watches[depGVK] = struct{}{}
-			log.V(1).Info("Watching dependent resource", "dependentAPIVersion", depGVK.GroupVersion(), "dependentKind", depGVK.Kind)
+// dependentObjects returns the first object of each kind in the manifest.
+func dependentObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	var (
+		dependents []*unstructured.Unstructured
+		seen       = make(map[schema.GroupVersionKind]struct{})
+	)
+	add := func(o runtime.Object) error {
+		u := o.(*unstructured.Unstructured)
+		gvk := u.GroupVersionKind()
+		if gvk.Empty() {
 			return nil
 		}
+		if _, ok := seen[gvk]; !ok {
+			seen[gvk] = struct{}{}
+			dependents = append(dependents, u)
+		}
+		return nil
+	}
+
+	manifests := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(manifests))
+	for k := range manifests {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	for _, k := range keys {
+		var obj unstructured.Unstructured
+		err := yaml.Unmarshal([]byte(manifests[k]), &obj)
+		if err != nil {
+			return nil, err
+		}
 
 		// List is not actually a resource and therefore cannot have a
 		// watch on it. The watch will be on the kinds listed in the list
 		// and will therefore need to be handled individually.
 		listGVK := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "List"}
-		if depGVK == listGVK {
-			errListItem := obj.EachListItem(func(o runtime.Object) error {
-				return setWatchOnResource(o)
-			})
-			if errListItem != nil {
-				return errListItem
-			}
-		} else {
-			err := setWatchOnResource(&obj)
-			if err != nil {
-				return err
+		if obj.GroupVersionKind() == listGVK {
+			if err := obj.EachListItem(add); err != nil {
+				return nil, err
 			}
+		} else if err := add(&obj); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	return dependents, nil
 }
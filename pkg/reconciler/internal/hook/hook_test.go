@@ -22,23 +22,38 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crtpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
 
 	sdkhandler "github.com/operator-framework/operator-lib/handler"
 
-	"github.com/operator-framework/helm-operator-plugins/pkg/hook"
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
 	"github.com/operator-framework/helm-operator-plugins/pkg/internal/fake"
 	internalhook "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/hook"
 )
@@ -46,7 +61,7 @@ import (
 var _ = Describe("Hook", func() {
 	Describe("dependentResourceWatcher", func() {
 		var (
-			drw   hook.PostHook
+			drw   internalhook.DependentResourceWatcher
 			c     *fake.Controller
 			rm    *meta.DefaultRESTMapper
 			cache cache.Cache
@@ -88,7 +103,7 @@ var _ = Describe("Hook", func() {
 			})
 			It("should fail with an invalid release manifest", func() {
 				rel.Manifest = "---\nfoobar"
-				err := drw.Exec(owner, *rel, log)
+				err := drw.Exec(ctx, owner, *rel, log)
 				Expect(err).To(HaveOccurred())
 			})
 			It("should fail with unknown owner kind", func() {
@@ -97,7 +112,7 @@ var _ = Describe("Hook", func() {
 					SearchedVersions: []string{"v1"},
 				}
 
-				Expect(drw.Exec(owner, *rel, log)).To(MatchError(err))
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(MatchError(err))
 			})
 			It("should fail with unknown dependent kind", func() {
 				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
@@ -105,7 +120,7 @@ var _ = Describe("Hook", func() {
 					GroupKind:        schema.GroupKind{Group: "apps", Kind: "ReplicaSet"},
 					SearchedVersions: []string{"v1"},
 				}
-				Expect(drw.Exec(owner, *rel, log)).To(MatchError(err))
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(MatchError(err))
 			})
 		})
 
@@ -132,7 +147,7 @@ var _ = Describe("Hook", func() {
 					Manifest: strings.Join([]string{clusterRole, clusterRole, rsOwnerNamespace, rsOwnerNamespace}, "---\n"),
 				}
 				drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-				Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 				Expect(c.WatchCalls).To(HaveLen(2))
 				Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
 				Expect(validateSourceHandlerType(c.WatchCalls[1].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
@@ -155,7 +170,7 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{rsOwnerNamespace, ssOtherNamespace}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 					Expect(c.WatchCalls).To(HaveLen(2))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
 					Expect(validateSourceHandlerType(c.WatchCalls[1].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
@@ -165,7 +180,7 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{clusterRole, clusterRoleBinding}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 					Expect(c.WatchCalls).To(HaveLen(2))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
 					Expect(validateSourceHandlerType(c.WatchCalls[1].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
@@ -175,7 +190,7 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{rsOwnerNamespaceWithKeep, ssOtherNamespaceWithKeep, clusterRoleWithKeep, clusterRoleBindingWithKeep}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 					Expect(c.WatchCalls).To(HaveLen(4))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
 					Expect(validateSourceHandlerType(c.WatchCalls[1].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
@@ -202,7 +217,7 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{rsOwnerNamespace}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 					Expect(c.WatchCalls).To(HaveLen(1))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
 				})
@@ -211,7 +226,7 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{clusterRole}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 					Expect(c.WatchCalls).To(HaveLen(1))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
 				})
@@ -220,7 +235,7 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{ssOtherNamespace}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 					Expect(c.WatchCalls).To(HaveLen(1))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
 				})
@@ -229,7 +244,7 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{rsOwnerNamespaceWithKeep, ssOtherNamespaceWithKeep, clusterRoleWithKeep}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
 					Expect(c.WatchCalls).To(HaveLen(3))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
 					Expect(validateSourceHandlerType(c.WatchCalls[1].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
@@ -240,21 +255,302 @@ var _ = Describe("Hook", func() {
 						Manifest: strings.Join([]string{replicaSetList}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					Expect(drw.Exec(owner, *rel, log)).To(Succeed())
-					Expect(c.WatchCalls).To(HaveLen(2))
+					Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+					Expect(c.WatchCalls).To(HaveLen(1))
 					Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
-					Expect(validateSourceHandlerType(c.WatchCalls[1].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
 				})
 				It("should error when unable to list objects", func() {
 					rel = &release.Release{
 						Manifest: strings.Join([]string{errReplicaSetList}, "---\n"),
 					}
 					drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch)
-					err := drw.Exec(owner, *rel, log)
+					err := drw.Exec(ctx, owner, *rel, log)
 					Expect(err).To(HaveOccurred())
 				})
 			})
 		})
+
+		Context("with metadata-only informers", func() {
+			BeforeEach(func() {
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, meta.RESTScopeNamespace)
+				rm.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+				owner = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"metadata": map[string]interface{}{
+							"name":      "testDeployment",
+							"namespace": "ownerNamespace",
+						},
+					},
+				}
+				rel = &release.Release{
+					Manifest: strings.Join([]string{rsOwnerNamespace, clusterRole}, "---\n"),
+				}
+			})
+
+			It("should watch resources with metadata-only handlers", func() {
+				drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch, internalhook.WithMetadataOnly())
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(2))
+				Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*metav1.PartialObjectMetadata](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
+				Expect(validateSourceHandlerType(c.WatchCalls[1].Source, &sdkhandler.EnqueueRequestForAnnotation[*metav1.PartialObjectMetadata]{})).To(Succeed())
+			})
+
+			It("should watch kinds whose status changes are watched with full objects", func() {
+				drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch, internalhook.WithMetadataOnly(), internalhook.WithStatusChangesOf(schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}))
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(2))
+				Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
+				Expect(validateSourceHandlerType(c.WatchCalls[1].Source, &sdkhandler.EnqueueRequestForAnnotation[*metav1.PartialObjectMetadata]{})).To(Succeed())
+			})
+		})
+
+		Context("with an event filter", func() {
+			var (
+				dependent *unstructured.Unstructured
+				source    reflect.Value
+			)
+
+			BeforeEach(func() {
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, meta.RESTScopeNamespace)
+				owner = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"metadata": map[string]interface{}{
+							"name":      "testDeployment",
+							"namespace": "ownerNamespace",
+							"uid":       "owner-uid",
+						},
+					},
+				}
+				rel = &release.Release{Manifest: rsOwnerNamespace}
+
+				f, err := eventfilter.New(eventfilter.Config{
+					IgnoreFields:      []eventfilter.IgnoreField{{Kind: "ReplicaSet.apps", Path: "spec.replicas"}},
+					ReconcileOnCreate: true,
+					UpdateDebounce:    metav1.Duration{Duration: time.Minute},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch, internalhook.WithEventFilter(f))
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(1))
+				source = reflect.Indirect(reflect.ValueOf(c.WatchCalls[0].Source))
+
+				dependent = &unstructured.Unstructured{}
+				Expect(yaml.Unmarshal([]byte(rsOwnerNamespace), &dependent.Object)).To(Succeed())
+				dependent.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "testDeployment", UID: "owner-uid", Controller: ptr.To(true)}})
+				Expect(unstructured.SetNestedField(dependent.Object, int64(3), "spec", "replicas")).To(Succeed())
+			})
+
+			update := func(mutate func(*unstructured.Unstructured)) bool {
+				updated := dependent.DeepCopy()
+				mutate(updated)
+				e := event.TypedUpdateEvent[*unstructured.Unstructured]{ObjectOld: dependent, ObjectNew: updated}
+				for _, p := range source.FieldByName("Predicates").Interface().([]crtpredicate.TypedPredicate[*unstructured.Unstructured]) {
+					if !p.Update(e) {
+						return false
+					}
+				}
+				return true
+			}
+
+			It("should reconcile on creations", func() {
+				p := source.FieldByName("Predicates").Interface().([]crtpredicate.TypedPredicate[*unstructured.Unstructured])
+				Expect(p).To(HaveLen(1))
+				Expect(p[0].Create(event.TypedCreateEvent[*unstructured.Unstructured]{Object: dependent})).To(BeTrue())
+			})
+
+			It("should ignore updates of ignored fields only", func() {
+				Expect(update(func(u *unstructured.Unstructured) {
+					Expect(unstructured.SetNestedField(u.Object, int64(5), "spec", "replicas")).To(Succeed())
+				})).To(BeFalse())
+				Expect(update(func(u *unstructured.Unstructured) {
+					u.SetLabels(map[string]string{"changed": "true"})
+				})).To(BeTrue())
+			})
+
+			It("should delay requests of updates by the debounce period", func() {
+				h := source.FieldByName("Handler").Interface().(handler.TypedEventHandler[*unstructured.Unstructured, reconcile.Request])
+				q := &recordingQueue{}
+				h.Update(ctx, event.TypedUpdateEvent[*unstructured.Unstructured]{ObjectOld: dependent, ObjectNew: dependent}, q)
+				h.Delete(ctx, event.TypedDeleteEvent[*unstructured.Unstructured]{Object: dependent}, q)
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ownerNamespace", Name: "testDeployment"}}
+				Expect(q.delayed).To(Equal(map[reconcile.Request]time.Duration{req: time.Minute}))
+				Expect(q.added).To(Equal([]reconcile.Request{req}))
+			})
+		})
+
+		Context("when releases stop referencing kinds", func() {
+			var (
+				rc     *removeRecordingCache
+				owner2 *unstructured.Unstructured
+			)
+
+			BeforeEach(func() {
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, meta.RESTScopeNamespace)
+				rm.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+				rc = &removeRecordingCache{Cache: cache}
+				owner = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"metadata": map[string]interface{}{
+							"name":      "testDeployment",
+							"namespace": "ownerNamespace",
+						},
+					},
+				}
+				owner2 = owner.DeepCopy()
+				owner2.SetName("otherTestDeployment")
+				rel = &release.Release{
+					Manifest: strings.Join([]string{rsOwnerNamespace, clusterRole}, "---\n"),
+				}
+				drw = internalhook.NewDependentResourceWatcher(c, rm, rc, sch)
+			})
+
+			It("should remove informers that no release references any more", func() {
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(drw.Exec(ctx, owner2, release.Release{Manifest: rsOwnerNamespace}, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(2))
+
+				By("keeping informers that another release references")
+				Expect(drw.Exec(ctx, owner, release.Release{Manifest: clusterRole}, log)).To(Succeed())
+				Expect(rc.removed).To(BeEmpty())
+
+				By("removing informers that no release references")
+				Expect(drw.Exec(ctx, owner, release.Release{Manifest: rsOwnerNamespace}, log)).To(Succeed())
+				Expect(rc.removed).To(Equal([]schema.GroupVersionKind{{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}}))
+
+				By("removing informers of forgotten releases")
+				drw.Forget(ctx, owner, log)
+				Expect(rc.removed).To(HaveLen(1))
+				drw.Forget(ctx, owner2, log)
+				Expect(rc.removed).To(HaveLen(2))
+				Expect(rc.removed[1]).To(Equal(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}))
+
+				By("watching removed kinds again")
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(4))
+			})
+
+			It("should keep informers that releases of other watchers reference", func() {
+				c2 := &fake.Controller{}
+				drw2 := internalhook.NewDependentResourceWatcher(c2, rm, rc, sch)
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(drw2.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c2.WatchCalls).To(HaveLen(2))
+
+				drw.Forget(ctx, owner, log)
+				Expect(rc.removed).To(BeEmpty())
+				drw2.Forget(ctx, owner, log)
+				Expect(rc.removed).To(HaveLen(2))
+			})
+		})
+
+		Context("with remote clusters", func() {
+			var (
+				rc     *helmclient.RemoteClusters
+				cl     client.Client
+				secret *corev1.Secret
+			)
+
+			kubeConfig := func(token string) []byte {
+				config := clientcmdapi.NewConfig()
+				config.Clusters["spoke"] = &clientcmdapi.Cluster{Server: "https://spoke.example.com:6443"}
+				config.AuthInfos["operator"] = &clientcmdapi.AuthInfo{Token: token}
+				config.Contexts["spoke"] = &clientcmdapi.Context{Cluster: "spoke", AuthInfo: "operator"}
+				config.CurrentContext = "spoke"
+				data, err := clientcmd.Write(*config)
+				Expect(err).NotTo(HaveOccurred())
+				return data
+			}
+
+			BeforeEach(func() {
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+				owner = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"metadata": map[string]interface{}{
+							"name":      "testDeployment",
+							"namespace": "ownerNamespace",
+							"uid":       "owner-uid",
+						},
+						"spec": map[string]interface{}{
+							"kubeConfigSecretRef": map[string]interface{}{"name": "spoke"},
+						},
+					},
+				}
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ownerNamespace", Name: "spoke"},
+					Data:       map[string][]byte{"kubeconfig": kubeConfig("token")},
+				}
+				cl = fakeclient.NewClientBuilder().WithObjects(secret).Build()
+				rc = helmclient.NewRemoteClusters(cl)
+
+				remoteCtx, cancel := context.WithCancel(ctx)
+				DeferCleanup(cancel)
+				go func() {
+					defer GinkgoRecover()
+					Expect(rc.Start(remoteCtx)).To(Succeed())
+				}()
+				Eventually(func() error {
+					cluster, err := rc.ClusterFor(ctx, owner, nil)
+					if err != nil {
+						return err
+					}
+					_, err = cluster.Cache()
+					return err
+				}).Should(Succeed())
+
+				rel = &release.Release{
+					Manifest: strings.Join([]string{rsOwnerNamespace, clusterRole}, "---\n"),
+				}
+				drw = internalhook.NewDependentResourceWatcher(c, rm, cache, sch, internalhook.WithRemoteClusters(rc))
+			})
+
+			It("should watch all resources with annotation handler", func() {
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(2))
+				Expect(validateSourceHandlerType(c.WatchCalls[0].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
+				Expect(validateSourceHandlerType(c.WatchCalls[1].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
+
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(2))
+			})
+
+			It("should watch resources again when the kubeconfig rotates", func() {
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(2))
+
+				secret.Data["kubeconfig"] = kubeConfig("rotated-token")
+				Expect(cl.Update(ctx, secret)).To(Succeed())
+				_, err := rc.ClusterFor(ctx, owner, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(4))
+			})
+
+			It("should watch resources of owners in the local cluster with the local cache", func() {
+				unstructured.RemoveNestedField(owner.Object, "spec", "kubeConfigSecretRef")
+				_, err := rc.ClusterFor(ctx, owner, nil)
+				Expect(err).NotTo(HaveOccurred())
+				rm.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, meta.RESTScopeNamespace)
+				rm.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+
+				Expect(drw.Exec(ctx, owner, *rel, log)).To(Succeed())
+				Expect(c.WatchCalls).To(HaveLen(2))
+				Expect(validateSourceHandlerType(c.WatchCalls[0].Source, handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](sch, rm, owner, handler.OnlyControllerOwner()))).To(Succeed())
+				Expect(validateSourceHandlerType(c.WatchCalls[1].Source, &sdkhandler.EnqueueRequestForAnnotation[*unstructured.Unstructured]{})).To(Succeed())
+			})
+		})
 	})
 })
 
@@ -276,6 +572,36 @@ var _ = Describe("validateSourceHandlerType", func() {
 	})
 })
 
+// removeRecordingCache records the kinds of the informers that are removed
+// from it.
+type removeRecordingCache struct {
+	cache.Cache
+	removed []schema.GroupVersionKind
+}
+
+func (c *removeRecordingCache) RemoveInformer(_ context.Context, obj client.Object) error {
+	c.removed = append(c.removed, obj.GetObjectKind().GroupVersionKind())
+	return nil
+}
+
+// recordingQueue records the requests that are added to it.
+type recordingQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	added   []reconcile.Request
+	delayed map[reconcile.Request]time.Duration
+}
+
+func (q *recordingQueue) Add(req reconcile.Request) {
+	q.added = append(q.added, req)
+}
+
+func (q *recordingQueue) AddAfter(req reconcile.Request, d time.Duration) {
+	if q.delayed == nil {
+		q.delayed = make(map[reconcile.Request]time.Duration)
+	}
+	q.delayed[req] = d
+}
+
 // validateSourceHandlerType takes in a source.Source and uses reflection to determine
 // if the handler used by the source matches the expected type.
 // It is assumed that the source.Source was created via the source.Kind() function.
@@ -18,12 +18,17 @@ package updater
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
 
 	"helm.sh/helm/v3/pkg/release"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,15 +37,45 @@ import (
 	"github.com/operator-framework/helm-operator-plugins/pkg/internal/status"
 )
 
-func New(client client.Client) Updater {
-	return Updater{
-		client: client,
+// Strategy is the way in which an Updater writes the changes of an object.
+type Strategy string
+
+const (
+	// StrategyUpdate writes the object and its status with updates, which
+	// fail with a Conflict if the object was changed concurrently.
+	StrategyUpdate Strategy = "Update"
+	// StrategyPatch writes the status fields of the operator with a merge
+	// patch and the finalizers with a JSON patch that only adds or removes
+	// the changed finalizers, so that concurrent changes of other fields of
+	// the object do not cause Conflicts and are not overwritten.
+	StrategyPatch Strategy = "Patch"
+)
+
+type Option func(*Updater)
+
+// WithStrategy sets the Strategy of the Updater, which defaults to
+// StrategyUpdate.
+func WithStrategy(s Strategy) Option {
+	return func(u *Updater) {
+		u.strategy = s
+	}
+}
+
+func New(client client.Client, opts ...Option) Updater {
+	u := Updater{
+		client:   client,
+		strategy: StrategyUpdate,
+	}
+	for _, o := range opts {
+		o(&u)
 	}
+	return u
 }
 
 type Updater struct {
 	isCanceled        bool
 	client            client.Client
+	strategy          Strategy
 	updateFuncs       []UpdateFunc
 	updateStatusFuncs []UpdateStatusFunc
 }
@@ -84,6 +119,10 @@ func (u *Updater) Apply(ctx context.Context, obj *unstructured.Unstructured) err
 
 	backoff := retry.DefaultRetry
 
+	if u.strategy == StrategyPatch {
+		return u.applyPatches(ctx, obj, backoff)
+	}
+
 	st := statusFor(obj)
 	needsStatusUpdate := false
 	for _, f := range u.updateStatusFuncs {
@@ -121,6 +160,201 @@ func (u *Updater) Apply(ctx context.Context, obj *unstructured.Unstructured) err
 	return nil
 }
 
+// applyPatches applies the updates with patches. Since patches do not contain
+// the resourceVersion of obj, they do not fail with Conflicts.
+func (u *Updater) applyPatches(ctx context.Context, obj *unstructured.Unstructured, backoff wait.Backoff) error {
+	st := statusFor(obj)
+	needsStatusUpdate := false
+	for _, f := range u.updateStatusFuncs {
+		needsStatusUpdate = f(st) || needsStatusUpdate
+	}
+
+	// As with updates, patch the status first, since the object might be
+	// garbage-collected once the finalizer is removed.
+	if needsStatusUpdate {
+		uSt, err := runtime.DefaultUnstructuredConverter.ToUnstructured(st)
+		if err != nil {
+			return err
+		}
+		patch, err := statusPatch(obj, uSt)
+		if err != nil {
+			return err
+		}
+		setOwnedStatusFields(obj, uSt)
+
+		if err := retryOnRetryableUpdateError(backoff, func() error {
+			return u.client.Status().Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+		}); err != nil {
+			return err
+		}
+	}
+
+	before := obj.DeepCopy()
+	needsUpdate := false
+	for _, f := range u.updateFuncs {
+		needsUpdate = f(obj) || needsUpdate
+	}
+	if !needsUpdate {
+		return nil
+	}
+
+	// Patch the finalizers separately, so that the patch of the other
+	// fields does not replace the finalizers set concurrently by others.
+	finalizers := obj.GetFinalizers()
+	obj.SetFinalizers(before.GetFinalizers())
+	patch := client.MergeFrom(before)
+	if data, err := patch.Data(obj); err != nil {
+		return err
+	} else if string(data) != "{}" {
+		if err := retryOnRetryableUpdateError(backoff, func() error {
+			return u.client.Patch(ctx, obj, patch)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// The operations of the finalizer patch depend on the current
+	// finalizers, so they are computed anew from the current object
+	// whenever the patch is retried, e.g. because its tests failed after a
+	// concurrent change of the finalizers.
+	added, removed := finalizerChanges(before.GetFinalizers(), finalizers)
+	retried := false
+	return retryOnRetryableUpdateError(backoff, func() error {
+		if retried {
+			if err := u.client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		retried = true
+
+		current := obj.GetFinalizers()
+		ops := finalizerPatchOps(current, applyFinalizerChanges(current, added, removed))
+		if len(ops) == 0 {
+			return nil
+		}
+		data, err := json.Marshal(ops)
+		if err != nil {
+			return err
+		}
+		return u.client.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, data))
+	})
+}
+
+// ownedStatusFields are the fields of the status that are written by the
+// Updater. Other fields of the status, e.g. those set by other controllers,
+// are never patched.
+var ownedStatusFields = []string{"conditions", "deployedRelease", "releaseFingerprint", "custom"}
+
+// statusPatch returns a merge patch that changes the owned fields of the
+// status of obj to those of st.
+func statusPatch(obj *unstructured.Unstructured, st map[string]interface{}) ([]byte, error) {
+	current, _, _ := unstructured.NestedMap(obj.Object, "status")
+	before, after := map[string]interface{}{}, map[string]interface{}{}
+	for _, f := range ownedStatusFields {
+		if v, ok := current[f]; ok {
+			before[f] = v
+		}
+		if v, ok := st[f]; ok {
+			after[f] = v
+		}
+	}
+	return client.MergeFrom(&unstructured.Unstructured{Object: map[string]interface{}{"status": before}}).
+		Data(&unstructured.Unstructured{Object: map[string]interface{}{"status": after}})
+}
+
+// setOwnedStatusFields sets the owned fields of the status of obj to those of
+// st, and keeps its other fields.
+func setOwnedStatusFields(obj *unstructured.Unstructured, st map[string]interface{}) {
+	current, _, _ := unstructured.NestedMap(obj.Object, "status")
+	if current == nil {
+		current = map[string]interface{}{}
+	}
+	for _, f := range ownedStatusFields {
+		if v, ok := st[f]; ok {
+			current[f] = v
+		} else {
+			delete(current, f)
+		}
+	}
+	obj.Object["status"] = current
+}
+
+// finalizerChanges returns the finalizers that were added to and removed
+// from before.
+func finalizerChanges(before, after []string) (added, removed []string) {
+	for _, f := range after {
+		if !slices.Contains(before, f) {
+			added = append(added, f)
+		}
+	}
+	for _, f := range before {
+		if !slices.Contains(after, f) {
+			removed = append(removed, f)
+		}
+	}
+	return added, removed
+}
+
+// applyFinalizerChanges returns the finalizers with the added finalizers
+// appended and the removed finalizers removed.
+func applyFinalizerChanges(finalizers, added, removed []string) []string {
+	out := make([]string, 0, len(finalizers)+len(added))
+	for _, f := range finalizers {
+		if !slices.Contains(removed, f) {
+			out = append(out, f)
+		}
+	}
+	for _, f := range added {
+		if !slices.Contains(out, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// finalizerPatchOps returns the JSON patch operations that change the
+// finalizers from before to after. Removals test that the finalizer is still
+// at its index, so that they fail rather than remove another finalizer if
+// the finalizers were changed concurrently.
+func finalizerPatchOps(before, after []string) []jsonPatchOp {
+	var ops []jsonPatchOp
+	for i := len(before) - 1; i >= 0; i-- {
+		if !slices.Contains(after, before[i]) {
+			path := fmt.Sprintf("/metadata/finalizers/%d", i)
+			ops = append(ops, jsonPatchOp{Op: "test", Path: path, Value: before[i]}, jsonPatchOp{Op: "remove", Path: path})
+		}
+	}
+	remaining := len(before) - len(ops)/2
+	for _, f := range after {
+		if slices.Contains(before, f) {
+			continue
+		}
+		if remaining == 0 {
+			ops = append(ops, jsonPatchOp{Op: "add", Path: "/metadata/finalizers", Value: []string{f}})
+		} else {
+			ops = append(ops, jsonPatchOp{Op: "add", Path: "/metadata/finalizers/-", Value: f})
+		}
+		remaining++
+	}
+	return ops
+}
+
+func AddFinalizer(finalizer string) UpdateFunc {
+	return func(obj *unstructured.Unstructured) bool {
+		if controllerutil.ContainsFinalizer(obj, finalizer) {
+			return false
+		}
+		controllerutil.AddFinalizer(obj, finalizer)
+		return true
+	}
+}
+
 func RemoveFinalizer(finalizer string) UpdateFunc {
 	return func(obj *unstructured.Unstructured) bool {
 		if !controllerutil.ContainsFinalizer(obj, finalizer) {
@@ -165,9 +399,63 @@ func RemoveDeployedRelease() UpdateStatusFunc {
 	return EnsureDeployedRelease(nil)
 }
 
+// EnsureReleaseFingerprint records that the release with the given version
+// was deployed from the inputs with the given fingerprint.
+func EnsureReleaseFingerprint(fingerprint string, version int) UpdateStatusFunc {
+	return func(status *helmAppStatus) bool {
+		newFingerprint := &helmAppReleaseFingerprint{Inputs: fingerprint, Version: version}
+		if status.ReleaseFingerprint != nil && *status.ReleaseFingerprint == *newFingerprint {
+			return false
+		}
+		status.ReleaseFingerprint = newFingerprint
+		return true
+	}
+}
+
+// RemoveReleaseFingerprint removes the fingerprint of the inputs of the
+// release.
+func RemoveReleaseFingerprint() UpdateStatusFunc {
+	return func(status *helmAppStatus) bool {
+		if status.ReleaseFingerprint == nil {
+			return false
+		}
+		status.ReleaseFingerprint = nil
+		return true
+	}
+}
+
+// EnsureCustomField sets the field at the given path under status.custom to
+// value, which must be a JSON value as produced by unstructured decoding.
+func EnsureCustomField(value interface{}, fields ...string) UpdateStatusFunc {
+	return func(status *helmAppStatus) bool {
+		if current, ok, _ := unstructured.NestedFieldNoCopy(status.Custom, fields...); ok && equality.Semantic.DeepEqual(current, value) {
+			return false
+		}
+		if status.Custom == nil {
+			status.Custom = map[string]interface{}{}
+		}
+		return unstructured.SetNestedField(status.Custom, runtime.DeepCopyJSONValue(value), fields...) == nil
+	}
+}
+
+// RemoveCustomField removes the field at the given path under status.custom.
+func RemoveCustomField(fields ...string) UpdateStatusFunc {
+	return func(status *helmAppStatus) bool {
+		if _, ok, _ := unstructured.NestedFieldNoCopy(status.Custom, fields...); !ok {
+			return false
+		}
+		unstructured.RemoveNestedField(status.Custom, fields...)
+		return true
+	}
+}
+
 type helmAppStatus struct {
 	Conditions      status.Conditions `json:"conditions"`
 	DeployedRelease *helmAppRelease   `json:"deployedRelease,omitempty"`
+	// ReleaseFingerprint identifies the inputs of the deployed release.
+	ReleaseFingerprint *helmAppReleaseFingerprint `json:"releaseFingerprint,omitempty"`
+	// Custom holds the fields that are set by hooks.
+	Custom map[string]interface{} `json:"custom,omitempty"`
 }
 
 type helmAppRelease struct {
@@ -175,6 +463,11 @@ type helmAppRelease struct {
 	Manifest string `json:"manifest,omitempty"`
 }
 
+type helmAppReleaseFingerprint struct {
+	Inputs  string `json:"inputs"`
+	Version int    `json:"version"`
+}
+
 func statusFor(obj *unstructured.Unstructured) *helmAppStatus {
 	if obj == nil || obj.Object == nil {
 		return nil
@@ -111,6 +111,127 @@ var _ = Describe("Updater", func() {
 	})
 })
 
+var _ = Describe("Updater with StrategyPatch", func() {
+	var (
+		cl  client.Client
+		obj *unstructured.Unstructured
+		key = types.NamespacedName{Namespace: "testNamespace", Name: "testDeployment"}
+	)
+
+	BeforeEach(func() {
+		cl = fake.NewClientBuilder().Build()
+		obj = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":       "testDeployment",
+				"namespace":  "testNamespace",
+				"finalizers": []interface{}{"other", testFinalizer},
+			},
+			"spec": map[string]interface{}{},
+		}}
+		Expect(cl.Create(context.TODO(), obj)).To(Succeed())
+
+		By("changing the object concurrently", func() {
+			concurrent := obj.DeepCopy()
+			concurrent.SetLabels(map[string]string{"foo": "bar"})
+			Expect(cl.Update(context.TODO(), concurrent)).To(Succeed())
+		})
+	})
+
+	It("should patch the status and finalizers without conflicts", func() {
+		u := New(cl, WithStrategy(StrategyPatch))
+		u.UpdateStatus(EnsureCondition(conditions.Deployed(corev1.ConditionTrue, "", "")))
+		u.Update(RemoveFinalizer(testFinalizer))
+		Expect(u.Apply(context.TODO(), obj)).To(Succeed())
+
+		Expect(cl.Get(context.TODO(), key, obj)).To(Succeed())
+		Expect((obj.Object["status"].(map[string]interface{}))["conditions"]).To(HaveLen(1))
+		Expect(obj.GetFinalizers()).To(Equal([]string{"other"}))
+		Expect(obj.GetLabels()).To(Equal(map[string]string{"foo": "bar"}))
+	})
+
+	It("should fail with a conflict when updating", func() {
+		u := New(cl)
+		u.Update(RemoveFinalizer(testFinalizer))
+		Expect(apierrors.IsConflict(u.Apply(context.TODO(), obj))).To(BeTrue())
+	})
+
+	It("should add a finalizer", func() {
+		u := New(cl, WithStrategy(StrategyPatch))
+		u.Update(AddFinalizer("new"))
+		Expect(u.Apply(context.TODO(), obj)).To(Succeed())
+
+		Expect(cl.Get(context.TODO(), key, obj)).To(Succeed())
+		Expect(obj.GetFinalizers()).To(Equal([]string{"other", testFinalizer, "new"}))
+	})
+
+	It("should keep the status fields it does not own", func() {
+		Expect(cl.Get(context.TODO(), key, obj)).To(Succeed())
+		Expect(unstructured.SetNestedField(obj.Object, int64(3), "status", "replicas")).To(Succeed())
+		Expect(cl.Status().Update(context.TODO(), obj)).To(Succeed())
+
+		u := New(cl, WithStrategy(StrategyPatch))
+		u.UpdateStatus(EnsureCondition(conditions.Deployed(corev1.ConditionTrue, "", "")))
+		Expect(u.Apply(context.TODO(), obj)).To(Succeed())
+
+		Expect(cl.Get(context.TODO(), key, obj)).To(Succeed())
+		Expect((obj.Object["status"].(map[string]interface{}))["conditions"]).To(HaveLen(1))
+		replicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas")
+		Expect(replicas).To(Equal(int64(3)))
+	})
+
+	It("should patch the finalizers of the current object after a concurrent change of the finalizers", func() {
+		By("removing a finalizer concurrently", func() {
+			concurrent := &unstructured.Unstructured{}
+			concurrent.SetGroupVersionKind(obj.GroupVersionKind())
+			Expect(cl.Get(context.TODO(), key, concurrent)).To(Succeed())
+			concurrent.SetFinalizers([]string{testFinalizer})
+			Expect(cl.Update(context.TODO(), concurrent)).To(Succeed())
+		})
+
+		u := New(cl, WithStrategy(StrategyPatch))
+		u.Update(RemoveFinalizer(testFinalizer), AddFinalizer("new"))
+		Expect(u.Apply(context.TODO(), obj)).To(Succeed())
+
+		Expect(cl.Get(context.TODO(), key, obj)).To(Succeed())
+		Expect(obj.GetFinalizers()).To(Equal([]string{"new"}))
+	})
+})
+
+var _ = Describe("finalizerPatchOps", func() {
+	It("should test and remove finalizers by index", func() {
+		Expect(finalizerPatchOps([]string{"a", "b", "c"}, []string{"b"})).To(Equal([]jsonPatchOp{
+			{Op: "test", Path: "/metadata/finalizers/2", Value: "c"},
+			{Op: "remove", Path: "/metadata/finalizers/2"},
+			{Op: "test", Path: "/metadata/finalizers/0", Value: "a"},
+			{Op: "remove", Path: "/metadata/finalizers/0"},
+		}))
+	})
+
+	It("should append finalizers", func() {
+		Expect(finalizerPatchOps([]string{"a"}, []string{"a", "b"})).To(Equal([]jsonPatchOp{
+			{Op: "add", Path: "/metadata/finalizers/-", Value: "b"},
+		}))
+	})
+
+	It("should add the finalizers if there are none", func() {
+		Expect(finalizerPatchOps(nil, []string{"a", "b"})).To(Equal([]jsonPatchOp{
+			{Op: "add", Path: "/metadata/finalizers", Value: []string{"a"}},
+			{Op: "add", Path: "/metadata/finalizers/-", Value: "b"},
+		}))
+	})
+})
+
+var _ = Describe("AddFinalizer", func() {
+	It("should add the finalizer if not present", func() {
+		obj := &unstructured.Unstructured{}
+		Expect(AddFinalizer(testFinalizer)(obj)).To(BeTrue())
+		Expect(obj.GetFinalizers()).To(Equal([]string{testFinalizer}))
+		Expect(AddFinalizer(testFinalizer)(obj)).To(BeFalse())
+	})
+})
+
 var _ = Describe("RemoveFinalizer", func() {
 	var obj *unstructured.Unstructured
 
@@ -214,6 +335,79 @@ var _ = Describe("RemoveDeployedRelease", func() {
 	})
 })
 
+var _ = Describe("EnsureReleaseFingerprint", func() {
+	var obj *helmAppStatus
+
+	BeforeEach(func() {
+		obj = &helmAppStatus{}
+	})
+
+	It("should set the fingerprint if not present", func() {
+		Expect(EnsureReleaseFingerprint("abc", 1)(obj)).To(BeTrue())
+		Expect(obj.ReleaseFingerprint).To(Equal(&helmAppReleaseFingerprint{Inputs: "abc", Version: 1}))
+	})
+
+	It("should not update an identical fingerprint", func() {
+		obj.ReleaseFingerprint = &helmAppReleaseFingerprint{Inputs: "abc", Version: 1}
+		Expect(EnsureReleaseFingerprint("abc", 1)(obj)).To(BeFalse())
+	})
+
+	It("should update the fingerprint of a different version", func() {
+		obj.ReleaseFingerprint = &helmAppReleaseFingerprint{Inputs: "abc", Version: 1}
+		Expect(EnsureReleaseFingerprint("abc", 2)(obj)).To(BeTrue())
+		Expect(obj.ReleaseFingerprint).To(Equal(&helmAppReleaseFingerprint{Inputs: "abc", Version: 2}))
+	})
+})
+
+var _ = Describe("RemoveReleaseFingerprint", func() {
+	It("should remove the fingerprint if present", func() {
+		obj := &helmAppStatus{ReleaseFingerprint: &helmAppReleaseFingerprint{Inputs: "abc", Version: 1}}
+		Expect(RemoveReleaseFingerprint()(obj)).To(BeTrue())
+		Expect(obj.ReleaseFingerprint).To(BeNil())
+	})
+
+	It("should not update if the fingerprint is not present", func() {
+		Expect(RemoveReleaseFingerprint()(&helmAppStatus{})).To(BeFalse())
+	})
+})
+
+var _ = Describe("EnsureCustomField", func() {
+	var obj *helmAppStatus
+
+	BeforeEach(func() {
+		obj = &helmAppStatus{}
+	})
+
+	It("should set the field if not present", func() {
+		Expect(EnsureCustomField("https://example.com", "endpoints", "web")(obj)).To(BeTrue())
+		Expect(obj.Custom).To(Equal(map[string]interface{}{"endpoints": map[string]interface{}{"web": "https://example.com"}}))
+	})
+
+	It("should not update an identical field", func() {
+		obj.Custom = map[string]interface{}{"version": "1.2.3"}
+		Expect(EnsureCustomField("1.2.3", "version")(obj)).To(BeFalse())
+	})
+
+	It("should update a different field", func() {
+		obj.Custom = map[string]interface{}{"version": "1.2.3"}
+		Expect(EnsureCustomField("1.2.4", "version")(obj)).To(BeTrue())
+		Expect(obj.Custom).To(Equal(map[string]interface{}{"version": "1.2.4"}))
+	})
+})
+
+var _ = Describe("RemoveCustomField", func() {
+	It("should remove the field if present", func() {
+		obj := &helmAppStatus{Custom: map[string]interface{}{"version": "1.2.3", "other": true}}
+		Expect(RemoveCustomField("version")(obj)).To(BeTrue())
+		Expect(obj.Custom).To(Equal(map[string]interface{}{"other": true}))
+	})
+
+	It("should not update if the field is not present", func() {
+		obj := &helmAppStatus{}
+		Expect(RemoveCustomField("version")(obj)).To(BeFalse())
+	})
+})
+
 var _ = Describe("statusFor", func() {
 	var obj *unstructured.Unstructured
 
@@ -18,8 +18,10 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 	"sync"
 	"time"
@@ -35,14 +37,22 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	sdkhandler "github.com/operator-framework/operator-lib/handler"
@@ -50,12 +60,17 @@ import (
 	"github.com/operator-framework/helm-operator-plugins/internal/sdk/controllerutil"
 	"github.com/operator-framework/helm-operator-plugins/pkg/annotation"
 	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
 	"github.com/operator-framework/helm-operator-plugins/pkg/hook"
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/status"
+	"github.com/operator-framework/helm-operator-plugins/pkg/maintenance"
 	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/conditions"
 	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/diff"
 	internalhook "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/hook"
 	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/updater"
 	internalvalues "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/values"
+	"github.com/operator-framework/helm-operator-plugins/pkg/sharding"
+	"github.com/operator-framework/helm-operator-plugins/pkg/statusmapping"
 	"github.com/operator-framework/helm-operator-plugins/pkg/values"
 )
 
@@ -68,8 +83,21 @@ type Reconciler struct {
 	valueTranslator    values.Translator
 	valueMapper        values.Mapper // nolint:staticcheck
 	eventRecorder      record.EventRecorder
-	preHooks           []hook.PreHook
-	postHooks          []hook.PostHook
+	preHooks           []hook.PreHookV2
+	postHooks          []hook.PostHookV2
+
+	statusMapper       *statusmapping.Mapper
+	updateStrategy     UpdateStrategy
+	preUninstallHooks  []hook.UninstallHook
+	postUninstallHooks []hook.UninstallHook
+
+	metadataOnlyDependentWatches bool
+	dependentEventFilter         *eventfilter.Filter
+	dependentResourceLabel       labels.Set
+	dependentResourceCache       cache.Cache
+	shardCache                   cache.Cache
+	unassignedCache              cache.Cache
+	dependentResourceWatcher     internalhook.DependentResourceWatcher
 
 	log                              logr.Logger
 	gvk                              *schema.GroupVersionKind
@@ -83,7 +111,21 @@ type Reconciler struct {
 	maxReleaseHistory                *int
 	skipPrimaryGVKSchemeRegistration bool
 	controllerSetupFuncs             []ControllerSetupFunc
-	pauseHandler                     PauseReconcileHandlerFunc
+	pauseHandler                     PauseModeHandlerFunc
+	upgradeScheduleHandler           UpgradeScheduleHandlerFunc
+	validatingWebhook                bool
+	defaultingWebhook                bool
+	defaultingWebhookPaths           []string
+	remoteClusters                   *helmclient.RemoteClusters
+	impersonation                    *helmclient.ImpersonationOpts
+	storageDriverMapper              helmclient.ObjectToStorageDriverMapper
+	sharder                          *sharding.Sharder
+	dryRunFullCheckPeriod            time.Duration
+	postRendererConfig               []string
+
+	chartDigestOnce sync.Once
+	chartDigest     string
+	dryRunChecks    dryRunChecks
 
 	annotSetupOnce       sync.Once
 	annotations          map[string]struct{}
@@ -144,7 +186,11 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		r.setupScheme(mgr)
 	}
 
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: r.maxConcurrentReconciles})
+	controllerOpts := controller.Options{Reconciler: r, MaxConcurrentReconciles: r.maxConcurrentReconciles}
+	if r.sharder != nil {
+		controllerOpts.NewQueue = r.sharder.NewQueue
+	}
+	c, err := controller.New(controllerName, mgr, controllerOpts)
 	if err != nil {
 		return err
 	}
@@ -160,6 +206,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}
 	}
 
+	if err := r.setupWebhooks(mgr); err != nil {
+		return err
+	}
+
 	r.log.Info("Watching resource",
 		"group", r.gvk.Group,
 		"version", r.gvk.Version,
@@ -193,6 +243,94 @@ func WithActionClientGetter(actionClientGetter helmclient.ActionClientGetter) Op
 	}
 }
 
+// WithRemoteClusters is an Option that configures the Reconciler to install
+// the releases of custom resources that reference a kubeconfig Secret in the
+// remote clusters of these kubeconfigs, e.g. for a hub operator that deploys
+// to spoke clusters. See helmclient.RemoteClusters for details.
+//
+// SetupWithManager adds rc to the manager, watches the dependent resources in
+// remote clusters with their caches and reconciles the custom resources when
+// their kubeconfig Secret changes. The reference to the kubeconfig Secret is
+// removed from the values if it is in the spec.
+//
+// If the Reconciler is configured with WithActionClientGetter, the
+// ActionConfigGetter of the ActionClientGetter must be configured with
+// helmclient.ClientClusterMapper(rc.ClusterFor).
+func WithRemoteClusters(rc *helmclient.RemoteClusters) Option {
+	return func(r *Reconciler) error {
+		r.remoteClusters = rc
+		return nil
+	}
+}
+
+// WithImpersonation is an Option that configures the Reconciler to run Helm
+// actions impersonating a ServiceAccount that is chosen by the custom
+// resource, so that custom resources can only deploy what the RBAC of their
+// ServiceAccount allows. See helmclient.ImpersonateServiceAccount for details.
+// The name of the ServiceAccount is removed from the values if it is in the
+// spec.
+//
+// If the Reconciler is configured with WithActionClientGetter, the
+// ActionConfigGetter of the ActionClientGetter must be configured with
+// helmclient.ClientRestConfigMapper(helmclient.ImpersonateServiceAccount(opts)).
+func WithImpersonation(opts helmclient.ImpersonationOpts) Option {
+	return func(r *Reconciler) error {
+		for _, pattern := range opts.AllowedServiceAccounts {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid allowed service account pattern %q: %w", pattern, err)
+			}
+		}
+		if len(opts.ServiceAccountPath) == 0 {
+			opts.ServiceAccountPath = helmclient.DefaultServiceAccountPath
+		}
+		r.impersonation = &opts
+		return nil
+	}
+}
+
+// WithStorageDriver is an Option that configures the storage driver in which
+// the Reconciler stores releases, e.g. helmclient.ChunkedSecretsStorageDriver.
+// Releases are stored in Secrets by default.
+//
+// It is ignored if the Reconciler is configured with WithActionClientGetter.
+func WithStorageDriver(mapper helmclient.ObjectToStorageDriverMapper) Option {
+	return func(r *Reconciler) error {
+		r.storageDriverMapper = mapper
+		return nil
+	}
+}
+
+// WithSharder is an Option that configures the Reconciler to only reconcile
+// the custom resources that are assigned to this replica by s, so that the
+// custom resources are reconciled by all replicas of the operator instead of
+// a single leader. The events of all watches, including the watches of
+// dependent resources, are filtered to the shard of the replica, and the
+// custom resources that move to the replica when replicas join or leave are
+// reconciled.
+//
+// The Reconciler labels the custom resources of its shard and all objects of
+// their releases with the labels of s, and watches them with caches that only
+// contain objects with these labels, and the metadata of the custom resources
+// that are not assigned to a shard yet. Like the cache of
+// WithDependentResourceLabel, these caches watch all namespaces. The
+// dependent resources of a custom resource that moved to this replica are
+// relabeled by the next upgrade of its release. Custom resources are locked
+// with s before they are reconciled, so that a replica that has not noticed
+// yet that a custom resource moved does not act on it at the same time.
+//
+// If WithActionClientGetter is used as well, its post-renderers must add the
+// labels of s, e.g. with helmclient.LabelPostRendererFunc.
+//
+// SetupWithManager adds s to the manager, which must not use leader election.
+// The Reconciler uses its own work queue, so it must not be combined with a
+// ControllerSetupFunc that replaces the queue of the controller.
+func WithSharder(s *sharding.Sharder) Option {
+	return func(r *Reconciler) error {
+		r.sharder = s
+		return nil
+	}
+}
+
 // WithEventRecorder is an Option that configures a Reconciler's EventRecorder.
 //
 // By default, manager.GetEventRecorderFor() is used if this option is not
@@ -275,6 +413,64 @@ func SkipDependentWatches(skip bool) Option {
 	}
 }
 
+// WithMetadataOnlyDependentWatches is an Option that configures whether the
+// Reconciler watches dependent objects with metadata-only informers, which
+// only cache the metadata of the objects instead of the full objects. Since
+// updates of objects without a generation, e.g. ConfigMaps, cannot be told
+// apart from updates of their status then, every update of such an object
+// triggers a reconciliation.
+//
+// Kinds whose values are mapped into the status with WithStatusMappings are
+// still watched with informers of full objects.
+func WithMetadataOnlyDependentWatches(metadataOnly bool) Option {
+	return func(r *Reconciler) error {
+		r.metadataOnlyDependentWatches = metadataOnly
+		return nil
+	}
+}
+
+// WithDependentEventFilter is an Option that configures which events of
+// dependent objects trigger a reconciliation, e.g. to ignore changes of
+// fields that other controllers make, such as spec.replicas of Deployments
+// that are scaled by a HorizontalPodAutoscaler, to reconcile when dependent
+// objects are created, or to debounce bursts of updates.
+//
+// By default, creations are ignored, deletions trigger a reconciliation, and
+// updates trigger a reconciliation if they change anything but the status.
+func WithDependentEventFilter(c eventfilter.Config) Option {
+	return func(r *Reconciler) error {
+		f, err := eventfilter.New(c)
+		if err != nil {
+			return err
+		}
+		r.dependentEventFilter = f
+		return nil
+	}
+}
+
+// WithDependentResourceLabel is an Option that configures the Reconciler to
+// set the label key=value on all objects of its releases and to watch
+// dependent objects with a cache that only contains objects with this label,
+// instead of with the cache of the manager, which contains all objects of the
+// watched kinds. The cache watches all namespaces. Objects of releases that
+// were installed without the label get it with their next upgrade.
+//
+// If WithActionClientGetter is used as well, its post-renderers must add the
+// label, e.g. with helmclient.LabelPostRendererFunc. Dependent objects of
+// releases in remote clusters are watched with the caches of these clusters.
+func WithDependentResourceLabel(key, value string) Option {
+	return func(r *Reconciler) error {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid dependent resource label key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid dependent resource label value %q: %s", value, strings.Join(errs, ", "))
+		}
+		r.dependentResourceLabel = labels.Set{key: value}
+		return nil
+	}
+}
+
 // SkipPrimaryGVKSchemeRegistration is an Option that allows to disable the default behaviour of
 // registering unstructured.Unstructured as underlying type for the GVK scheme.
 //
@@ -444,11 +640,46 @@ func WithUninstallAnnotations(as ...annotation.Uninstall) Option {
 // for a given custom resource
 type PauseReconcileHandlerFunc func(ctx context.Context, obj *unstructured.Unstructured) (bool, error)
 
+// PauseMode is the part of the reconciliation of a custom resource that is paused.
+type PauseMode string
+
+const (
+	// PauseNone does not pause reconciliation.
+	PauseNone PauseMode = ""
+	// PauseAll pauses reconciliation entirely, including uninstalls.
+	PauseAll PauseMode = "All"
+	// PauseUpgrades pauses upgrades of the release, while it is still
+	// installed, corrected for drift and uninstalled.
+	PauseUpgrades PauseMode = "Upgrades"
+	// PauseDriftCorrection pauses the correction of drift of the resources
+	// of the release, while it is still installed, upgraded and uninstalled.
+	PauseDriftCorrection PauseMode = "DriftCorrection"
+)
+
+// PauseModeHandlerFunc defines a function type that determines which part of the reconciliation of a given custom
+// resource should be paused.
+type PauseModeHandlerFunc func(ctx context.Context, obj *unstructured.Unstructured) (PauseMode, error)
+
 // WithPauseReconcileHandler is an Option that sets a PauseReconcile handler, which is a function that
 // determines whether reconciliation should be paused for the custom resource watched by this reconciler.
 //
 // Example usage: WithPauseReconcileHandler(PauseReconcileIfAnnotationTrue("my.domain/pause-reconcile"))
 func WithPauseReconcileHandler(handler PauseReconcileHandlerFunc) Option {
+	return WithPauseModeHandler(func(ctx context.Context, obj *unstructured.Unstructured) (PauseMode, error) {
+		paused, err := handler(ctx, obj)
+		if paused {
+			return PauseAll, err
+		}
+		return PauseNone, err
+	})
+}
+
+// WithPauseModeHandler is an Option that sets a PauseMode handler, which is a function that determines which part
+// of the reconciliation of the custom resource watched by this reconciler should be paused. The mode is reflected
+// in the reason of the Paused condition. It replaces a handler set with WithPauseReconcileHandler.
+//
+// Example usage: WithPauseModeHandler(PauseModeFromAnnotation("my.domain/pause-reconcile"))
+func WithPauseModeHandler(handler PauseModeHandlerFunc) Option {
 	return func(r *Reconciler) error {
 		r.pauseHandler = handler
 		return nil
@@ -467,10 +698,74 @@ func PauseReconcileIfAnnotationTrue(annotationName string) PauseReconcileHandler
 	}
 }
 
+// PauseModeFromAnnotation returns a PauseModeHandlerFunc that reads the pause mode from the given annotation.
+// The values "true" and "All" pause reconciliation entirely, "Upgrades" and "DriftCorrection" pause only
+// that part of it, and any other value, e.g. "false", does not pause reconciliation.
+func PauseModeFromAnnotation(annotationName string) PauseModeHandlerFunc {
+	return func(_ context.Context, obj *unstructured.Unstructured) (PauseMode, error) {
+		switch v := PauseMode(obj.GetAnnotations()[annotationName]); v {
+		case "true", PauseAll:
+			return PauseAll, nil
+		case PauseUpgrades, PauseDriftCorrection:
+			return v, nil
+		}
+		return PauseNone, nil
+	}
+}
+
+// MaintenanceWindowsAnnotation is the annotation of custom resources that
+// declares the maintenance windows of UpgradeInMaintenanceWindows.
+const MaintenanceWindowsAnnotation = "helm.sdk.operatorframework.io/maintenance-windows"
+
+// UpgradeScheduleHandlerFunc defines a function type that determines whether the release of a custom resource may be
+// upgraded at the given time. If it may not, it returns when the upgrade should be retried, or the zero time if that
+// is unknown.
+type UpgradeScheduleHandlerFunc func(ctx context.Context, obj *unstructured.Unstructured, now time.Time) (allowed bool, next time.Time, err error)
+
+// WithUpgradeScheduleHandler is an Option that sets an UpgradeSchedule handler, which is a function that determines
+// when the releases of custom resources may be upgraded. Upgrades that are not allowed are deferred, and the
+// UpgradeDeferred condition tells when they are retried, while installs, the reconciliation of the deployed release
+// and uninstalls continue. If the handler fails, the upgrade is deferred as well.
+//
+// Example usage: WithUpgradeScheduleHandler(UpgradeInMaintenanceWindows(MaintenanceWindowsAnnotation))
+func WithUpgradeScheduleHandler(handler UpgradeScheduleHandlerFunc) Option {
+	return func(r *Reconciler) error {
+		r.upgradeScheduleHandler = handler
+		return nil
+	}
+}
+
+// UpgradeInMaintenanceWindows returns an UpgradeScheduleHandlerFunc that only allows upgrades inside maintenance
+// windows. The windows are read from the given annotation, in the format of maintenance.ParseList, or default to
+// the given windows if the annotation is not present. Upgrades are allowed at any time if there are no windows, e.g.
+// if the annotation is empty.
+func UpgradeInMaintenanceWindows(annotationName string, windows ...maintenance.Window) UpgradeScheduleHandlerFunc {
+	return func(_ context.Context, obj *unstructured.Unstructured, now time.Time) (bool, time.Time, error) {
+		objWindows := windows
+		if v, ok := obj.GetAnnotations()[annotationName]; ok {
+			var err error
+			if objWindows, err = maintenance.ParseList(v); err != nil {
+				return false, time.Time{}, err
+			}
+		}
+		allowed, next := maintenance.Allowed(objWindows, now)
+		return allowed, next, nil
+	}
+}
+
 // WithPreHook is an Option that configures the reconciler to run the given
 // PreHook just before performing any actions (e.g. install, upgrade, uninstall,
 // or reconciliation).
 func WithPreHook(h hook.PreHook) Option {
+	return WithPreHookV2(hook.AdaptPreHook(h))
+}
+
+// WithPreHookV2 is an Option that configures the reconciler to run the given
+// PreHookV2 before determining the state of the release. Hooks run in the
+// order they are configured, each receiving the values returned by the
+// previous ones, and may abort the reconciliation or fail it according to
+// their FailurePolicy.
+func WithPreHookV2(h hook.PreHookV2) Option {
 	return func(r *Reconciler) error {
 		r.preHooks = append(r.preHooks, h)
 		return nil
@@ -480,12 +775,110 @@ func WithPreHook(h hook.PreHook) Option {
 // WithPostHook is an Option that configures the reconciler to run the given
 // PostHook just after performing any non-uninstall release actions.
 func WithPostHook(h hook.PostHook) Option {
+	return WithPostHookV2(hook.AdaptPostHook(h))
+}
+
+// WithPostHookV2 is an Option that configures the reconciler to run the given
+// PostHookV2 just after performing any non-uninstall release actions.
+func WithPostHookV2(h hook.PostHookV2) Option {
 	return func(r *Reconciler) error {
 		r.postHooks = append(r.postHooks, h)
 		return nil
 	}
 }
 
+// UpdateStrategy is the way in which the reconciler writes the finalizer and
+// the status of custom resources.
+type UpdateStrategy string
+
+const (
+	// UpdateStrategyUpdate writes custom resources with updates, which fail
+	// with a Conflict, and restart the reconciliation, if the custom
+	// resource was changed concurrently.
+	UpdateStrategyUpdate UpdateStrategy = "Update"
+	// UpdateStrategyPatch writes only the fields the reconciler owns, i.e.
+	// its status fields and its own finalizer, with patches, which do not
+	// conflict with concurrent changes of other fields.
+	UpdateStrategyPatch UpdateStrategy = "Patch"
+)
+
+// WithUpdateStrategy is an Option that configures how the reconciler writes
+// the finalizer and the status of custom resources. It defaults to
+// UpdateStrategyUpdate.
+func WithUpdateStrategy(s UpdateStrategy) Option {
+	return func(r *Reconciler) error {
+		switch s {
+		case UpdateStrategyUpdate, UpdateStrategyPatch:
+			r.updateStrategy = s
+			return nil
+		}
+		return fmt.Errorf("invalid update strategy %q", s)
+	}
+}
+
+// SkipUnchangedDryRuns is an Option that configures the Reconciler to skip
+// the server-side dry-run upgrade that determines whether a release needs to
+// be upgraded, if the inputs of the release are unchanged. The Reconciler
+// records a fingerprint of the values, the chart, the override values and the
+// configuration of the post-renderers in status.releaseFingerprint of the
+// custom resource together with the version of the release. If both match, the
+// release is considered unchanged. The release of each custom resource is
+// still checked with a dry-run at least once per fullCheckPeriod, e.g. to
+// detect changes of templates that look up objects in the cluster.
+//
+// postRendererConfig identifies the configuration of post-renderers that are
+// added with WithActionClientGetter, e.g. their versions, so that releases
+// are checked again when it changes.
+func SkipUnchangedDryRuns(fullCheckPeriod time.Duration, postRendererConfig ...string) Option {
+	return func(r *Reconciler) error {
+		if fullCheckPeriod <= 0 {
+			return errors.New("dry-run full check period must be positive")
+		}
+		r.dryRunFullCheckPeriod = fullCheckPeriod
+		r.postRendererConfig = postRendererConfig
+		return nil
+	}
+}
+
+// WithStatusMappings is an Option that configures the reconciler to map values
+// of the objects of the release into status.custom of the custom resource
+// after every reconciliation. Unless dependent watches are skipped, changes
+// to the status of these objects also trigger a reconciliation, so that the
+// values stay current.
+func WithStatusMappings(mappings ...statusmapping.Mapping) Option {
+	return func(r *Reconciler) error {
+		m, err := statusmapping.New(mappings...)
+		if err != nil {
+			return err
+		}
+		r.statusMapper = m
+		return nil
+	}
+}
+
+// WithPreUninstallHook is an Option that configures the reconciler to run the
+// given UninstallHook just before uninstalling the release of a custom
+// resource that is being deleted. The hook delays the uninstall if it fails
+// or requests to be requeued.
+func WithPreUninstallHook(h hook.UninstallHook) Option {
+	return func(r *Reconciler) error {
+		r.preUninstallHooks = append(r.preUninstallHooks, h)
+		return nil
+	}
+}
+
+// WithPostUninstallHook is an Option that configures the reconciler to run the
+// given UninstallHook just after uninstalling the release of a custom
+// resource that is being deleted. The hook delays the removal of the
+// finalizer if it fails or requests to be requeued, in which case it runs
+// again, without the release, when the custom resource is requeued.
+func WithPostUninstallHook(h hook.UninstallHook) Option {
+	return func(r *Reconciler) error {
+		r.postUninstallHooks = append(r.postUninstallHooks, h)
+		return nil
+	}
+}
+
 // WithValueTranslator is an Option that configures a function that translates a
 // custom resource to the values passed to Helm.
 // Use this if you need to customize the logic that translates your custom resource to Helm values.
@@ -586,28 +979,61 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(*r.gvk)
+	// The request may have been queued before the custom resource moved to
+	// the shard of another replica.
+	if r.sharder != nil && !r.sharder.Owns(req.NamespacedName) {
+		log.V(1).Info("Resource is not in the shard of this replica, nothing to do")
+		return ctrl.Result{}, nil
+	}
+
 	err = r.client.Get(ctx, req.NamespacedName, obj)
 	if apierrors.IsNotFound(err) {
 		log.V(1).Info("Resource not found, nothing to do")
+		if r.sharder != nil {
+			r.sharder.Release(ctx, req.NamespacedName)
+		}
 		return ctrl.Result{}, nil
 	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	if r.sharder != nil {
+		// The shard cache only contains the resource once it is labeled
+		// with the shard of this replica, which triggers the next
+		// reconciliation.
+		if obj.GetLabels()[sharding.ShardLabel] != r.sharder.Identity() {
+			return ctrl.Result{}, r.assignShard(ctx, obj, log)
+		}
+		unlock, err := r.sharder.Lock(ctx, req.NamespacedName)
+		if errors.Is(err, sharding.ErrNotOwner) {
+			log.V(1).Info("Resource is not in the shard of this replica, nothing to do")
+			return ctrl.Result{}, nil
+		}
+		if errors.Is(err, sharding.ErrLocked) {
+			log.Info("Resource is still locked by another replica, waiting for it to finish", "reason", err.Error())
+			return ctrl.Result{RequeueAfter: r.sharder.RenewPeriod()}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to lock %s/%s: %w", req.Namespace, req.Name, err)
+		}
+		defer unlock()
+	}
+
 	// The finalizer must be present on the CR before we can do anything. Otherwise, if the reconciliation fails,
 	// there might be resources created by the chart that will not be garbage-collected
 	// (cluster-scoped resources or resources in other namespaces, which are not bound by an owner reference).
 	// This is a safety measure to ensure that the chart is fully uninstalled before the CR is deleted.
 	if obj.GetDeletionTimestamp() == nil && !controllerutil.ContainsFinalizer(obj, uninstallFinalizer) {
 		log.V(1).Info("Adding uninstall finalizer.")
-		obj.SetFinalizers(append(obj.GetFinalizers(), uninstallFinalizer))
-		if err := r.client.Update(ctx, obj); err != nil {
+		finalizerUpdater := r.newUpdater()
+		finalizerUpdater.Update(updater.AddFinalizer(uninstallFinalizer))
+		if err := finalizerUpdater.Apply(ctx, obj); err != nil {
 			return ctrl.Result{}, errs.Wrapf(err, "failed to add uninstall finalizer to %s/%s", req.NamespacedName.Namespace, req.NamespacedName.Name)
 		}
 	}
 
-	u := updater.New(r.client)
+	u := r.newUpdater()
 	defer func() {
 		applyErr := u.Apply(ctx, obj)
 		if err == nil && !apierrors.IsNotFound(applyErr) {
@@ -615,13 +1041,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		}
 	}()
 
+	pauseMode := PauseNone
 	if r.pauseHandler != nil {
-		paused, err := r.pauseHandler(ctx, obj)
+		mode, err := r.pauseHandler(ctx, obj)
 		if err != nil {
 			log.Error(err, "pause reconcile handler failed")
 		}
+		pauseMode = mode
 
-		if paused {
+		if pauseMode == PauseAll {
 			log.Info("Reconcile is paused for this resource.")
 			u.UpdateStatus(
 				updater.EnsureCondition(conditions.Paused(corev1.ConditionTrue, conditions.ReasonPauseReconcileAnnotationTrue, "")),
@@ -635,7 +1063,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		}
 	}
 
-	u.UpdateStatus(updater.EnsureCondition(conditions.Paused(corev1.ConditionFalse, "", "")))
+	switch pauseMode {
+	case PauseUpgrades:
+		log.Info("Upgrades are paused for this resource.")
+		u.UpdateStatus(updater.EnsureCondition(conditions.Paused(corev1.ConditionTrue, conditions.ReasonUpgradesPaused, "")))
+	case PauseDriftCorrection:
+		log.Info("Drift correction is paused for this resource.")
+		u.UpdateStatus(updater.EnsureCondition(conditions.Paused(corev1.ConditionTrue, conditions.ReasonDriftCorrectionPaused, "")))
+	default:
+		u.UpdateStatus(updater.EnsureCondition(conditions.Paused(corev1.ConditionFalse, "", "")))
+	}
 
 	actionClient, err := r.actionClientGetter.ActionClientFor(ctx, obj)
 	if err != nil {
@@ -669,11 +1106,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	u.UpdateStatus(updater.EnsureCondition(conditions.Initialized(corev1.ConditionTrue, "", "")))
 
 	if obj.GetDeletionTimestamp() != nil {
-		if err := r.handleDeletion(ctx, actionClient, obj, log); err != nil {
+		requeueAfter, err := r.handleDeletion(ctx, actionClient, obj, log)
+		if err != nil {
 			return ctrl.Result{}, err
 		}
 		u.CancelUpdates()
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	vals, err := r.getValues(ctx, obj)
@@ -685,7 +1123,39 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	rel, state, err := r.getReleaseState(actionClient, obj, vals.AsMap())
+	hookCtx := hook.WithStatusWriter(ctx, &statusWriter{u: &u})
+	vals, hookRequeueAfter, abort, err := r.runPreHooks(hookCtx, obj, vals, log)
+	if abort != nil {
+		reason := conditions.ReasonPreHookAborted
+		if abort.Reason != "" {
+			reason = status.ConditionReason(abort.Reason)
+		}
+		u.UpdateStatus(
+			updater.EnsureCondition(conditions.Irreconcilable(corev1.ConditionTrue, reason, abort.Message)),
+			updater.EnsureConditionUnknown(conditions.TypeReleaseFailed),
+		)
+		requeueAfter := r.reconcilePeriod
+		if abort.RequeueAfter > 0 {
+			requeueAfter = abort.RequeueAfter
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	if err != nil {
+		u.UpdateStatus(
+			updater.EnsureCondition(conditions.Irreconcilable(corev1.ConditionTrue, conditions.ReasonPreHookError, err)),
+			updater.EnsureConditionUnknown(conditions.TypeReleaseFailed),
+		)
+		return ctrl.Result{}, err
+	}
+
+	var fingerprint string
+	if r.dryRunFullCheckPeriod > 0 {
+		if fingerprint, err = r.releaseFingerprint(vals.AsMap()); err != nil {
+			log.Error(err, "release fingerprint failed")
+		}
+	}
+
+	rel, state, err := r.getReleaseState(actionClient, obj, vals.AsMap(), fingerprint, log)
 	if err != nil {
 		u.UpdateStatus(
 			updater.EnsureCondition(conditions.Irreconcilable(corev1.ConditionTrue, conditions.ReasonErrorGettingReleaseState, err)),
@@ -697,9 +1167,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 	u.UpdateStatus(updater.EnsureCondition(conditions.Irreconcilable(corev1.ConditionFalse, "", "")))
 
-	for _, h := range r.preHooks {
-		if err := h.Exec(obj, vals, log); err != nil {
-			log.Error(err, "pre-release hook failed")
+	if state == stateNeedsUpgrade && pauseMode == PauseUpgrades {
+		state = stateUpgradePaused
+	}
+
+	requeueAfter := r.reconcilePeriod
+	if hookRequeueAfter > 0 && (requeueAfter == 0 || hookRequeueAfter < requeueAfter) {
+		requeueAfter = hookRequeueAfter
+	}
+	if r.upgradeScheduleHandler != nil {
+		if state != stateNeedsUpgrade {
+			u.UpdateStatus(updater.EnsureCondition(conditions.UpgradeDeferred(corev1.ConditionFalse, "", "")))
+		} else if retryAfter, deferred := r.deferUpgrade(ctx, &u, obj, log); deferred {
+			state = stateUpgradeDeferred
+			if retryAfter > 0 && (requeueAfter == 0 || retryAfter < requeueAfter) {
+				requeueAfter = retryAfter
+			}
 		}
 	}
 
@@ -716,7 +1199,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			return ctrl.Result{}, err
 		}
 
-	case stateUnchanged:
+	case stateUnchanged, stateUpgradeDeferred, stateUpgradePaused:
+		if pauseMode == PauseDriftCorrection {
+			break
+		}
 		if err := r.doReconcile(actionClient, &u, rel, log); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -724,19 +1210,178 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, fmt.Errorf("unexpected release state: %s", state)
 	}
 
+	// The fingerprint is only recorded if the release was rendered from the
+	// current inputs, so that deferred and paused upgrades are checked again.
+	if fingerprint != "" && (state == stateNeedsInstall || state == stateNeedsUpgrade || state == stateUnchanged) {
+		u.UpdateStatus(updater.EnsureReleaseFingerprint(fingerprint, rel.Version))
+	}
+
 	for _, h := range r.postHooks {
-		if err := h.Exec(obj, *rel, log); err != nil {
+		if err := h.Exec(hookCtx, obj, *rel, log); err != nil {
 			log.Error(err, "post-release hook failed", "name", rel.Name, "version", rel.Version)
 		}
 	}
 
+	if r.statusMapper != nil {
+		if err := r.mapStatus(hookCtx, obj, rel); err != nil {
+			log.Error(err, "status mapping failed", "name", rel.Name, "version", rel.Version)
+		}
+	}
+
 	ensureDeployedRelease(&u, rel)
 	u.UpdateStatus(
 		updater.EnsureCondition(conditions.ReleaseFailed(corev1.ConditionFalse, "", "")),
 		updater.EnsureCondition(conditions.Irreconcilable(corev1.ConditionFalse, "", "")),
 	)
 
-	return ctrl.Result{RequeueAfter: r.reconcilePeriod}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// assignShard labels obj with the shard of this replica, so that the shard
+// cache and the post-renderers of this replica pick it up.
+func (r *Reconciler) assignShard(ctx context.Context, obj *unstructured.Unstructured, log logr.Logger) error {
+	patch := client.MergeFrom(obj.DeepCopy())
+	lbls := obj.GetLabels()
+	if lbls == nil {
+		lbls = map[string]string{}
+	}
+	previous := lbls[sharding.ShardLabel]
+	lbls[sharding.ShardLabel] = r.sharder.Identity()
+	obj.SetLabels(lbls)
+	if err := r.client.Patch(ctx, obj, patch); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to assign %s/%s to shard: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	log.Info("Assigned resource to the shard of this replica", "previousShard", previous)
+	return nil
+}
+
+// runPreHooks runs the pre-hooks in order and returns the values they
+// modified and the shortest duration after which they requested to be
+// requeued. If a hook aborts the reconciliation, it returns the AbortError.
+// It returns the errors of fatal hooks, and only logs those of advisory ones.
+func (r *Reconciler) runPreHooks(ctx context.Context, obj *unstructured.Unstructured, vals chartutil.Values, log logr.Logger) (chartutil.Values, time.Duration, *hook.AbortError, error) {
+	var requeueAfter time.Duration
+	for _, h := range r.preHooks {
+		res, err := h.Exec(ctx, obj, vals, log)
+		var abort *hook.AbortError
+		if errors.As(err, &abort) {
+			log.Info("Reconciliation aborted by pre-release hook", "reason", abort.Reason, "message", abort.Message)
+			return vals, 0, abort, nil
+		}
+		if err != nil {
+			if h.FailurePolicy() == hook.FailurePolicyFatal {
+				return vals, 0, nil, fmt.Errorf("pre-release hook failed: %w", err)
+			}
+			log.Error(err, "pre-release hook failed")
+			continue
+		}
+		if res.Values != nil {
+			vals = res.Values
+		}
+		if res.RequeueAfter > 0 && (requeueAfter == 0 || res.RequeueAfter < requeueAfter) {
+			requeueAfter = res.RequeueAfter
+		}
+	}
+	return vals, requeueAfter, nil, nil
+}
+
+// newUpdater returns an Updater that writes custom resources with the update
+// strategy of the reconciler.
+func (r *Reconciler) newUpdater() updater.Updater {
+	strategy := updater.StrategyUpdate
+	if r.updateStrategy == UpdateStrategyPatch {
+		strategy = updater.StrategyPatch
+	}
+	return updater.New(r.client, updater.WithStrategy(strategy))
+}
+
+// mapStatus maps the values of the objects of rel into the status of obj. The
+// objects are read from the cluster the release is installed in.
+func (r *Reconciler) mapStatus(ctx context.Context, obj *unstructured.Unstructured, rel *release.Release) error {
+	var (
+		reader client.Reader = r.client
+		mapper               = r.client.RESTMapper()
+	)
+	if r.dependentResourceCache != nil {
+		reader = r.dependentResourceCache
+	}
+	if r.remoteClusters != nil {
+		if cluster := r.remoteClusters.Lookup(obj); cluster != nil {
+			c, err := cluster.Cache()
+			if err != nil {
+				return err
+			}
+			reader, mapper = c, cluster.RESTMapper
+		}
+	}
+	return r.statusMapper.Map(ctx, reader, mapper, obj.GetNamespace(), rel.Manifest, hook.StatusWriterFrom(ctx))
+}
+
+// statusWriter is the hook.StatusWriter that records the status changes of
+// hooks in the updater of the reconciliation.
+type statusWriter struct {
+	u *updater.Updater
+}
+
+func (w *statusWriter) SetCondition(conditionType string, stat corev1.ConditionStatus, reason, message string) error {
+	t := status.ConditionType(conditionType)
+	if conditions.IsReserved(t) {
+		return fmt.Errorf("condition type %q is reserved for the reconciler", conditionType)
+	}
+	w.u.UpdateStatus(updater.EnsureCondition(status.Condition{
+		Type:    t,
+		Status:  stat,
+		Reason:  status.ConditionReason(reason),
+		Message: message,
+	}))
+	return nil
+}
+
+func (w *statusWriter) SetField(value interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		return errors.New("field path must not be empty")
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("invalid value of status field %q: %w", strings.Join(fields, "."), err)
+	}
+	var v interface{}
+	if err := utiljson.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("invalid value of status field %q: %w", strings.Join(fields, "."), err)
+	}
+	w.u.UpdateStatus(updater.EnsureCustomField(v, fields...))
+	return nil
+}
+
+func (w *statusWriter) RemoveField(fields ...string) {
+	w.u.UpdateStatus(updater.RemoveCustomField(fields...))
+}
+
+// deferUpgrade returns whether the upgrade of the release of obj is deferred
+// by the upgrade schedule handler and, if it is, after how long it should be
+// retried, which is 0 if that is unknown.
+func (r *Reconciler) deferUpgrade(ctx context.Context, u *updater.Updater, obj *unstructured.Unstructured, log logr.Logger) (time.Duration, bool) {
+	now := time.Now()
+	allowed, next, err := r.upgradeScheduleHandler(ctx, obj, now)
+	if err != nil {
+		log.Error(err, "upgrade schedule handler failed, deferring upgrade")
+		u.UpdateStatus(updater.EnsureCondition(conditions.UpgradeDeferred(corev1.ConditionTrue, conditions.ReasonUpgradeScheduleError, err)))
+		return 0, true
+	}
+	if allowed {
+		u.UpdateStatus(updater.EnsureCondition(conditions.UpgradeDeferred(corev1.ConditionFalse, "", "")))
+		return 0, false
+	}
+
+	message := "upgrade is deferred, no maintenance window starts in the future"
+	var retryAfter time.Duration
+	if !next.IsZero() {
+		message = fmt.Sprintf("upgrade is deferred until the next maintenance window starts at %s", next.UTC().Format(time.RFC3339))
+		retryAfter = next.Sub(now)
+	}
+	log.Info("Deferring upgrade outside of maintenance windows", "nextWindow", next)
+	u.UpdateStatus(updater.EnsureCondition(conditions.UpgradeDeferred(corev1.ConditionTrue, conditions.ReasonOutsideMaintenanceWindow, message)))
+	return retryAfter, true
 }
 
 func (r *Reconciler) getValues(ctx context.Context, obj *unstructured.Unstructured) (chartutil.Values, error) {
@@ -748,6 +1393,7 @@ func (r *Reconciler) getValues(ctx context.Context, obj *unstructured.Unstructur
 		return chartutil.Values{}, err
 	}
 	vals = r.valueMapper.Map(vals)
+	r.removeOperatorFields(vals)
 	vals, err = chartutil.CoalesceValues(r.chrt, vals)
 	if err != nil {
 		return chartutil.Values{}, err
@@ -755,16 +1401,39 @@ func (r *Reconciler) getValues(ctx context.Context, obj *unstructured.Unstructur
 	return vals, nil
 }
 
+// removeOperatorFields removes the fields of the spec that configure the
+// operator rather than the release from vals.
+func (r *Reconciler) removeOperatorFields(vals chartutil.Values) {
+	var paths [][]string
+	if r.remoteClusters != nil {
+		paths = append(paths, r.remoteClusters.KubeConfigSecretRefPath())
+	}
+	if r.impersonation != nil {
+		paths = append(paths, r.impersonation.ServiceAccountPath)
+	}
+	for _, p := range paths {
+		if len(p) > 1 && p[0] == "spec" {
+			unstructured.RemoveNestedField(vals, p[1:]...)
+		}
+	}
+}
+
 type helmReleaseState string
 
 const (
 	stateNeedsInstall helmReleaseState = "needs install"
 	stateNeedsUpgrade helmReleaseState = "needs upgrade"
 	stateUnchanged    helmReleaseState = "unchanged"
-	stateError        helmReleaseState = "error"
+	// stateUpgradeDeferred is a release that needs an upgrade that is
+	// deferred by the upgrade schedule handler.
+	stateUpgradeDeferred helmReleaseState = "upgrade deferred"
+	// stateUpgradePaused is a release that needs an upgrade while upgrades
+	// are paused by the pause handler.
+	stateUpgradePaused helmReleaseState = "upgrade paused"
+	stateError         helmReleaseState = "error"
 )
 
-func (r *Reconciler) handleDeletion(ctx context.Context, actionClient helmclient.ActionInterface, obj *unstructured.Unstructured, log logr.Logger) error {
+func (r *Reconciler) handleDeletion(ctx context.Context, actionClient helmclient.ActionInterface, obj *unstructured.Unstructured, log logr.Logger) (time.Duration, error) {
 	if controllerutil.ContainsFinalizer(obj, uninstallFinalizer) {
 		// Use defer in a closure so that it executes before we wait for
 		// the deletion of the CR. This might seem unnecessary since we're
@@ -772,17 +1441,18 @@ func (r *Reconciler) handleDeletion(ctx context.Context, actionClient helmclient
 		// However, if uninstall fails, the finalizer will not be removed
 		// and we need to be able to update the conditions on the CR to
 		// indicate that the uninstall failed.
-		if err := func() (err error) {
-			uninstallUpdater := updater.New(r.client)
+		requeueAfter, err := func() (_ time.Duration, err error) {
+			uninstallUpdater := r.newUpdater()
 			defer func() {
 				applyErr := uninstallUpdater.Apply(ctx, obj)
 				if err == nil {
 					err = applyErr
 				}
 			}()
-			return r.doUninstall(actionClient, &uninstallUpdater, obj, log)
-		}(); err != nil {
-			return err
+			return r.doUninstallWithHooks(ctx, actionClient, &uninstallUpdater, obj, log)
+		}()
+		if err != nil || requeueAfter > 0 {
+			return requeueAfter, err
 		}
 	} else {
 		log.Info("Resource is already terminated, skipping deletion.")
@@ -797,10 +1467,71 @@ func (r *Reconciler) handleDeletion(ctx context.Context, actionClient helmclient
 	// will attempt to uninstall the release again.
 	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, r.waitForDeletionTimeout)
 	defer timeoutCancel()
-	return controllerutil.WaitForDeletion(timeoutCtx, r.client, obj)
+	return 0, controllerutil.WaitForDeletion(timeoutCtx, r.client, obj)
+}
+
+// doUninstallWithHooks uninstalls the release between the pre- and
+// post-uninstall hooks and removes the finalizer afterwards. If a hook fails
+// or requests to be requeued, it stops and returns the error or the duration
+// after which the CR should be requeued.
+func (r *Reconciler) doUninstallWithHooks(ctx context.Context, actionClient helmclient.ActionInterface, u *updater.Updater, obj *unstructured.Unstructured, log logr.Logger) (time.Duration, error) {
+	ctx = hook.WithStatusWriter(ctx, &statusWriter{u: u})
+	var rel *release.Release
+	if len(r.preUninstallHooks) > 0 || len(r.postUninstallHooks) > 0 {
+		var err error
+		if rel, err = actionClient.Get(obj.GetName()); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return 0, err
+		}
+	}
+
+	if requeueAfter, err := r.runUninstallHooks(ctx, u, r.preUninstallHooks, conditions.ReasonPreUninstallHookPending, obj, rel, log); err != nil || requeueAfter > 0 {
+		return requeueAfter, err
+	}
+	if err := r.doUninstall(actionClient, u, obj, log); err != nil {
+		return 0, err
+	}
+	if requeueAfter, err := r.runUninstallHooks(ctx, u, r.postUninstallHooks, conditions.ReasonPostUninstallHookPending, obj, rel, log); err != nil || requeueAfter > 0 {
+		return requeueAfter, err
+	}
+	if r.dependentResourceWatcher != nil {
+		r.dependentResourceWatcher.Forget(ctx, obj, log)
+	}
+	if r.remoteClusters != nil {
+		r.remoteClusters.Forget(obj)
+	}
+	r.dryRunChecks.forget(client.ObjectKeyFromObject(obj))
+
+	u.Update(updater.RemoveFinalizer(uninstallFinalizer))
+	if len(r.preUninstallHooks) > 0 || len(r.postUninstallHooks) > 0 {
+		u.UpdateStatus(updater.EnsureCondition(conditions.UninstallBlocked(corev1.ConditionFalse, "", "")))
+	}
+	return 0, nil
 }
 
-func (r *Reconciler) getReleaseState(client helmclient.ActionInterface, obj metav1.Object, vals map[string]interface{}) (*release.Release, helmReleaseState, error) {
+// runUninstallHooks runs the given uninstall hooks in order. If a hook fails or
+// requests to be requeued, it marks the CR UninstallBlocked and returns the
+// error or the duration after which the CR should be requeued.
+func (r *Reconciler) runUninstallHooks(ctx context.Context, u *updater.Updater, hooks []hook.UninstallHook, pendingReason status.ConditionReason, obj *unstructured.Unstructured, rel *release.Release, log logr.Logger) (time.Duration, error) {
+	for _, h := range hooks {
+		res, err := h.Exec(ctx, obj, rel, log)
+		if err != nil {
+			u.UpdateStatus(updater.EnsureCondition(conditions.UninstallBlocked(corev1.ConditionTrue, conditions.ReasonUninstallHookError, err)))
+			return 0, fmt.Errorf("uninstall hook failed: %w", err)
+		}
+		if res.RequeueAfter > 0 {
+			log.Info("Uninstall delayed by hook", "reason", pendingReason, "message", res.Message, "requeueAfter", res.RequeueAfter)
+			u.UpdateStatus(updater.EnsureCondition(conditions.UninstallBlocked(corev1.ConditionTrue, pendingReason, res.Message)))
+			return res.RequeueAfter, nil
+		}
+	}
+	return 0, nil
+}
+
+// getReleaseState returns the current release and whether it needs to be
+// installed or upgraded. If fingerprint is set and matches the fingerprint
+// recorded for the deployed release, the release is unchanged without a
+// dry-run upgrade, unless a full check is due.
+func (r *Reconciler) getReleaseState(client helmclient.ActionInterface, obj *unstructured.Unstructured, vals map[string]interface{}, fingerprint string, log logr.Logger) (*release.Release, helmReleaseState, error) {
 	currentRelease, err := client.Get(obj.GetName())
 	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
 		return nil, stateError, err
@@ -810,6 +1541,15 @@ func (r *Reconciler) getReleaseState(client helmclient.ActionInterface, obj meta
 		return nil, stateNeedsInstall, nil
 	}
 
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	if fingerprint != "" &&
+		currentRelease.Info.Status == release.StatusDeployed &&
+		fingerprintMatches(obj, fingerprint, currentRelease) &&
+		!r.dryRunChecks.due(key, r.dryRunFullCheckPeriod) {
+		log.V(1).Info("Release inputs are unchanged, skipping dry-run upgrade", "name", currentRelease.Name, "version", currentRelease.Version)
+		return currentRelease, stateUnchanged, nil
+	}
+
 	var opts []helmclient.UpgradeOption
 	if *r.maxReleaseHistory > 0 {
 		opts = append(opts, func(u *action.Upgrade) error {
@@ -831,6 +1571,9 @@ func (r *Reconciler) getReleaseState(client helmclient.ActionInterface, obj meta
 	if err != nil {
 		return currentRelease, stateError, err
 	}
+	if fingerprint != "" {
+		r.dryRunChecks.record(key)
+	}
 	if specRelease.Manifest != currentRelease.Manifest ||
 		currentRelease.Info.Status == release.StatusFailed ||
 		currentRelease.Info.Status == release.StatusSuperseded {
@@ -942,7 +1685,7 @@ func (r *Reconciler) doUninstall(actionClient helmclient.ActionInterface, u *upd
 
 	resp, err := actionClient.Uninstall(obj.GetName(), opts...)
 	if errors.Is(err, driver.ErrReleaseNotFound) {
-		log.Info("Release not found, removing finalizer")
+		log.Info("Release not found, skipping uninstall")
 	} else if err != nil {
 		u.UpdateStatus(
 			updater.EnsureCondition(conditions.Irreconcilable(corev1.ConditionTrue, conditions.ReasonReconcileError, err)),
@@ -957,7 +1700,6 @@ func (r *Reconciler) doUninstall(actionClient helmclient.ActionInterface, u *upd
 			fmt.Println(diff.Generate(resp.Release.Manifest, ""))
 		}
 	}
-	u.Update(updater.RemoveFinalizer(uninstallFinalizer))
 	u.UpdateStatus(
 		updater.EnsureCondition(conditions.ReleaseFailed(corev1.ConditionFalse, "", "")),
 		updater.EnsureCondition(conditions.Deployed(corev1.ConditionFalse, conditions.ReasonUninstallSuccessful, "")),
@@ -984,11 +1726,25 @@ func (r *Reconciler) addDefaults(mgr ctrl.Manager, controllerName string) error
 		r.log = ctrl.Log.WithName("controllers").WithName("Helm")
 	}
 	if r.actionClientGetter == nil {
-		actionConfigGetter, err := helmclient.NewActionConfigGetter(mgr.GetConfig(), mgr.GetRESTMapper())
+		var opts []helmclient.ActionConfigGetterOption
+		if r.remoteClusters != nil {
+			opts = append(opts, helmclient.ClientClusterMapper(r.remoteClusters.ClusterFor))
+		}
+		if r.impersonation != nil {
+			opts = append(opts, helmclient.ClientRestConfigMapper(helmclient.ImpersonateServiceAccount(*r.impersonation)))
+		}
+		if r.storageDriverMapper != nil {
+			opts = append(opts, helmclient.StorageDriverMapper(r.storageDriverMapper))
+		}
+		actionConfigGetter, err := helmclient.NewActionConfigGetter(mgr.GetConfig(), mgr.GetRESTMapper(), opts...)
 		if err != nil {
 			return fmt.Errorf("creating action config getter: %w", err)
 		}
-		r.actionClientGetter, err = helmclient.NewActionClientGetter(actionConfigGetter)
+		var clientOpts []helmclient.ActionClientGetterOption
+		if lbls := r.dependentLabels(); len(lbls) > 0 {
+			clientOpts = append(clientOpts, helmclient.AppendPostRenderers(helmclient.LabelPostRendererFunc(lbls)))
+		}
+		r.actionClientGetter, err = helmclient.NewActionClientGetter(actionConfigGetter, clientOpts...)
 		if err != nil {
 			return fmt.Errorf("creating action client getter: %v", err)
 		}
@@ -996,12 +1752,7 @@ func (r *Reconciler) addDefaults(mgr ctrl.Manager, controllerName string) error
 	if r.eventRecorder == nil {
 		r.eventRecorder = mgr.GetEventRecorderFor(controllerName)
 	}
-	if r.valueTranslator == nil {
-		r.valueTranslator = internalvalues.DefaultTranslator
-	}
-	if r.valueMapper == nil {
-		r.valueMapper = internalvalues.DefaultMapper
-	}
+	r.addValueDefaults()
 
 	if r.waitForDeletionTimeout == 0 {
 		r.waitForDeletionTimeout = internalvalues.DefaultWaitForDeletionTimeout
@@ -1014,6 +1765,24 @@ func (r *Reconciler) addDefaults(mgr ctrl.Manager, controllerName string) error
 	return nil
 }
 
+// dependentLabels returns the labels that the Reconciler sets on all objects
+// of its releases: the dependent resource label and the shard label.
+func (r *Reconciler) dependentLabels() labels.Set {
+	if r.sharder == nil {
+		return r.dependentResourceLabel
+	}
+	return labels.Merge(r.dependentResourceLabel, r.sharder.Labels())
+}
+
+func (r *Reconciler) addValueDefaults() {
+	if r.valueTranslator == nil {
+		r.valueTranslator = internalvalues.DefaultTranslator
+	}
+	if r.valueMapper == nil {
+		r.valueMapper = internalvalues.DefaultMapper
+	}
+}
+
 func (r *Reconciler) setupScheme(mgr ctrl.Manager) {
 	mgr.GetScheme().AddKnownTypeWithName(*r.gvk, &unstructured.Unstructured{})
 	metav1.AddToGroupVersion(mgr.GetScheme(), r.gvk.GroupVersion())
@@ -1033,9 +1802,16 @@ func (r *Reconciler) setupWatches(mgr ctrl.Manager, c controller.Controller) err
 		preds = append(preds, selectorPredicate)
 	}
 
+	primaryCache := mgr.GetCache()
+	if r.sharder != nil {
+		if err := r.setupShardCaches(mgr); err != nil {
+			return err
+		}
+		primaryCache = r.shardCache
+	}
 	if err := c.Watch(
 		source.Kind(
-			mgr.GetCache(),
+			primaryCache,
 			client.Object(obj),
 			&sdkhandler.InstrumentedEnqueueRequestForObject[client.Object]{},
 			preds...,
@@ -1067,12 +1843,180 @@ func (r *Reconciler) setupWatches(mgr ctrl.Manager, c controller.Controller) err
 		return err
 	}
 
+	if r.remoteClusters != nil {
+		if err := r.setupRemoteClusterWatches(mgr, c); err != nil {
+			return err
+		}
+	}
+
+	if r.sharder != nil {
+		if err := r.setupShardWatches(mgr, c); err != nil {
+			return err
+		}
+	}
+
 	if !r.skipDependentWatches {
-		r.postHooks = append([]hook.PostHook{internalhook.NewDependentResourceWatcher(c, mgr.GetRESTMapper(), mgr.GetCache(), mgr.GetScheme())}, r.postHooks...)
+		var opts []internalhook.DependentResourceWatcherOption
+		if r.remoteClusters != nil {
+			opts = append(opts, internalhook.WithRemoteClusters(r.remoteClusters))
+		}
+		if r.statusMapper != nil {
+			opts = append(opts, internalhook.WithStatusChangesOf(r.statusMapper.Kinds()...))
+		}
+		if r.metadataOnlyDependentWatches {
+			opts = append(opts, internalhook.WithMetadataOnly())
+		}
+		if r.dependentEventFilter != nil {
+			opts = append(opts, internalhook.WithEventFilter(r.dependentEventFilter))
+		}
+		dependentCache := mgr.GetCache()
+		if lbls := r.dependentLabels(); len(lbls) > 0 {
+			if err := r.setupDependentResourceCache(mgr, lbls); err != nil {
+				return err
+			}
+			dependentCache = r.dependentResourceCache
+		}
+		r.dependentResourceWatcher = internalhook.NewDependentResourceWatcher(c, mgr.GetRESTMapper(), dependentCache, mgr.GetScheme(), opts...)
+		r.postHooks = append([]hook.PostHookV2{r.dependentResourceWatcher}, r.postHooks...)
 	}
 	return nil
 }
 
+// setupDependentResourceCache creates the cache that only contains objects
+// with the given labels and adds it to the manager, which starts and stops it.
+func (r *Reconciler) setupDependentResourceCache(mgr ctrl.Manager, lbls labels.Set) error {
+	c, err := cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient:           mgr.GetHTTPClient(),
+		Scheme:               mgr.GetScheme(),
+		Mapper:               mgr.GetRESTMapper(),
+		DefaultLabelSelector: labels.SelectorFromSet(lbls),
+	})
+	if err != nil {
+		return fmt.Errorf("creating dependent resource cache: %w", err)
+	}
+	if err := mgr.Add(c); err != nil {
+		return err
+	}
+	r.dependentResourceCache = c
+	return nil
+}
+
+// setupRemoteClusterWatches adds the remote clusters to the manager, so that
+// the caches of the remote clusters are stopped with the manager, and
+// reconciles the custom resources of a remote cluster when its kubeconfig
+// Secret changes.
+func (r *Reconciler) setupRemoteClusterWatches(mgr ctrl.Manager, c controller.Controller) error {
+	if err := mgr.Add(r.remoteClusters); err != nil {
+		return fmt.Errorf("adding remote clusters to manager: %w", err)
+	}
+	return c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			client.Object(&corev1.Secret{}),
+			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, secret client.Object) []reconcile.Request {
+				var reqs []reconcile.Request
+				for _, key := range r.remoteClusters.ObjectsFor(client.ObjectKeyFromObject(secret), *r.gvk) {
+					reqs = append(reqs, reconcile.Request{NamespacedName: key})
+				}
+				return reqs
+			}),
+		),
+	)
+}
+
+// setupShardCaches creates the cache that only contains the custom resources
+// of the shard of this replica and the cache that only contains the metadata
+// of the custom resources that are not assigned to a shard yet, and adds them
+// to the manager, which starts and stops them.
+func (r *Reconciler) setupShardCaches(mgr ctrl.Manager) error {
+	selector, err := metav1.LabelSelectorAsSelector(&r.labelSelector)
+	if err != nil {
+		return err
+	}
+	shardRequirements, _ := r.sharder.Selector().Requirements()
+	unassigned, err := labels.NewRequirement(sharding.ShardLabel, selection.DoesNotExist, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range []struct {
+		cache    *cache.Cache
+		selector labels.Selector
+	}{
+		{&r.shardCache, selector.Add(shardRequirements...)},
+		{&r.unassignedCache, selector.Add(*unassigned)},
+	} {
+		*c.cache, err = cache.New(mgr.GetConfig(), cache.Options{
+			HTTPClient:           mgr.GetHTTPClient(),
+			Scheme:               mgr.GetScheme(),
+			Mapper:               mgr.GetRESTMapper(),
+			DefaultLabelSelector: c.selector,
+		})
+		if err != nil {
+			return fmt.Errorf("creating shard cache: %w", err)
+		}
+		if err := mgr.Add(*c.cache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupShardWatches adds the sharder to the manager and reconciles the custom
+// resources that are not assigned to a shard yet, and those that move to the
+// shard of this replica when replicas join or leave, so that they are
+// assigned to it.
+func (r *Reconciler) setupShardWatches(mgr ctrl.Manager, c controller.Controller) error {
+	if err := mgr.Add(r.sharder); err != nil {
+		return fmt.Errorf("adding sharder to manager: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&r.labelSelector)
+	if err != nil {
+		return err
+	}
+
+	unassigned := &metav1.PartialObjectMetadata{}
+	unassigned.SetGroupVersionKind(*r.gvk)
+	if err := c.Watch(
+		source.Kind(
+			r.unassignedCache,
+			client.Object(unassigned),
+			&handler.EnqueueRequestForObject{},
+		),
+	); err != nil {
+		return err
+	}
+
+	// The custom resources of other shards are not cached, so the metadata
+	// of all custom resources is listed from the API server, which only
+	// happens when the members of the group change.
+	rebalance := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		if ctx.Err() != nil {
+			return
+		}
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(r.gvk.GroupVersion().WithKind(r.gvk.Kind + "List"))
+		if err := mgr.GetAPIReader().List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			r.log.Error(err, "Failed to list resources of shard")
+			return
+		}
+		for i := range list.Items {
+			key := client.ObjectKeyFromObject(&list.Items[i])
+			if r.sharder.Owns(key) && list.Items[i].Labels[sharding.ShardLabel] != r.sharder.Identity() {
+				q.Add(reconcile.Request{NamespacedName: key})
+			}
+		}
+	}
+	return c.Watch(source.Func(func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		r.sharder.OnRebalance(func(func(types.NamespacedName) bool) {
+			rebalance(ctx, q)
+		})
+		// The shard may have changed before the controller started.
+		go rebalance(ctx, q)
+		return nil
+	}))
+}
+
 func ensureDeployedRelease(u *updater.Updater, rel *release.Release) {
 	reason := conditions.ReasonInstallSuccessful
 	message := "release was successfully installed"
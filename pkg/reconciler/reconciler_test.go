@@ -41,11 +41,13 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -65,11 +67,14 @@ import (
 	"github.com/operator-framework/helm-operator-plugins/internal/sdk/controllerutil"
 	"github.com/operator-framework/helm-operator-plugins/pkg/annotation"
 	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
 	"github.com/operator-framework/helm-operator-plugins/pkg/hook"
 	"github.com/operator-framework/helm-operator-plugins/pkg/internal/status"
 	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
 	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/conditions"
-	helmfake "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/fake"
+	reconcilertesting "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/testing"
+	"github.com/operator-framework/helm-operator-plugins/pkg/sharding"
+	"github.com/operator-framework/helm-operator-plugins/pkg/statusmapping"
 	"github.com/operator-framework/helm-operator-plugins/pkg/values"
 )
 
@@ -148,11 +153,183 @@ var _ = Describe("Reconciler", func() {
 		})
 		_ = Describe("WithActionClientGetter", func() {
 			It("should set the reconciler action client getter", func() {
-				fakeActionClientGetter := helmfake.NewActionClientGetter(nil, nil)
+				fakeActionClientGetter := reconcilertesting.NewActionClientGetter(nil, nil)
 				Expect(WithActionClientGetter(fakeActionClientGetter)(r)).To(Succeed())
 				Expect(r.actionClientGetter).To(Equal(fakeActionClientGetter))
 			})
 		})
+		_ = Describe("WithRemoteClusters", func() {
+			It("should set the reconciler remote clusters", func() {
+				rc := helmclient.NewRemoteClusters(nil)
+				Expect(WithRemoteClusters(rc)(r)).To(Succeed())
+				Expect(r.remoteClusters).To(BeIdenticalTo(rc))
+			})
+			It("should remove the kubeconfig secret reference from the values", func() {
+				chrt := testutil.MustLoadChart("../../pkg/internal/testdata/test-chart-1.2.0.tgz")
+				Expect(WithChart(chrt)(r)).To(Succeed())
+				Expect(WithRemoteClusters(helmclient.NewRemoteClusters(nil))(r)).To(Succeed())
+				r.addValueDefaults()
+
+				obj := &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"replicaCount":        int64(2),
+						"kubeConfigSecretRef": map[string]interface{}{"name": "spoke"},
+					},
+				}}
+				vals, err := r.getValues(context.Background(), obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vals).NotTo(HaveKey("kubeConfigSecretRef"))
+				Expect(vals).To(HaveKeyWithValue("replicaCount", int64(2)))
+			})
+			It("should forget the remote cluster of a resource after its release was uninstalled", func() {
+				ctx := context.Background()
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "spoke"},
+					Data: map[string][]byte{helmclient.DefaultKubeConfigSecretKey: []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: spoke
+  cluster:
+    server: https://spoke.example.com:6443
+users:
+- name: operator
+  user:
+    token: secret-token
+contexts:
+- name: spoke
+  context:
+    cluster: spoke
+    user: operator
+current-context: spoke
+`)},
+				}
+				rc := helmclient.NewRemoteClusters(fake.NewClientBuilder().WithObjects(secret).Build())
+				Expect(WithRemoteClusters(rc)(r)).To(Succeed())
+				r.log = logr.Discard()
+
+				obj := &unstructured.Unstructured{}
+				obj.SetGroupVersionKind(gvk)
+				obj.SetNamespace("ns")
+				obj.SetName("test")
+				obj.SetUID("test-uid")
+				Expect(unstructured.SetNestedField(obj.Object, "spoke", "spec", "kubeConfigSecretRef", "name")).To(Succeed())
+				cluster, err := rc.ClusterFor(ctx, obj, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rc.Lookup(obj)).To(BeIdenticalTo(cluster))
+
+				ac := reconcilertesting.NewActionClient()
+				ac.HandleUninstall = reconcilertesting.Return(&release.UninstallReleaseResponse{Release: &release.Release{Name: "test"}}, nil)
+				u := r.newUpdater()
+				Expect(r.doUninstallWithHooks(ctx, ac, &u, obj, logr.Discard())).To(BeZero())
+				Expect(rc.Lookup(obj)).To(BeNil())
+				Expect(rc.ObjectsFor(cluster.Secret, gvk)).To(BeEmpty())
+			})
+		})
+		_ = Describe("WithStorageDriver", func() {
+			It("should set the reconciler storage driver mapper", func() {
+				Expect(WithStorageDriver(helmclient.ChunkedSecretsStorageDriver(helmclient.ChunkedSecretsStorageDriverOpts{}))(r)).To(Succeed())
+				Expect(r.storageDriverMapper).NotTo(BeNil())
+			})
+		})
+		_ = Describe("WithImpersonation", func() {
+			It("should set the reconciler impersonation options", func() {
+				Expect(WithImpersonation(helmclient.ImpersonationOpts{DefaultServiceAccount: "helm"})(r)).To(Succeed())
+				Expect(r.impersonation).To(Equal(&helmclient.ImpersonationOpts{
+					ServiceAccountPath:    helmclient.DefaultServiceAccountPath,
+					DefaultServiceAccount: "helm",
+				}))
+			})
+			It("should fail with invalid allowed service account patterns", func() {
+				Expect(WithImpersonation(helmclient.ImpersonationOpts{AllowedServiceAccounts: []string{"deployer-["}})(r)).NotTo(Succeed())
+			})
+			It("should remove the service account name from the values", func() {
+				chrt := testutil.MustLoadChart("../../pkg/internal/testdata/test-chart-1.2.0.tgz")
+				Expect(WithChart(chrt)(r)).To(Succeed())
+				Expect(WithImpersonation(helmclient.ImpersonationOpts{})(r)).To(Succeed())
+				r.addValueDefaults()
+
+				obj := &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"replicaCount":       int64(2),
+						"serviceAccountName": "deployer",
+					},
+				}}
+				vals, err := r.getValues(context.Background(), obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vals).NotTo(HaveKey("serviceAccountName"))
+				Expect(vals).To(HaveKeyWithValue("replicaCount", int64(2)))
+			})
+		})
+		_ = Describe("WithSharder", func() {
+			var s *sharding.Sharder
+			BeforeEach(func() {
+				cl := fake.NewClientBuilder().Build()
+				var err error
+				s, err = sharding.New(cl, cl, "pod-a", "operators")
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should set the reconciler sharder", func() {
+				Expect(WithSharder(s)(r)).To(Succeed())
+				Expect(r.sharder).To(BeIdenticalTo(s))
+			})
+			It("should not reconcile resources of other shards", func() {
+				Expect(WithSharder(s)(r)).To(Succeed())
+				Expect(WithGroupVersionKind(gvk)(r)).To(Succeed())
+				r.log = logr.Discard()
+
+				// The sharder has not joined its group, so it owns nothing
+				// and the reconciler must not use its unset client.
+				res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cr"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(reconcile.Result{}))
+			})
+			It("should label the dependent resources with the shard", func() {
+				Expect(WithSharder(s)(r)).To(Succeed())
+				Expect(r.dependentLabels()).To(Equal(labels.Set{sharding.ShardLabel: "pod-a"}))
+				Expect(WithDependentResourceLabel("app.kubernetes.io/managed-by", "my-operator")(r)).To(Succeed())
+				Expect(r.dependentLabels()).To(Equal(labels.Set{
+					sharding.ShardLabel:            "pod-a",
+					"app.kubernetes.io/managed-by": "my-operator",
+				}))
+			})
+			It("should assign resources of its shard to it before reconciling them", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				DeferCleanup(cancel)
+				clock := clocktesting.NewFakeClock(time.Now())
+				cl := fake.NewClientBuilder().Build()
+				s, err := sharding.New(cl, cl, "pod-a", "operators", sharding.WithClock(clock))
+				Expect(err).ToNot(HaveOccurred())
+				go func() {
+					defer GinkgoRecover()
+					Expect(s.Start(ctx)).To(Succeed())
+				}()
+				Eventually(clock.HasWaiters).Should(BeTrue())
+				clock.Step(sharding.DefaultRenewPeriod)
+				Eventually(s.Members).Should(Equal([]string{"pod-a"}))
+
+				scheme := runtime.NewScheme()
+				scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+				scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+				obj := &unstructured.Unstructured{}
+				obj.SetGroupVersionKind(gvk)
+				obj.SetNamespace("ns")
+				obj.SetName("cr")
+				obj.SetLabels(map[string]string{sharding.ShardLabel: "pod-b"})
+				objClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj).Build()
+
+				Expect(WithSharder(s)(r)).To(Succeed())
+				Expect(WithGroupVersionKind(gvk)(r)).To(Succeed())
+				Expect(WithClient(objClient)(r)).To(Succeed())
+				r.log = logr.Discard()
+
+				res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(reconcile.Result{}))
+				Expect(objClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+				Expect(obj.GetLabels()).To(HaveKeyWithValue(sharding.ShardLabel, "pod-a"))
+			})
+		})
 		_ = Describe("WithEventRecorder", func() {
 			It("should set the reconciler event recorder", func() {
 				rec := record.NewFakeRecorder(0)
@@ -203,6 +380,51 @@ var _ = Describe("Reconciler", func() {
 				Expect(r.skipDependentWatches).To(BeTrue())
 			})
 		})
+		_ = Describe("WithMetadataOnlyDependentWatches", func() {
+			It("should set the reconciler to watch dependent resources with metadata-only informers", func() {
+				Expect(WithMetadataOnlyDependentWatches(true)(r)).To(Succeed())
+				Expect(r.metadataOnlyDependentWatches).To(BeTrue())
+			})
+		})
+		_ = Describe("SkipUnchangedDryRuns", func() {
+			It("should set the reconciler dry-run full check period and post-renderer config", func() {
+				Expect(SkipUnchangedDryRuns(time.Hour, "kustomize-v1")(r)).To(Succeed())
+				Expect(r.dryRunFullCheckPeriod).To(Equal(time.Hour))
+				Expect(r.postRendererConfig).To(Equal([]string{"kustomize-v1"}))
+			})
+			It("should fail if the period is not positive", func() {
+				Expect(SkipUnchangedDryRuns(0)(r)).NotTo(Succeed())
+				Expect(SkipUnchangedDryRuns(-time.Hour)(r)).NotTo(Succeed())
+			})
+		})
+		_ = Describe("WithDependentEventFilter", func() {
+			It("should set the reconciler dependent event filter", func() {
+				Expect(WithDependentEventFilter(eventfilter.Config{
+					IgnoreFields:      []eventfilter.IgnoreField{{Kind: "Deployment.apps", Path: "spec.replicas"}},
+					ReconcileOnCreate: true,
+					UpdateDebounce:    metav1.Duration{Duration: time.Second},
+				})(r)).To(Succeed())
+				Expect(r.dependentEventFilter.ReconcileOnCreate()).To(BeTrue())
+				Expect(r.dependentEventFilter.UpdateDebounce()).To(Equal(time.Second))
+			})
+			It("should fail for invalid ignored fields", func() {
+				Expect(WithDependentEventFilter(eventfilter.Config{
+					IgnoreFields: []eventfilter.IgnoreField{{Path: "spec..replicas"}},
+				})(r)).NotTo(Succeed())
+				Expect(r.dependentEventFilter).To(BeNil())
+			})
+		})
+		_ = Describe("WithDependentResourceLabel", func() {
+			It("should set the reconciler dependent resource label", func() {
+				Expect(WithDependentResourceLabel("example.com/managed-by", "test-operator")(r)).To(Succeed())
+				Expect(r.dependentResourceLabel).To(Equal(labels.Set{"example.com/managed-by": "test-operator"}))
+			})
+			It("should fail for invalid labels", func() {
+				Expect(WithDependentResourceLabel("example.com/", "test-operator")(r)).NotTo(Succeed())
+				Expect(WithDependentResourceLabel("example.com/managed-by", "test operator")(r)).NotTo(Succeed())
+				Expect(r.dependentResourceLabel).To(BeNil())
+			})
+		})
 		_ = Describe("WithMaxConcurrentReconciles", func() {
 			It("should set the reconciler max concurrent reconciled", func() {
 				Expect(WithMaxConcurrentReconciles(1)(r)).To(Succeed())
@@ -406,10 +628,22 @@ var _ = Describe("Reconciler", func() {
 				})
 				Expect(WithPreHook(preHook)(r)).To(Succeed())
 				Expect(r.preHooks).To(HaveLen(1))
-				Expect(r.preHooks[0].Exec(nil, nil, logr.Discard())).To(Succeed())
+				_, err := r.preHooks[0].Exec(context.Background(), nil, nil, logr.Discard())
+				Expect(err).ToNot(HaveOccurred())
 				Expect(called).To(BeTrue())
 			})
 		})
+		_ = Describe("WithPreHookV2", func() {
+			It("should append a reconciler prehook", func() {
+				preHook := hook.FatalPreHook(func(context.Context, *unstructured.Unstructured, chartutil.Values, logr.Logger) (hook.PreHookResult, error) {
+					return hook.PreHookResult{}, nil
+				})
+				Expect(WithPreHook(hook.PreHookFunc(func(*unstructured.Unstructured, chartutil.Values, logr.Logger) error { return nil }))(r)).To(Succeed())
+				Expect(WithPreHookV2(preHook)(r)).To(Succeed())
+				Expect(r.preHooks).To(HaveLen(2))
+				Expect(r.preHooks[1].FailurePolicy()).To(Equal(hook.FailurePolicyFatal))
+			})
+		})
 		_ = Describe("WithPostHook", func() {
 			It("should set a reconciler posthook", func() {
 				called := false
@@ -419,10 +653,49 @@ var _ = Describe("Reconciler", func() {
 				})
 				Expect(WithPostHook(postHook)(r)).To(Succeed())
 				Expect(r.postHooks).To(HaveLen(1))
-				Expect(r.postHooks[0].Exec(nil, release.Release{}, logr.Discard())).To(Succeed())
+				Expect(r.postHooks[0].Exec(context.Background(), nil, release.Release{}, logr.Discard())).To(Succeed())
 				Expect(called).To(BeTrue())
 			})
 		})
+		_ = Describe("WithUpdateStrategy", func() {
+			It("should set the reconciler update strategy", func() {
+				Expect(WithUpdateStrategy(UpdateStrategyPatch)(r)).To(Succeed())
+				Expect(r.updateStrategy).To(Equal(UpdateStrategyPatch))
+			})
+			It("should fail for unknown strategies", func() {
+				Expect(WithUpdateStrategy("Apply")(r)).NotTo(Succeed())
+			})
+		})
+		_ = Describe("WithStatusMappings", func() {
+			It("should set the reconciler status mapper", func() {
+				Expect(WithStatusMappings(statusmapping.Mapping{APIVersion: "v1", Kind: "Service", JSONPath: ".status", Field: "service"})(r)).To(Succeed())
+				Expect(r.statusMapper).NotTo(BeNil())
+			})
+			It("should fail for invalid mappings", func() {
+				Expect(WithStatusMappings(statusmapping.Mapping{APIVersion: "v1", Kind: "Service", Field: "service"})(r)).NotTo(Succeed())
+				Expect(r.statusMapper).To(BeNil())
+			})
+		})
+		_ = Describe("WithPreUninstallHook", func() {
+			It("should set a reconciler pre-uninstall hook", func() {
+				h := hook.UninstallHookFunc(func(context.Context, *unstructured.Unstructured, *release.Release, logr.Logger) (hook.UninstallHookResult, error) {
+					return hook.UninstallHookResult{}, nil
+				})
+				Expect(WithPreUninstallHook(h)(r)).To(Succeed())
+				Expect(r.preUninstallHooks).To(HaveLen(1))
+				Expect(r.postUninstallHooks).To(BeEmpty())
+			})
+		})
+		_ = Describe("WithPostUninstallHook", func() {
+			It("should set a reconciler post-uninstall hook", func() {
+				h := hook.UninstallHookFunc(func(context.Context, *unstructured.Unstructured, *release.Release, logr.Logger) (hook.UninstallHookResult, error) {
+					return hook.UninstallHookResult{}, nil
+				})
+				Expect(WithPostUninstallHook(h)(r)).To(Succeed())
+				Expect(r.postUninstallHooks).To(HaveLen(1))
+				Expect(r.preUninstallHooks).To(BeEmpty())
+			})
+		})
 		_ = Describe("WithValueMapper", func() {
 			It("should set the reconciler value mapper", func() {
 				mapper := values.MapperFunc(func(chartutil.Values) chartutil.Values {
@@ -471,6 +744,24 @@ var _ = Describe("Reconciler", func() {
 		})
 	})
 
+	_ = Describe("PauseModeFromAnnotation", func() {
+		DescribeTable("reads the pause mode from the annotation",
+			func(annotations map[string]string, expected PauseMode) {
+				obj := &unstructured.Unstructured{}
+				obj.SetAnnotations(annotations)
+				mode, err := PauseModeFromAnnotation("my.domain/pause-reconcile")(context.Background(), obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mode).To(Equal(expected))
+			},
+			Entry("without the annotation", nil, PauseNone),
+			Entry("with false", map[string]string{"my.domain/pause-reconcile": "false"}, PauseNone),
+			Entry("with true", map[string]string{"my.domain/pause-reconcile": "true"}, PauseAll),
+			Entry("with All", map[string]string{"my.domain/pause-reconcile": "All"}, PauseAll),
+			Entry("with Upgrades", map[string]string{"my.domain/pause-reconcile": "Upgrades"}, PauseUpgrades),
+			Entry("with DriftCorrection", map[string]string{"my.domain/pause-reconcile": "DriftCorrection"}, PauseDriftCorrection),
+		)
+	})
+
 	_ = Describe("Reconcile", func() {
 		var (
 			obj    *unstructured.Unstructured
@@ -638,8 +929,8 @@ var _ = Describe("Reconciler", func() {
 						It("returns an error getting the release", func() {
 							By("creating a reconciler with a broken action client getter", func() {
 								r.actionClientGetter = helmclient.ActionClientGetterFunc(func(context.Context, client.Object) (helmclient.ActionInterface, error) {
-									cl := helmfake.NewActionClient()
-									return &cl, nil
+									cl := reconcilertesting.NewActionClient()
+									return cl, nil
 								})
 							})
 
@@ -826,14 +1117,14 @@ var _ = Describe("Reconciler", func() {
 					When("all install preconditions met", func() {
 						When("installation fails", func() {
 							BeforeEach(func() {
-								ac := helmfake.NewActionClient()
+								ac := reconcilertesting.NewActionClient()
 								ac.HandleGet = func() (*release.Release, error) {
 									return nil, driver.ErrReleaseNotFound
 								}
 								ac.HandleInstall = func() (*release.Release, error) {
 									return nil, errors.New("install failed: foobar")
 								}
-								r.actionClientGetter = helmfake.NewActionClientGetter(&ac, nil)
+								r.actionClientGetter = reconcilertesting.NewActionClientGetter(ac, nil)
 							})
 							It("handles the installation error", func() {
 								By("returning an error", func() {
@@ -920,6 +1211,119 @@ var _ = Describe("Reconciler", func() {
 								verifyHooksCalled(ctx, r, req)
 							})
 						})
+						When("hooks write the status", func() {
+							It("applies their changes with the reconciler's", func() {
+								By("adding hooks that write the status", func() {
+									Expect(WithPreHookV2(hook.PreHookV2Func(func(ctx context.Context, _ *unstructured.Unstructured, _ chartutil.Values, _ logr.Logger) (hook.PreHookResult, error) {
+										w := hook.StatusWriterFrom(ctx)
+										Expect(w.SetCondition(conditions.TypeDeployed, corev1.ConditionFalse, "", "")).To(HaveOccurred())
+										return hook.PreHookResult{}, w.SetCondition("LicenseValid", corev1.ConditionTrue, "LicenseFound", "")
+									}))(r)).To(Succeed())
+									Expect(WithPostHookV2(hook.PostHookV2Func(func(ctx context.Context, _ *unstructured.Unstructured, rel release.Release, _ logr.Logger) error {
+										w := hook.StatusWriterFrom(ctx)
+										Expect(w.SetField(map[string]int{"http": 80}, "endpoints", "web")).To(Succeed())
+										return w.SetField(rel.Version, "releaseVersion")
+									}))(r)).To(Succeed())
+								})
+
+								By("successfully reconciling a request", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(err).ToNot(HaveOccurred())
+									Expect(res).To(Equal(reconcile.Result{}))
+								})
+
+								By("verifying the CR status", func() {
+									Expect(mgr.GetAPIReader().Get(ctx, objKey, obj)).To(Succeed())
+									objStat := &objStatus{}
+									Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, objStat)).To(Succeed())
+									Expect(objStat.Status.Conditions.IsTrueFor(conditions.TypeDeployed)).To(BeTrue())
+									Expect(objStat.Status.Conditions.IsTrueFor("LicenseValid")).To(BeTrue())
+
+									custom, _, err := unstructured.NestedMap(obj.Object, "status", "custom")
+									Expect(err).ToNot(HaveOccurred())
+									Expect(custom).To(Equal(map[string]interface{}{
+										"endpoints":      map[string]interface{}{"web": map[string]interface{}{"http": int64(80)}},
+										"releaseVersion": int64(1),
+									}))
+								})
+							})
+						})
+						When("a pre-hook aborts", func() {
+							It("does not install the release", func() {
+								By("adding an aborting pre-hook", func() {
+									Expect(WithPreHookV2(hook.PreHookV2Func(func(context.Context, *unstructured.Unstructured, chartutil.Values, logr.Logger) (hook.PreHookResult, error) {
+										return hook.PreHookResult{}, &hook.AbortError{Reason: "LicenseMissing", Message: "no license", RequeueAfter: time.Minute}
+									}))(r)).To(Succeed())
+								})
+
+								By("successfully reconciling a request", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(err).ToNot(HaveOccurred())
+									Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+								})
+
+								By("verifying the release is not installed", func() {
+									_, err := ac.Get(obj.GetName())
+									Expect(err).To(MatchError(driver.ErrReleaseNotFound))
+								})
+
+								By("verifying the CR status", func() {
+									Expect(mgr.GetAPIReader().Get(ctx, objKey, obj)).To(Succeed())
+									objStat := &objStatus{}
+									Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, objStat)).To(Succeed())
+									c := objStat.Status.Conditions.GetCondition(conditions.TypeIrreconcilable)
+									Expect(c).NotTo(BeNil())
+									Expect(c.Status).To(Equal(corev1.ConditionTrue))
+									Expect(string(c.Reason)).To(Equal("LicenseMissing"))
+									Expect(c.Message).To(Equal("no license"))
+								})
+							})
+						})
+						When("a fatal pre-hook fails", func() {
+							It("returns the error", func() {
+								By("adding a failing fatal pre-hook", func() {
+									Expect(WithPreHookV2(hook.FatalPreHook(func(context.Context, *unstructured.Unstructured, chartutil.Values, logr.Logger) (hook.PreHookResult, error) {
+										return hook.PreHookResult{}, errors.New("secret not resolved")
+									}))(r)).To(Succeed())
+								})
+
+								By("reconciling unsuccessfully", func() {
+									_, err := r.Reconcile(ctx, req)
+									Expect(err).To(MatchError(ContainSubstring("secret not resolved")))
+								})
+
+								By("verifying the CR status", func() {
+									Expect(mgr.GetAPIReader().Get(ctx, objKey, obj)).To(Succeed())
+									objStat := &objStatus{}
+									Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, objStat)).To(Succeed())
+									c := objStat.Status.Conditions.GetCondition(conditions.TypeIrreconcilable)
+									Expect(c).NotTo(BeNil())
+									Expect(c.Reason).To(Equal(conditions.ReasonPreHookError))
+								})
+							})
+						})
+						When("a pre-hook modifies the values", func() {
+							It("installs the release with the modified values", func() {
+								By("adding a value-mutating pre-hook", func() {
+									Expect(WithPreHookV2(hook.PreHookV2Func(func(_ context.Context, _ *unstructured.Unstructured, vals chartutil.Values, _ logr.Logger) (hook.PreHookResult, error) {
+										vals["replicaCount"] = 3
+										return hook.PreHookResult{Values: vals, RequeueAfter: time.Minute}, nil
+									}))(r)).To(Succeed())
+								})
+
+								By("successfully reconciling a request", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(err).ToNot(HaveOccurred())
+									Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+								})
+
+								By("verifying the release values", func() {
+									rel, err := ac.Get(obj.GetName())
+									Expect(err).ToNot(HaveOccurred())
+									Expect(rel.Config).To(HaveKeyWithValue("replicaCount", BeNumerically("==", 3)))
+								})
+							})
+						})
 					})
 				})
 				When("requested CR release is present", func() {
@@ -976,8 +1380,8 @@ var _ = Describe("Reconciler", func() {
 						It("returns an error getting the release", func() {
 							By("creating a reconciler with a broken action client getter", func() {
 								r.actionClientGetter = helmclient.ActionClientGetterFunc(func(context.Context, client.Object) (helmclient.ActionInterface, error) {
-									cl := helmfake.NewActionClient()
-									return &cl, nil
+									cl := reconcilertesting.NewActionClient()
+									return cl, nil
 								})
 							})
 
@@ -1142,7 +1546,7 @@ var _ = Describe("Reconciler", func() {
 					When("state is Deployed", func() {
 						When("upgrade fails", func() {
 							BeforeEach(func() {
-								ac := helmfake.NewActionClient()
+								ac := reconcilertesting.NewActionClient()
 								ac.HandleGet = func() (*release.Release, error) {
 									return &release.Release{Name: "test", Version: 1, Manifest: "manifest: 1"}, nil
 								}
@@ -1154,7 +1558,7 @@ var _ = Describe("Reconciler", func() {
 									}
 									return nil, errors.New("upgrade failed: foobar")
 								}
-								r.actionClientGetter = helmfake.NewActionClientGetter(&ac, nil)
+								r.actionClientGetter = reconcilertesting.NewActionClientGetter(ac, nil)
 							})
 							It("handles the upgrade error", func() {
 								By("returning an error", func() {
@@ -1246,9 +1650,78 @@ var _ = Describe("Reconciler", func() {
 								})
 							})
 						})
+						When("upgrade is outside of the maintenance windows", func() {
+							It("defers the upgrade until the next window", func() {
+								By("adding an upgrade schedule handler to the Reconciler", func() {
+									handler := WithUpgradeScheduleHandler(UpgradeInMaintenanceWindows(MaintenanceWindowsAnnotation))
+									Expect(handler(r)).To(Succeed())
+								})
+
+								By("changing the CR outside of its maintenance window", func() {
+									Expect(mgr.GetClient().Get(ctx, objKey, obj)).To(Succeed())
+									hour := (time.Now().UTC().Hour() + 12) % 24
+									obj.SetAnnotations(map[string]string{MaintenanceWindowsAnnotation: fmt.Sprintf("CRON_TZ=UTC 0 %d * * * 1h", hour)})
+									obj.Object["spec"] = map[string]interface{}{"replicaCount": "2"}
+									Expect(mgr.GetClient().Update(ctx, obj)).To(Succeed())
+								})
+
+								By("successfully reconciling a request", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(err).ToNot(HaveOccurred())
+									Expect(res.RequeueAfter).To(BeNumerically(">", 10*time.Hour))
+									Expect(res.RequeueAfter).To(BeNumerically("<=", 12*time.Hour))
+								})
+
+								By("verifying the release has not changed", func() {
+									rel, err := ac.Get(obj.GetName())
+									Expect(err).ToNot(HaveOccurred())
+									Expect(rel).NotTo(BeNil())
+									Expect(*rel).To(Equal(*currentRelease))
+								})
+
+								By("verifying the CR status is UpgradeDeferred", func() {
+									Expect(mgr.GetAPIReader().Get(ctx, objKey, obj)).To(Succeed())
+									objStat := &objStatus{}
+									Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, objStat)).To(Succeed())
+									Expect(objStat.Status.Conditions.IsTrueFor(conditions.TypeDeployed)).To(BeTrue())
+									Expect(objStat.Status.Conditions.IsFalseFor(conditions.TypeIrreconcilable)).To(BeTrue())
+									c := objStat.Status.Conditions.GetCondition(conditions.TypeUpgradeDeferred)
+									Expect(c).NotTo(BeNil())
+									Expect(c.Status).To(Equal(corev1.ConditionTrue))
+									Expect(c.Reason).To(Equal(conditions.ReasonOutsideMaintenanceWindow))
+									Expect(c.Message).To(ContainSubstring("until the next maintenance window starts at"))
+								})
+
+								By("opening a maintenance window", func() {
+									Expect(mgr.GetClient().Get(ctx, objKey, obj)).To(Succeed())
+									obj.SetAnnotations(map[string]string{MaintenanceWindowsAnnotation: "CRON_TZ=UTC 0 * * * * 1h"})
+									Expect(mgr.GetClient().Update(ctx, obj)).To(Succeed())
+								})
+
+								By("successfully reconciling a request", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(res).To(Equal(reconcile.Result{}))
+									Expect(err).ToNot(HaveOccurred())
+								})
+
+								By("verifying the release is upgraded", func() {
+									rel, err := ac.Get(obj.GetName())
+									Expect(err).ToNot(HaveOccurred())
+									Expect(rel).NotTo(BeNil())
+									Expect(rel.Version).To(Equal(2))
+								})
+
+								By("verifying the upgrade is no longer deferred", func() {
+									Expect(mgr.GetAPIReader().Get(ctx, objKey, obj)).To(Succeed())
+									objStat := &objStatus{}
+									Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, objStat)).To(Succeed())
+									Expect(objStat.Status.Conditions.IsFalseFor(conditions.TypeUpgradeDeferred)).To(BeTrue())
+								})
+							})
+						})
 						When("reconciliation fails", func() {
 							BeforeEach(func() {
-								ac := helmfake.NewActionClient()
+								ac := reconcilertesting.NewActionClient()
 								ac.HandleGet = func() (*release.Release, error) {
 									return &release.Release{Name: "test", Version: 1, Manifest: "manifest: 1", Info: &release.Info{Status: release.StatusDeployed}}, nil
 								}
@@ -1258,7 +1731,7 @@ var _ = Describe("Reconciler", func() {
 								ac.HandleReconcile = func() error {
 									return errors.New("reconciliation failed: foobar")
 								}
-								r.actionClientGetter = helmfake.NewActionClientGetter(&ac, nil)
+								r.actionClientGetter = reconcilertesting.NewActionClientGetter(ac, nil)
 							})
 							It("handles the reconciliation error", func() {
 								By("returning an error", func() {
@@ -1359,14 +1832,14 @@ var _ = Describe("Reconciler", func() {
 						})
 						When("uninstall fails", func() {
 							BeforeEach(func() {
-								ac := helmfake.NewActionClient()
+								ac := reconcilertesting.NewActionClient()
 								ac.HandleGet = func() (*release.Release, error) {
 									return &release.Release{Name: "test", Version: 1, Manifest: "manifest: 1"}, nil
 								}
 								ac.HandleUninstall = func() (*release.UninstallReleaseResponse, error) {
 									return nil, errors.New("uninstall failed: foobar")
 								}
-								r.actionClientGetter = helmfake.NewActionClientGetter(&ac, nil)
+								r.actionClientGetter = reconcilertesting.NewActionClientGetter(ac, nil)
 							})
 							It("handles the uninstall error", func() {
 								By("deleting the CR", func() {
@@ -1431,6 +1904,69 @@ var _ = Describe("Reconciler", func() {
 								})
 							})
 						})
+						When("uninstall hooks delay the uninstall", func() {
+							It("runs the hooks and removes the finalizer once they are done", func() {
+								var preRelease, postRelease *release.Release
+								backupDone := false
+								By("adding uninstall hooks to the Reconciler", func() {
+									Expect(WithPreUninstallHook(hook.UninstallHookFunc(func(_ context.Context, _ *unstructured.Unstructured, rel *release.Release, _ logr.Logger) (hook.UninstallHookResult, error) {
+										preRelease = rel
+										if !backupDone {
+											return hook.UninstallHookResult{RequeueAfter: time.Minute, Message: "backup in progress"}, nil
+										}
+										return hook.UninstallHookResult{}, nil
+									}))(r)).To(Succeed())
+									Expect(WithPostUninstallHook(hook.UninstallHookFunc(func(_ context.Context, _ *unstructured.Unstructured, rel *release.Release, _ logr.Logger) (hook.UninstallHookResult, error) {
+										postRelease = rel
+										return hook.UninstallHookResult{}, nil
+									}))(r)).To(Succeed())
+								})
+
+								By("deleting the CR", func() {
+									Expect(mgr.GetClient().Delete(ctx, obj)).To(Succeed())
+								})
+
+								By("reconciling a request while the backup is in progress", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(err).ToNot(HaveOccurred())
+									Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+								})
+
+								By("verifying the release is not uninstalled and the CR is UninstallBlocked", func() {
+									Expect(preRelease).NotTo(BeNil())
+									Expect(preRelease.Name).To(Equal(currentRelease.Name))
+									_, err := ac.Get(obj.GetName())
+									Expect(err).ToNot(HaveOccurred())
+
+									Expect(mgr.GetAPIReader().Get(ctx, objKey, obj)).To(Succeed())
+									objStat := &objStatus{}
+									Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, objStat)).To(Succeed())
+									c := objStat.Status.Conditions.GetCondition(conditions.TypeUninstallBlocked)
+									Expect(c).NotTo(BeNil())
+									Expect(c.Status).To(Equal(corev1.ConditionTrue))
+									Expect(c.Reason).To(Equal(conditions.ReasonPreUninstallHookPending))
+									Expect(c.Message).To(Equal("backup in progress"))
+								})
+
+								By("reconciling a request after the backup is done", func() {
+									backupDone = true
+									res, err := r.Reconcile(ctx, req)
+									Expect(err).ToNot(HaveOccurred())
+									Expect(res).To(Equal(reconcile.Result{}))
+								})
+
+								By("verifying the release is uninstalled and the post-uninstall hook ran", func() {
+									verifyNoRelease(ctx, mgr.GetClient(), obj.GetNamespace(), obj.GetName(), currentRelease)
+									Expect(postRelease).NotTo(BeNil())
+									Expect(postRelease.Name).To(Equal(currentRelease.Name))
+								})
+
+								By("ensuring the finalizer is removed and the CR is deleted", func() {
+									err := mgr.GetAPIReader().Get(ctx, objKey, obj)
+									Expect(apierrors.IsNotFound(err)).To(BeTrue())
+								})
+							})
+						})
 						When("pause-reconcile annotation is present", func() {
 							It("pauses reconciliation", func() {
 								By("adding a pause-reconcile handler to the Reconciler", func() {
@@ -1494,6 +2030,60 @@ var _ = Describe("Reconciler", func() {
 								})
 							})
 						})
+						When("upgrades are paused", func() {
+							It("pauses upgrades only", func() {
+								By("adding a pause mode handler to the Reconciler", func() {
+									pauseHandler := WithPauseModeHandler(PauseModeFromAnnotation("my.domain/pause-reconcile"))
+									Expect(pauseHandler(r)).To(Succeed())
+								})
+
+								By("changing the CR while upgrades are paused", func() {
+									Expect(mgr.GetClient().Get(ctx, objKey, obj)).To(Succeed())
+									obj.SetAnnotations(map[string]string{"my.domain/pause-reconcile": string(PauseUpgrades)})
+									obj.Object["spec"] = map[string]interface{}{"replicaCount": "666"}
+									Expect(mgr.GetClient().Update(ctx, obj)).To(Succeed())
+								})
+
+								By("successfully reconciling a request", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(res).To(Equal(reconcile.Result{}))
+									Expect(err).ToNot(HaveOccurred())
+								})
+
+								By("verifying the release has not changed", func() {
+									rel, err := ac.Get(obj.GetName())
+									Expect(err).ToNot(HaveOccurred())
+									Expect(rel).NotTo(BeNil())
+									Expect(*rel).To(Equal(*currentRelease))
+								})
+
+								By("verifying the CR status is Paused for upgrades", func() {
+									Expect(mgr.GetAPIReader().Get(ctx, objKey, obj)).To(Succeed())
+									objStat := &objStatus{}
+									Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, objStat)).To(Succeed())
+									Expect(objStat.Status.Conditions.IsTrueFor(conditions.TypeDeployed)).To(BeTrue())
+									Expect(objStat.Status.Conditions.IsFalseFor(conditions.TypeIrreconcilable)).To(BeTrue())
+									c := objStat.Status.Conditions.GetCondition(conditions.TypePaused)
+									Expect(c).NotTo(BeNil())
+									Expect(c.Status).To(Equal(corev1.ConditionTrue))
+									Expect(c.Reason).To(Equal(conditions.ReasonUpgradesPaused))
+								})
+
+								By("deleting the CR", func() {
+									Expect(mgr.GetClient().Delete(ctx, obj)).To(Succeed())
+								})
+
+								By("successfully reconciling a request", func() {
+									res, err := r.Reconcile(ctx, req)
+									Expect(res).To(Equal(reconcile.Result{}))
+									Expect(err).ToNot(HaveOccurred())
+								})
+
+								By("verifying the release is uninstalled", func() {
+									verifyNoRelease(ctx, mgr.GetClient(), obj.GetNamespace(), obj.GetName(), currentRelease)
+								})
+							})
+						})
 					})
 				})
 			})
@@ -1855,8 +2445,8 @@ func verifyHooksCalled(ctx context.Context, r *Reconciler, req reconcile.Request
 			return errors.New("post hook foobar")
 		})
 		r.log = zap.New(zap.WriteTo(buf))
-		r.preHooks = append(r.preHooks, preHook)
-		r.postHooks = append(r.postHooks, postHook)
+		r.preHooks = append(r.preHooks, hook.AdaptPreHook(preHook))
+		r.postHooks = append(r.postHooks, hook.AdaptPostHook(postHook))
 	})
 	By("successfully reconciling a request", func() {
 		res, err := r.Reconcile(ctx, req)
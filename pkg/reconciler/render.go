@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+)
+
+// Render renders the release that the Reconciler would install for obj,
+// without contacting a cluster. It computes the values with the same
+// overrides, translator, mapper and chart defaults as Reconcile, applies the
+// install annotations of obj, and runs a client-only dry-run install.
+//
+// If the Reconciler was configured with WithActionClientGetter, the action
+// clients of that getter are used, so that their post-renderers are applied.
+// Otherwise, an offline action client getter is used, which applies the
+// default post-renderer.
+//
+// Options in opts are applied after the ones that configure the dry run, e.g.
+// to set the Kubernetes version the chart is rendered for.
+func (r *Reconciler) Render(ctx context.Context, obj *unstructured.Unstructured, opts ...helmclient.InstallOption) (*release.Release, error) {
+	actionClientGetter := r.actionClientGetter
+	if actionClientGetter == nil {
+		var err error
+		actionClientGetter, err = r.offlineActionClientGetter()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r.render(ctx, actionClientGetter, obj, opts...)
+}
+
+// offlineActionClientGetter returns an offline action client getter that
+// applies the post-renderers the Reconciler configures itself and never
+// uninstalls or rolls back releases when rendering fails.
+func (r *Reconciler) offlineActionClientGetter() (helmclient.ActionClientGetter, error) {
+	opts := []helmclient.ActionClientGetterOption{helmclient.WithFailureRollbacks(false)}
+	if lbls := r.dependentLabels(); len(lbls) > 0 {
+		opts = append(opts, helmclient.AppendPostRenderers(helmclient.LabelPostRendererFunc(lbls)))
+	}
+	return helmclient.NewOfflineActionClientGetter(opts...)
+}
+
+func (r *Reconciler) render(ctx context.Context, actionClientGetter helmclient.ActionClientGetter, obj *unstructured.Unstructured, opts ...helmclient.InstallOption) (*release.Release, error) {
+	r.addValueDefaults()
+
+	// Values are computed from a copy, since overrides are applied to the
+	// object itself.
+	obj = obj.DeepCopy()
+	vals, err := r.getValues(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("get values: %w", err)
+	}
+
+	actionClient, err := actionClientGetter.ActionClientFor(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("get action client: %w", err)
+	}
+
+	installOpts := []helmclient.InstallOption{func(i *action.Install) error {
+		i.DryRun = true
+		i.DryRunOption = "client"
+		i.ClientOnly = true
+		return nil
+	}}
+	for name, annot := range r.installAnnotations {
+		if v, ok := obj.GetAnnotations()[name]; ok {
+			installOpts = append(installOpts, annot.InstallOption(v))
+		}
+	}
+	installOpts = append(installOpts, opts...)
+
+	rel, err := actionClient.Install(obj.GetName(), obj.GetNamespace(), r.chrt, vals, installOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("render release: %w", err)
+	}
+	return rel, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/postrender"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+	"github.com/operator-framework/helm-operator-plugins/pkg/values"
+)
+
+var _ = Describe("Render", func() {
+	var obj *unstructured.Unstructured
+
+	BeforeEach(func() {
+		obj = testutil.BuildTestCR(gvk)
+		obj.Object["spec"] = map[string]interface{}{"replicaCount": int64(2)}
+	})
+
+	It("renders the release with the reconciler's values pipeline", func() {
+		r, err := New(
+			WithGroupVersionKind(gvk),
+			WithChart(chrt),
+			WithOverrideValues(map[string]string{"replicaCount": "5"}),
+			WithValueTranslator(values.TranslatorFunc(func(_ context.Context, u *unstructured.Unstructured) (chartutil.Values, error) {
+				spec := u.Object["spec"].(map[string]interface{})
+				return chartutil.Values{"replicaCount": spec["replicaCount"], "fullnameOverride": "translated"}, nil
+			})),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		rel, err := r.Render(context.Background(), obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rel.Manifest).To(ContainSubstring("name: translated\n"))
+		Expect(rel.Manifest).To(ContainSubstring("replicas: 5\n"))
+		Expect(rel.Manifest).To(ContainSubstring("uid: test-uid\n"))
+
+		By("not modifying the object", func() {
+			Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{"replicaCount": int64(2)}))
+		})
+	})
+
+	It("applies install options", func() {
+		r, err := New(WithGroupVersionKind(gvk), WithChart(chrt))
+		Expect(err).ToNot(HaveOccurred())
+
+		kubeVersion, err := chartutil.ParseKubeVersion("v1.20.0")
+		Expect(err).ToNot(HaveOccurred())
+		rel, err := r.Render(context.Background(), obj, func(i *action.Install) error {
+			i.KubeVersion = kubeVersion
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rel.Manifest).ToNot(BeEmpty())
+	})
+
+	It("applies the post-renderers of the configured action client getter", func() {
+		acg, err := helmclient.NewOfflineActionClientGetter(helmclient.AppendPostRenderers(
+			func(_ meta.RESTMapper, _ kube.Interface, _ client.Object) postrender.PostRenderer {
+				return helmclient.PostRendererFunc(func(in *bytes.Buffer) (*bytes.Buffer, error) {
+					return bytes.NewBufferString(in.String() + "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: post-rendered\n"), nil
+				})
+			},
+		))
+		Expect(err).ToNot(HaveOccurred())
+		r, err := New(WithGroupVersionKind(gvk), WithChart(chrt), WithActionClientGetter(acg))
+		Expect(err).ToNot(HaveOccurred())
+
+		rel, err := r.Render(context.Background(), obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rel.Manifest).To(ContainSubstring("name: post-rendered\n"))
+	})
+})
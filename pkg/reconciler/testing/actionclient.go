@@ -14,11 +14,14 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package fake
+// Package testing provides fakes, fixtures and an envtest-based harness for
+// testing operators that are built with the reconciler package.
+package testing
 
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -28,6 +31,8 @@ import (
 	"github.com/operator-framework/helm-operator-plugins/pkg/client"
 )
 
+// NewActionClientGetter returns an action client getter that returns
+// actionClient for every object, or orErr if it is not nil.
 func NewActionClientGetter(actionClient client.ActionInterface, orErr error) client.ActionClientGetter {
 	return &fakeActionClientGetter{
 		actionClient: actionClient,
@@ -49,6 +54,10 @@ func (hcg *fakeActionClientGetter) ActionClientFor(_ context.Context, _ crclient
 	return hcg.actionClient, nil
 }
 
+// ActionClient is a fake client.ActionInterface that records its calls and
+// answers them with its Handle functions. The handlers of a new ActionClient
+// return "not implemented" errors, so tests only need to script the actions
+// they expect.
 type ActionClient struct {
 	Gets       []GetCall
 	Histories  []HistoryCall
@@ -65,25 +74,20 @@ type ActionClient struct {
 	HandleUninstall func() (*release.UninstallReleaseResponse, error)
 	HandleReconcile func() error
 	HandleConfig    func() *action.Configuration
+
+	m sync.Mutex
 }
 
-func NewActionClient() ActionClient {
-	relFunc := func(err error) func() (*release.Release, error) {
-		return func() (*release.Release, error) { return nil, err }
-	}
-	historyFunc := func(err error) func() ([]*release.Release, error) {
-		return func() ([]*release.Release, error) { return nil, err }
-	}
-	uninstFunc := func(err error) func() (*release.UninstallReleaseResponse, error) {
-		return func() (*release.UninstallReleaseResponse, error) { return nil, err }
-	}
+// NewActionClient returns an ActionClient without recorded calls whose
+// handlers return errors.
+func NewActionClient() *ActionClient {
 	recFunc := func(err error) func() error {
 		return func() error { return err }
 	}
 	conFunc := func(conf *action.Configuration) func() *action.Configuration {
 		return func() *action.Configuration { return conf }
 	}
-	return ActionClient{
+	return &ActionClient{
 		Gets:       make([]GetCall, 0),
 		Histories:  make([]HistoryCall, 0),
 		Installs:   make([]InstallCall, 0),
@@ -92,11 +96,11 @@ func NewActionClient() ActionClient {
 		Reconciles: make([]ReconcileCall, 0),
 		Configs:    make([]ConfigCall, 0),
 
-		HandleGet:       relFunc(errors.New("get not implemented")),
-		HandleHistory:   historyFunc(errors.New("history not implemented")),
-		HandleInstall:   relFunc(errors.New("install not implemented")),
-		HandleUpgrade:   relFunc(errors.New("upgrade not implemented")),
-		HandleUninstall: uninstFunc(errors.New("uninstall not implemented")),
+		HandleGet:       Return[*release.Release](nil, errors.New("get not implemented")),
+		HandleHistory:   Return[[]*release.Release](nil, errors.New("history not implemented")),
+		HandleInstall:   Return[*release.Release](nil, errors.New("install not implemented")),
+		HandleUpgrade:   Return[*release.Release](nil, errors.New("upgrade not implemented")),
+		HandleUninstall: Return[*release.UninstallReleaseResponse](nil, errors.New("uninstall not implemented")),
 		HandleReconcile: recFunc(errors.New("reconcile not implemented")),
 		HandleConfig:    conFunc(nil),
 	}
@@ -104,6 +108,40 @@ func NewActionClient() ActionClient {
 
 var _ client.ActionInterface = &ActionClient{}
 
+// Response is a scripted response of an action.
+type Response[T any] struct {
+	Value T
+	Err   error
+}
+
+// Return returns a handler that always returns v and err.
+func Return[T any](v T, err error) func() (T, error) {
+	return func() (T, error) { return v, err }
+}
+
+// Script returns a handler that returns the given responses in order. Once
+// all responses have been returned, it keeps returning the last one. A
+// handler without responses returns the zero value.
+func Script[T any](responses ...Response[T]) func() (T, error) {
+	var (
+		m    sync.Mutex
+		next int
+	)
+	return func() (T, error) {
+		m.Lock()
+		defer m.Unlock()
+		if len(responses) == 0 {
+			var zero T
+			return zero, nil
+		}
+		r := responses[next]
+		if next < len(responses)-1 {
+			next++
+		}
+		return r.Value, r.Err
+	}
+}
+
 type GetCall struct {
 	Name string
 	Opts []client.GetOption
@@ -142,36 +180,50 @@ type ReconcileCall struct {
 type ConfigCall struct{}
 
 func (c *ActionClient) Get(name string, opts ...client.GetOption) (*release.Release, error) {
+	c.m.Lock()
 	c.Gets = append(c.Gets, GetCall{name, opts})
+	c.m.Unlock()
 	return c.HandleGet()
 }
 
 func (c *ActionClient) History(name string, opts ...client.HistoryOption) ([]*release.Release, error) {
+	c.m.Lock()
 	c.Histories = append(c.Histories, HistoryCall{name, opts})
+	c.m.Unlock()
 	return c.HandleHistory()
 }
 
 func (c *ActionClient) Install(name, namespace string, chrt *chart.Chart, vals map[string]interface{}, opts ...client.InstallOption) (*release.Release, error) {
+	c.m.Lock()
 	c.Installs = append(c.Installs, InstallCall{name, namespace, chrt, vals, opts})
+	c.m.Unlock()
 	return c.HandleInstall()
 }
 
 func (c *ActionClient) Upgrade(name, namespace string, chrt *chart.Chart, vals map[string]interface{}, opts ...client.UpgradeOption) (*release.Release, error) {
+	c.m.Lock()
 	c.Upgrades = append(c.Upgrades, UpgradeCall{name, namespace, chrt, vals, opts})
+	c.m.Unlock()
 	return c.HandleUpgrade()
 }
 
 func (c *ActionClient) Uninstall(name string, opts ...client.UninstallOption) (*release.UninstallReleaseResponse, error) {
+	c.m.Lock()
 	c.Uninstalls = append(c.Uninstalls, UninstallCall{name, opts})
+	c.m.Unlock()
 	return c.HandleUninstall()
 }
 
 func (c *ActionClient) Reconcile(rel *release.Release) error {
+	c.m.Lock()
 	c.Reconciles = append(c.Reconciles, ReconcileCall{rel})
+	c.m.Unlock()
 	return c.HandleReconcile()
 }
 
 func (c *ActionClient) Config() *action.Configuration {
+	c.m.Lock()
 	c.Configs = append(c.Configs, ConfigCall{})
+	c.m.Unlock()
 	return c.HandleConfig()
 }
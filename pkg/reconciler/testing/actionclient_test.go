@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/release"
+
+	reconcilertesting "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/testing"
+)
+
+var _ = Describe("ActionClient", func() {
+	It("should record calls and return errors for unscripted actions", func() {
+		ac := reconcilertesting.NewActionClient()
+		_, err := ac.Get("test")
+		Expect(err).To(MatchError("get not implemented"))
+		_, err = ac.Install("test", "default", nil, map[string]interface{}{"replicas": 2})
+		Expect(err).To(MatchError("install not implemented"))
+
+		Expect(ac.Gets).To(HaveLen(1))
+		Expect(ac.Gets[0].Name).To(Equal("test"))
+		Expect(ac.Installs).To(HaveLen(1))
+		Expect(ac.Installs[0].Namespace).To(Equal("default"))
+		Expect(ac.Installs[0].Values).To(Equal(map[string]interface{}{"replicas": 2}))
+	})
+
+	It("should return scripted responses in order and repeat the last one", func() {
+		rel := &release.Release{Name: "test", Version: 1}
+		ac := reconcilertesting.NewActionClient()
+		ac.HandleGet = reconcilertesting.Script(
+			reconcilertesting.Response[*release.Release]{Err: errors.New("not found")},
+			reconcilertesting.Response[*release.Release]{Value: rel},
+		)
+
+		_, err := ac.Get("test")
+		Expect(err).To(MatchError("not found"))
+		for range 2 {
+			got, err := ac.Get("test")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(got).To(Equal(rel))
+		}
+	})
+
+	It("should be returned by the action client getter", func() {
+		ac := reconcilertesting.NewActionClient()
+		got, err := reconcilertesting.NewActionClientGetter(ac, nil).ActionClientFor(context.Background(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got).To(BeIdenticalTo(ac))
+
+		_, err = reconcilertesting.NewActionClientGetter(ac, errors.New("broken")).ActionClientFor(context.Background(), nil)
+		Expect(err).To(MatchError("broken"))
+	})
+})
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler/internal/conditions"
+)
+
+// The types of the conditions that the reconciler sets.
+const (
+	ConditionInitialized      = conditions.TypeInitialized
+	ConditionDeployed         = conditions.TypeDeployed
+	ConditionReleaseFailed    = conditions.TypeReleaseFailed
+	ConditionIrreconcilable   = conditions.TypeIrreconcilable
+	ConditionPaused           = conditions.TypePaused
+	ConditionUpgradeDeferred  = conditions.TypeUpgradeDeferred
+	ConditionUninstallBlocked = conditions.TypeUninstallBlocked
+)
+
+// Condition is a condition in the status of a custom resource.
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetConditions returns the conditions in the status of obj.
+func GetConditions(obj *unstructured.Unstructured) ([]Condition, error) {
+	raw, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	conds := make([]Condition, 0, len(raw))
+	for i, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("condition %d is a %T, not an object", i, r)
+		}
+		var c Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &c); err != nil {
+			return nil, fmt.Errorf("decode condition %d: %v", i, err)
+		}
+		conds = append(conds, c)
+	}
+	return conds, nil
+}
+
+// GetCondition returns the condition of the given type in the status of obj.
+// It returns false if obj has no such condition.
+func GetCondition(obj *unstructured.Unstructured, conditionType string) (Condition, bool, error) {
+	conds, err := GetConditions(obj)
+	if err != nil {
+		return Condition{}, false, err
+	}
+	for _, c := range conds {
+		if c.Type == conditionType {
+			return c, true, nil
+		}
+	}
+	return Condition{}, false, nil
+}
+
+// HaveCondition succeeds if the actual *unstructured.Unstructured has a
+// condition of the given type with the given status, e.g. "True". If reason
+// is not empty, the reason of the condition must match as well.
+func HaveCondition(conditionType, status, reason string) types.GomegaMatcher {
+	return &conditionMatcher{want: Condition{Type: conditionType, Status: status, Reason: reason}}
+}
+
+type conditionMatcher struct {
+	want   Condition
+	actual []Condition
+}
+
+func (m *conditionMatcher) Match(actual interface{}) (bool, error) {
+	obj, ok := actual.(*unstructured.Unstructured)
+	if !ok {
+		return false, fmt.Errorf("HaveCondition expects an *unstructured.Unstructured, got %T", actual)
+	}
+	conds, err := GetConditions(obj)
+	if err != nil {
+		return false, err
+	}
+	m.actual = conds
+	for _, c := range conds {
+		if c.Type == m.want.Type {
+			return c.Status == m.want.Status && (m.want.Reason == "" || c.Reason == m.want.Reason), nil
+		}
+	}
+	return false, nil
+}
+
+func (m *conditionMatcher) FailureMessage(_ interface{}) string {
+	return format.Message(m.actual, "to contain condition", m.describe())
+}
+
+func (m *conditionMatcher) NegatedFailureMessage(_ interface{}) string {
+	return format.Message(m.actual, "not to contain condition", m.describe())
+}
+
+func (m *conditionMatcher) describe() string {
+	if m.want.Reason == "" {
+		return fmt.Sprintf("%s=%s", m.want.Type, m.want.Status)
+	}
+	return fmt.Sprintf("%s=%s (%s)", m.want.Type, m.want.Status, m.want.Reason)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	reconcilertesting "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/testing"
+)
+
+var _ = Describe("BuildCR", func() {
+	It("should apply options", func() {
+		obj := reconcilertesting.BuildCR(gvk,
+			reconcilertesting.WithName("app"),
+			reconcilertesting.WithSpec(map[string]interface{}{"replicaCount": int64(2)}),
+			reconcilertesting.WithAnnotations(map[string]string{"a": "b"}),
+		)
+		Expect(obj.GroupVersionKind()).To(Equal(gvk))
+		Expect(obj.GetName()).To(Equal("app"))
+		Expect(obj.GetNamespace()).To(Equal("default"))
+		Expect(obj.GetAnnotations()).To(Equal(map[string]string{"a": "b"}))
+		Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{"replicaCount": int64(2)}))
+	})
+})
+
+var _ = Describe("Conditions", func() {
+	var obj *unstructured.Unstructured
+
+	BeforeEach(func() {
+		obj = reconcilertesting.BuildCR(gvk)
+		obj.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Initialized", "status": "True"},
+				map[string]interface{}{"type": "Deployed", "status": "True", "reason": "InstallSuccessful", "message": "installed"},
+			},
+		}
+	})
+
+	It("should get conditions", func() {
+		c, ok, err := reconcilertesting.GetCondition(obj, reconcilertesting.ConditionDeployed)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(c).To(Equal(reconcilertesting.Condition{Type: "Deployed", Status: "True", Reason: "InstallSuccessful", Message: "installed"}))
+
+		_, ok, err = reconcilertesting.GetCondition(obj, reconcilertesting.ConditionIrreconcilable)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should match conditions", func() {
+		Expect(obj).To(reconcilertesting.HaveCondition(reconcilertesting.ConditionInitialized, "True", ""))
+		Expect(obj).To(reconcilertesting.HaveCondition(reconcilertesting.ConditionDeployed, "True", "InstallSuccessful"))
+		Expect(obj).ToNot(reconcilertesting.HaveCondition(reconcilertesting.ConditionDeployed, "True", "UpgradeSuccessful"))
+		Expect(obj).ToNot(reconcilertesting.HaveCondition(reconcilertesting.ConditionDeployed, "False", ""))
+		Expect(obj).ToNot(reconcilertesting.HaveCondition(reconcilertesting.ConditionIrreconcilable, "True", ""))
+	})
+})
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+)
+
+// Reconciler is a reconciler that can be run by an Environment, e.g. a
+// *reconciler.Reconciler.
+type Reconciler interface {
+	SetupWithManager(mgr ctrl.Manager) error
+}
+
+// Environment is an API server started with envtest to run reconcilers
+// against. Starting it requires the API server and etcd binaries, which
+// are located with the KUBEBUILDER_ASSETS environment variable.
+type Environment struct {
+	// Config is the REST config of the API server.
+	Config *rest.Config
+	// Client is a client of the API server that can read and write custom
+	// resources as *unstructured.Unstructured.
+	Client client.Client
+
+	env *envtest.Environment
+}
+
+// StartEnvironment starts an API server and installs a CRD built by BuildCRD
+// for each of the given kinds.
+func StartEnvironment(gvks ...schema.GroupVersionKind) (*Environment, error) {
+	env := &envtest.Environment{}
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("start test environment: %w", err)
+	}
+	e := &Environment{Config: cfg, env: env}
+
+	crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(gvks))
+	for _, gvk := range gvks {
+		crds = append(crds, BuildCRD(gvk))
+	}
+	if _, err := envtest.InstallCRDs(cfg, envtest.CRDInstallOptions{CRDs: crds}); err != nil {
+		return nil, errors.Join(fmt.Errorf("install CRDs: %w", err), env.Stop())
+	}
+	if e.Client, err = client.New(cfg, client.Options{}); err != nil {
+		return nil, errors.Join(fmt.Errorf("create client: %w", err), env.Stop())
+	}
+	return e, nil
+}
+
+// Stop stops the API server.
+func (e *Environment) Stop() error {
+	return e.env.Stop()
+}
+
+// ActionClientGetter returns an action client getter that installs releases
+// in the API server and stores them in s instead of in secrets.
+func (e *Environment) ActionClientGetter(s *MemoryStorage, opts ...helmclient.ActionClientGetterOption) (helmclient.ActionClientGetter, error) {
+	acg, err := helmclient.NewActionConfigGetter(e.Config, e.Client.RESTMapper(), helmclient.StorageDriverMapper(s.Driver))
+	if err != nil {
+		return nil, err
+	}
+	return helmclient.NewActionClientGetter(acg, opts...)
+}
+
+// Run sets up r with a new manager and starts it. The manager runs until ctx
+// is done or the returned function is called, which waits for the manager to
+// stop and returns its error.
+func (e *Environment) Run(ctx context.Context, r Reconciler) (func() error, error) {
+	// Since the dependent resource watcher of the reconciler uses the scheme
+	// of the manager, every manager gets its own scheme to avoid races.
+	sch := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(sch); err != nil {
+		return nil, err
+	}
+	mgr, err := manager.New(e.Config, manager.Options{
+		Metrics: metricsserver.Options{
+			BindAddress: "0",
+		},
+		Scheme: sch,
+		Controller: config.Controller{
+			SkipNameValidation: ptr.To(true),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create manager: %w", err)
+	}
+	if err := r.SetupWithManager(mgr); err != nil {
+		return nil, fmt.Errorf("set up reconciler: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- mgr.Start(ctx)
+	}()
+	return func() error {
+		cancel()
+		return <-done
+	}, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/reconciler"
+	reconcilertesting "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/testing"
+)
+
+var _ = Describe("Environment", Ordered, func() {
+	var env *reconcilertesting.Environment
+
+	BeforeAll(func() {
+		var err error
+		env, err = reconcilertesting.StartEnvironment(gvk)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(env.Stop)
+	})
+
+	It("should run a reconciler that installs the chart fixture", func(ctx context.Context) {
+		s := reconcilertesting.NewMemoryStorage()
+		acg, err := env.ActionClientGetter(s)
+		Expect(err).ToNot(HaveOccurred())
+		r, err := reconciler.New(
+			reconciler.WithGroupVersionKind(gvk),
+			reconciler.WithChart(*reconcilertesting.MustLoadChart("../../internal/testdata/test-chart-1.2.0.tgz")),
+			reconciler.WithActionClientGetter(acg),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		stop, err := env.Run(context.Background(), r)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(stop)
+
+		obj := reconcilertesting.BuildCR(gvk)
+		Expect(env.Client.Create(ctx, obj)).To(Succeed())
+		Eventually(func(g Gomega) {
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(gvk)
+			g.Expect(env.Client.Get(ctx, client.ObjectKeyFromObject(obj), u)).To(Succeed())
+			g.Expect(u).To(reconcilertesting.HaveCondition(reconcilertesting.ConditionDeployed, "True", "InstallSuccessful"))
+		}).WithContext(ctx).Should(Succeed())
+
+		rels, err := s.Releases(obj.GetNamespace())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rels).To(HaveLen(1))
+		Expect(rels[0].Name).To(Equal(obj.GetName()))
+	}, SpecTimeout(time.Minute))
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+)
+
+// BuildCRD returns a namespaced CRD for gvk with a status subresource whose
+// schema preserves unknown fields.
+func BuildCRD(gvk schema.GroupVersionKind) *apiextensionsv1.CustomResourceDefinition {
+	crd := testutil.BuildTestCRD(gvk)
+	crd.Spec.Versions[0].Name = gvk.Version
+	return &crd
+}
+
+// CROption configures a custom resource built by BuildCR.
+type CROption func(*unstructured.Unstructured)
+
+// WithName sets the name of the custom resource, which is "test" by default.
+func WithName(name string) CROption {
+	return func(obj *unstructured.Unstructured) {
+		obj.SetName(name)
+	}
+}
+
+// WithNamespace sets the namespace of the custom resource, which is "default"
+// by default.
+func WithNamespace(namespace string) CROption {
+	return func(obj *unstructured.Unstructured) {
+		obj.SetNamespace(namespace)
+	}
+}
+
+// WithSpec sets the spec, i.e. the values, of the custom resource.
+func WithSpec(spec map[string]interface{}) CROption {
+	return func(obj *unstructured.Unstructured) {
+		obj.Object["spec"] = spec
+	}
+}
+
+// WithAnnotations adds annotations to the custom resource.
+func WithAnnotations(annotations map[string]string) CROption {
+	return func(obj *unstructured.Unstructured) {
+		as := obj.GetAnnotations()
+		if as == nil {
+			as = map[string]string{}
+		}
+		for k, v := range annotations {
+			as[k] = v
+		}
+		obj.SetAnnotations(as)
+	}
+}
+
+// WithLabels adds labels to the custom resource.
+func WithLabels(labels map[string]string) CROption {
+	return func(obj *unstructured.Unstructured) {
+		ls := obj.GetLabels()
+		if ls == nil {
+			ls = map[string]string{}
+		}
+		for k, v := range labels {
+			ls[k] = v
+		}
+		obj.SetLabels(ls)
+	}
+}
+
+// BuildCR returns a custom resource of kind gvk named "test" in the "default"
+// namespace with an empty spec.
+func BuildCR(gvk schema.GroupVersionKind, opts ...CROption) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{},
+	}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName("test")
+	obj.SetNamespace("default")
+	for _, o := range opts {
+		o(obj)
+	}
+	return obj
+}
+
+// MustLoadChart loads the chart fixture at path, which may be a chart
+// directory or archive, and panics if it cannot be loaded.
+func MustLoadChart(path string) *chart.Chart {
+	chrt, err := loader.Load(path)
+	if err != nil {
+		panic(err)
+	}
+	return chrt
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+)
+
+// MemoryStorage stores releases in memory instead of in secrets. Releases are
+// stored in the namespace of the custom resource they belong to and are kept
+// until the MemoryStorage is garbage collected, so they survive across
+// reconciliations.
+type MemoryStorage struct {
+	m       sync.Mutex
+	drivers map[string]*driver.Memory
+}
+
+// NewMemoryStorage returns an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{drivers: map[string]*driver.Memory{}}
+}
+
+// Driver returns the storage driver for the namespace of obj. It is a
+// client.ObjectToStorageDriverMapper and can be passed to
+// client.StorageDriverMapper.
+func (s *MemoryStorage) Driver(_ context.Context, obj client.Object, _ *rest.Config) (driver.Driver, error) {
+	return s.driverFor(obj.GetNamespace()), nil
+}
+
+var _ helmclient.ObjectToStorageDriverMapper = (&MemoryStorage{}).Driver
+
+// Releases returns all revisions of all releases stored in namespace.
+func (s *MemoryStorage) Releases(namespace string) ([]*release.Release, error) {
+	return s.driverFor(namespace).List(func(*release.Release) bool { return true })
+}
+
+func (s *MemoryStorage) driverFor(namespace string) *driver.Memory {
+	s.m.Lock()
+	defer s.m.Unlock()
+	d, ok := s.drivers[namespace]
+	if !ok {
+		d = driver.NewMemory()
+		d.SetNamespace(namespace)
+		s.drivers[namespace] = d
+	}
+	return d
+}
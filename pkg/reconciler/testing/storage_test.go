@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+
+	reconcilertesting "github.com/operator-framework/helm-operator-plugins/pkg/reconciler/testing"
+)
+
+var _ = Describe("MemoryStorage", func() {
+	It("should keep the releases of each namespace", func() {
+		s := reconcilertesting.NewMemoryStorage()
+		for _, ns := range []string{"ns1", "ns2"} {
+			d, err := s.Driver(context.Background(), reconcilertesting.BuildCR(gvk, reconcilertesting.WithNamespace(ns)), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(storage.Init(d).Create(&release.Release{Name: "test-" + ns, Namespace: ns, Version: 1, Info: &release.Info{Status: release.StatusDeployed}})).To(Succeed())
+		}
+
+		d, err := s.Driver(context.Background(), reconcilertesting.BuildCR(gvk, reconcilertesting.WithNamespace("ns1")), nil)
+		Expect(err).ToNot(HaveOccurred())
+		rel, err := storage.Init(d).Deployed("test-ns1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rel.Namespace).To(Equal("ns1"))
+
+		rels, err := s.Releases("ns2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rels).To(HaveLen(1))
+		Expect(rels[0].Name).To(Equal("test-ns2"))
+	})
+})
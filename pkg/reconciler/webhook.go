@@ -0,0 +1,289 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WithValidatingWebhook is an Option that configures whether SetupWithManager
+// registers a validating admission webhook for the Reconciler's
+// GroupVersionKind with the manager's webhook server.
+//
+// The webhook rejects custom resources whose release cannot be rendered,
+// e.g. because their values do not match the chart's values schema or
+// because a template fails to render. See Validator for details.
+//
+// The webhook is served at the path that controller-runtime generates for
+// the GroupVersionKind, e.g. /validate-example-com-v1-testapp. A
+// ValidatingWebhookConfiguration pointing to it has to be created separately.
+func WithValidatingWebhook(enabled bool) Option {
+	return func(r *Reconciler) error {
+		r.validatingWebhook = enabled
+		return nil
+	}
+}
+
+// WithDefaultingWebhook is an Option that configures SetupWithManager to
+// register a mutating admission webhook for the Reconciler's
+// GroupVersionKind with the manager's webhook server.
+//
+// The webhook fills fields that are not set in the spec of custom resources
+// with the defaults from the chart's values, so that the effective
+// configuration is visible in the custom resources. If allowedPaths is not
+// empty, only the defaults at these dot-separated paths, e.g. "image.tag",
+// are filled. See Defaulter for details.
+//
+// The webhook is served at the path that controller-runtime generates for
+// the GroupVersionKind, e.g. /mutate-example-com-v1-testapp. A
+// MutatingWebhookConfiguration pointing to it has to be created separately.
+func WithDefaultingWebhook(allowedPaths ...string) Option {
+	return func(r *Reconciler) error {
+		for _, p := range allowedPaths {
+			if p == "" || strings.HasPrefix(p, ".") || strings.HasSuffix(p, ".") || strings.Contains(p, "..") {
+				return fmt.Errorf("invalid defaulting webhook path %q", p)
+			}
+		}
+		r.defaultingWebhook = true
+		r.defaultingWebhookPaths = allowedPaths
+		return nil
+	}
+}
+
+// Validator returns an admission.CustomValidator for the custom resources
+// of the Reconciler.
+//
+// On create and update, it computes the values of the custom resource with
+// the Reconciler's values pipeline and renders the release client-side, like
+// Render does. It always renders with an offline action client that never
+// touches the cluster, so the post-renderers of WithActionClientGetter are not
+// applied. Custom resources for which this fails are rejected with the error
+// of the failed step. Updates that do not change the spec and updates of
+// custom resources that are being deleted are always allowed, so that
+// finalizers and metadata of custom resources that were created before the
+// webhook was enabled can still be updated. Deletions are always allowed.
+func (r *Reconciler) Validator() admission.CustomValidator {
+	return &validator{r: r}
+}
+
+var _ admission.CustomValidator = (*validator)(nil)
+
+type validator struct {
+	r *Reconciler
+}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, obj)
+}
+
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldU, ok := oldObj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected unstructured object, got %T", oldObj)
+	}
+	newU, ok := newObj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected unstructured object, got %T", newObj)
+	}
+	if newU.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(oldU.Object["spec"], newU.Object["spec"]) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, newU)
+}
+
+func (v *validator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *validator) validate(ctx context.Context, obj runtime.Object) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("expected unstructured object, got %T", obj)
+	}
+	// The release is always rendered offline, so that a failure to render it
+	// cannot affect the release that is deployed for the custom resource.
+	actionClientGetter, err := v.r.offlineActionClientGetter()
+	if err != nil {
+		return err
+	}
+	if _, err := v.r.render(ctx, actionClientGetter, u); err != nil {
+		return apierrors.NewInvalid(u.GroupVersionKind().GroupKind(), u.GetName(), field.ErrorList{
+			field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error()),
+		})
+	}
+	return nil
+}
+
+// Defaulter returns an admission.CustomDefaulter for the custom resources of
+// the Reconciler.
+//
+// It fills the fields that are not set in the spec of a custom resource
+// with the defaults of the chart's values, including those of its
+// dependencies. Fields that are set, even if they are set to null, are left
+// unchanged, and maps that are set are filled recursively. Defaults that are
+// null are never filled. If the Reconciler was configured with allowed paths,
+// only the defaults at these paths are filled.
+//
+// The defaults are filled into the spec as is, so the Defaulter is only
+// meaningful if the Reconciler uses the spec as values, i.e. if its value
+// translator and mapper do not restructure the spec.
+func (r *Reconciler) Defaulter() admission.CustomDefaulter {
+	return &defaulter{r: r}
+}
+
+var _ admission.CustomDefaulter = (*defaulter)(nil)
+
+type defaulter struct {
+	r *Reconciler
+}
+
+func (d *defaulter) Default(_ context.Context, obj runtime.Object) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("expected unstructured object, got %T", obj)
+	}
+	if u.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	defaults, err := d.chartDefaults()
+	if err != nil {
+		return err
+	}
+
+	spec, ok := u.Object["spec"].(map[string]interface{})
+	if !ok {
+		if u.Object["spec"] != nil {
+			return nil
+		}
+		spec = map[string]interface{}{}
+	}
+	if len(d.r.defaultingWebhookPaths) == 0 {
+		fillDefaults(spec, defaults)
+	} else {
+		for _, path := range d.r.defaultingWebhookPaths {
+			fillDefaultsAtPath(spec, defaults, strings.Split(path, "."))
+		}
+	}
+	if len(spec) > 0 {
+		u.Object["spec"] = spec
+	}
+	return nil
+}
+
+// chartDefaults returns a deep copy of the coalesced values of the chart and
+// its dependencies, with the same number types as unstructured objects.
+func (d *defaulter) chartDefaults() (map[string]interface{}, error) {
+	vals, err := chartutil.CoalesceValues(d.r.chrt, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("coalesce chart values: %w", err)
+	}
+	data, err := json.Marshal(vals)
+	if err != nil {
+		return nil, fmt.Errorf("marshal chart values: %w", err)
+	}
+	var defaults map[string]interface{}
+	if err := utiljson.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("unmarshal chart values: %w", err)
+	}
+	return defaults, nil
+}
+
+// fillDefaults sets each key of defaults that is not set in dst, and fills
+// the maps that are set in both recursively. Null defaults are skipped, also
+// within maps.
+func fillDefaults(dst, defaults map[string]interface{}) {
+	for k, dv := range defaults {
+		if dv == nil {
+			continue
+		}
+		dm, dok := dv.(map[string]interface{})
+		v, ok := dst[k]
+		if !ok {
+			if !dok {
+				dst[k] = dv
+				continue
+			}
+			v = map[string]interface{}{}
+			dst[k] = v
+		}
+		if vm, vok := v.(map[string]interface{}); vok && dok {
+			fillDefaults(vm, dm)
+		}
+	}
+}
+
+// fillDefaultsAtPath fills the default at path into dst, creating the maps
+// along the path in dst as needed.
+func fillDefaultsAtPath(dst, defaults map[string]interface{}, path []string) {
+	k := path[0]
+	dv, ok := defaults[k]
+	if !ok || dv == nil {
+		return
+	}
+	if len(path) == 1 {
+		fillDefaults(dst, map[string]interface{}{k: dv})
+		return
+	}
+	dm, ok := dv.(map[string]interface{})
+	if !ok {
+		return
+	}
+	v, ok := dst[k]
+	if !ok {
+		v = map[string]interface{}{}
+	}
+	vm, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	fillDefaultsAtPath(vm, dm, path[1:])
+	if len(vm) > 0 {
+		dst[k] = vm
+	}
+}
+
+func (r *Reconciler) setupWebhooks(mgr ctrl.Manager) error {
+	if !r.validatingWebhook && !r.defaultingWebhook {
+		return nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(*r.gvk)
+	blder := ctrl.NewWebhookManagedBy(mgr).For(obj)
+	if r.validatingWebhook {
+		blder = blder.WithValidator(r.Validator())
+	}
+	if r.defaultingWebhook {
+		blder = blder.WithDefaulter(r.Defaulter())
+	}
+	if err := blder.Complete(); err != nil {
+		return fmt.Errorf("setting up webhooks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	jsonpatch "gomodules.xyz/jsonpatch/v2"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+	"github.com/operator-framework/helm-operator-plugins/pkg/internal/testutil"
+	"github.com/operator-framework/helm-operator-plugins/pkg/values"
+)
+
+// chartWithSchema returns a copy of chrt with a values schema that requires
+// replicaCount to be an integer.
+func chartWithSchema() *chart.Chart {
+	c := chrt
+	c.Schema = []byte(`{"type": "object", "properties": {"replicaCount": {"type": "integer"}}}`)
+	return &c
+}
+
+var _ = Describe("Validator", func() {
+	var (
+		r   *Reconciler
+		obj *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		var err error
+		r, err = New(WithGroupVersionKind(gvk), WithChart(*chartWithSchema()))
+		Expect(err).ToNot(HaveOccurred())
+
+		obj = testutil.BuildTestCR(gvk)
+		obj.Object["spec"] = map[string]interface{}{"replicaCount": int64(2)}
+	})
+
+	It("should allow custom resources whose release renders", func() {
+		_, err := r.Validator().ValidateCreate(context.Background(), obj)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should reject custom resources whose values do not match the chart's schema", func() {
+		obj.Object["spec"] = map[string]interface{}{"replicaCount": "many"}
+		_, err := r.Validator().ValidateCreate(context.Background(), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("replicaCount"))
+	})
+
+	It("should reject custom resources whose values cannot be computed", func() {
+		r, err := New(
+			WithGroupVersionKind(gvk),
+			WithChart(chrt),
+			WithValueTranslator(values.TranslatorFunc(func(context.Context, *unstructured.Unstructured) (chartutil.Values, error) {
+				return nil, errors.New("spec.replicaCount is too large")
+			})),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = r.Validator().ValidateCreate(context.Background(), obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.replicaCount is too large"))
+	})
+
+	It("should validate updates that change the spec", func() {
+		newObj := obj.DeepCopy()
+		newObj.Object["spec"] = map[string]interface{}{"replicaCount": "many"}
+		_, err := r.Validator().ValidateUpdate(context.Background(), obj, newObj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("should allow updates that do not change the spec", func() {
+		obj.Object["spec"] = map[string]interface{}{"replicaCount": "many"}
+		newObj := obj.DeepCopy()
+		newObj.SetFinalizers([]string{uninstallFinalizer})
+		_, err := r.Validator().ValidateUpdate(context.Background(), obj, newObj)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should allow updates of custom resources that are being deleted", func() {
+		newObj := obj.DeepCopy()
+		newObj.Object["spec"] = map[string]interface{}{"replicaCount": "many"}
+		now := metav1.Now()
+		newObj.SetDeletionTimestamp(&now)
+		_, err := r.Validator().ValidateUpdate(context.Background(), obj, newObj)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	When("rendering fails for a custom resource whose release is deployed", func() {
+		var (
+			mem       *driver.Memory
+			liveCalls int
+		)
+
+		BeforeEach(func() {
+			c := chrt
+			c.Templates = append([]*chart.File{{Name: "templates/fail.yaml", Data: []byte(`{{ if .Values.fail }}{{ fail "fail is set" }}{{ end }}`)}}, c.Templates...)
+
+			mem = driver.NewMemory()
+			mem.SetNamespace(obj.GetNamespace())
+			Expect(mem.Create("sh.helm.release.v1.test.v1", &release.Release{
+				Name:      obj.GetName(),
+				Namespace: obj.GetNamespace(),
+				Version:   1,
+				Info:      &release.Info{Status: release.StatusDeployed},
+				Chart:     &c,
+			})).To(Succeed())
+
+			// The action clients of this getter uninstall and roll back
+			// releases that fail, like those of the default getter.
+			acg, err := helmclient.NewActionConfigGetter(&rest.Config{Host: "http://localhost:0"}, helmclient.NewOfflineRESTMapper(),
+				helmclient.StorageDriverMapper(func(context.Context, client.Object, *rest.Config) (driver.Driver, error) {
+					return mem, nil
+				}),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			actionClientGetter, err := helmclient.NewActionClientGetter(acg)
+			Expect(err).ToNot(HaveOccurred())
+			liveCalls = 0
+			r, err = New(WithGroupVersionKind(gvk), WithChart(c), WithActionClientGetter(helmclient.ActionClientGetterFunc(
+				func(ctx context.Context, obj client.Object) (helmclient.ActionInterface, error) {
+					liveCalls++
+					return actionClientGetter.ActionClientFor(ctx, obj)
+				},
+			)))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		expectDeployedRelease := func() {
+			rel, err := mem.Get("sh.helm.release.v1.test.v1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rel.Info.Status).To(Equal(release.StatusDeployed))
+		}
+
+		It("should reject updates without touching the release", func() {
+			newObj := obj.DeepCopy()
+			newObj.Object["spec"] = map[string]interface{}{"fail": true}
+			_, err := r.Validator().ValidateUpdate(context.Background(), obj, newObj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("fail is set"))
+			Expect(liveCalls).To(BeZero())
+			expectDeployedRelease()
+		})
+
+		It("should not uninstall the release when rendering with the action client getter", func() {
+			obj.Object["spec"] = map[string]interface{}{"fail": true}
+			_, err := r.Render(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("fail is set")))
+			expectDeployedRelease()
+		})
+	})
+
+	It("should deny admission requests with a precise message", func() {
+		obj.Object["spec"] = map[string]interface{}{"replicaCount": "many"}
+		raw, err := json.Marshal(obj)
+		Expect(err).ToNot(HaveOccurred())
+
+		wh := admission.WithCustomValidator(runtime.NewScheme(), &unstructured.Unstructured{}, r.Validator())
+		resp := wh.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(BeEquivalentTo(422))
+		Expect(resp.Result.Message).To(ContainSubstring("replicaCount"))
+	})
+})
+
+var _ = Describe("Defaulter", func() {
+	var obj *unstructured.Unstructured
+
+	BeforeEach(func() {
+		obj = testutil.BuildTestCR(gvk)
+		obj.Object["spec"] = map[string]interface{}{"replicaCount": int64(2)}
+	})
+
+	newDefaulter := func(allowedPaths ...string) admission.CustomDefaulter {
+		GinkgoHelper()
+		r, err := New(WithGroupVersionKind(gvk), WithChart(chrt), WithDefaultingWebhook(allowedPaths...))
+		Expect(err).ToNot(HaveOccurred())
+		return r.Defaulter()
+	}
+
+	It("should fill unset fields with the chart's defaults", func() {
+		obj.Object["spec"] = map[string]interface{}{
+			"replicaCount": int64(2),
+			"image":        map[string]interface{}{"tag": "1.0"},
+			"resources":    nil,
+		}
+		Expect(newDefaulter().Default(context.Background(), obj)).To(Succeed())
+
+		spec := obj.Object["spec"].(map[string]interface{})
+		Expect(spec).To(HaveKeyWithValue("replicaCount", int64(2)))
+		Expect(spec).To(HaveKeyWithValue("image", map[string]interface{}{
+			"repository": "nginx",
+			"pullPolicy": "IfNotPresent",
+			"tag":        "1.0",
+		}))
+		Expect(spec).To(HaveKeyWithValue("resources", BeNil()))
+		Expect(spec).To(HaveKey("serviceAccount"))
+		Expect(spec["serviceAccount"]).To(HaveKeyWithValue("create", true))
+		Expect(spec["serviceAccount"]).ToNot(HaveKey("name"))
+	})
+
+	It("should only fill the defaults at the allowed paths", func() {
+		obj.Object["spec"] = nil
+		Expect(newDefaulter("image.repository", "replicaCount", "nonexistent.path").Default(context.Background(), obj)).To(Succeed())
+		Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{
+			"image":        map[string]interface{}{"repository": "nginx"},
+			"replicaCount": int64(1),
+		}))
+	})
+
+	It("should not modify custom resources that are being deleted", func() {
+		now := metav1.Now()
+		obj.SetDeletionTimestamp(&now)
+		Expect(newDefaulter().Default(context.Background(), obj)).To(Succeed())
+		Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{"replicaCount": int64(2)}))
+	})
+
+	It("should reject invalid allowed paths", func() {
+		_, err := New(WithGroupVersionKind(gvk), WithChart(chrt), WithDefaultingWebhook("image..tag"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should patch custom resources in admission requests", func() {
+		raw, err := json.Marshal(obj)
+		Expect(err).ToNot(HaveOccurred())
+
+		wh := admission.WithCustomDefaulter(runtime.NewScheme(), &unstructured.Unstructured{}, newDefaulter("image.repository"))
+		resp := wh.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(ConsistOf(jsonpatch.JsonPatchOperation{
+			Operation: "add",
+			Path:      "/spec/image",
+			Value:     map[string]interface{}{"repository": "nginx"},
+		}))
+	})
+})
+
+var _ = Describe("Webhooks", Ordered, func() {
+	var (
+		env    *envtest.Environment
+		cl     client.Client
+		cancel context.CancelFunc
+
+		webhookGVK = schema.GroupVersionKind{Group: "webhook.example.com", Version: "v1", Kind: "TestApp"}
+	)
+
+	BeforeAll(func() {
+		validatePath := "/validate-webhook-example-com-v1-testapp"
+		mutatePath := "/mutate-webhook-example-com-v1-testapp"
+		failurePolicy := admissionregistrationv1.Fail
+		sideEffects := admissionregistrationv1.SideEffectClassNone
+		crd := testutil.BuildTestCRD(webhookGVK)
+		rules := []admissionregistrationv1.RuleWithOperations{{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{webhookGVK.Group},
+				APIVersions: []string{webhookGVK.Version},
+				Resources:   []string{crd.Spec.Names.Plural},
+			},
+		}}
+		env = &envtest.Environment{
+			CRDs: []*apiextensionsv1.CustomResourceDefinition{&crd},
+			WebhookInstallOptions: envtest.WebhookInstallOptions{
+				MutatingWebhooks: []*admissionregistrationv1.MutatingWebhookConfiguration{{
+					ObjectMeta: metav1.ObjectMeta{Name: "testapp-defaulting"},
+					Webhooks: []admissionregistrationv1.MutatingWebhook{{
+						Name: "mtestapp.webhook.example.com",
+						ClientConfig: admissionregistrationv1.WebhookClientConfig{
+							Service: &admissionregistrationv1.ServiceReference{Name: "webhook", Namespace: "default", Path: &mutatePath},
+						},
+						Rules:                   rules,
+						FailurePolicy:           &failurePolicy,
+						SideEffects:             &sideEffects,
+						AdmissionReviewVersions: []string{"v1"},
+					}},
+				}},
+				ValidatingWebhooks: []*admissionregistrationv1.ValidatingWebhookConfiguration{{
+					ObjectMeta: metav1.ObjectMeta{Name: "testapp-validation"},
+					Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+						Name: "vtestapp.webhook.example.com",
+						ClientConfig: admissionregistrationv1.WebhookClientConfig{
+							Service: &admissionregistrationv1.ServiceReference{Name: "webhook", Namespace: "default", Path: &validatePath},
+						},
+						Rules:                   rules,
+						FailurePolicy:           &failurePolicy,
+						SideEffects:             &sideEffects,
+						AdmissionReviewVersions: []string{"v1"},
+					}},
+				}},
+			},
+		}
+		cfg, err := env.Start()
+		Expect(err).ToNot(HaveOccurred())
+
+		wio := env.WebhookInstallOptions
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Metrics: metricsserver.Options{BindAddress: "0"},
+			WebhookServer: webhook.NewServer(webhook.Options{
+				Host:    wio.LocalServingHost,
+				Port:    wio.LocalServingPort,
+				CertDir: wio.LocalServingCertDir,
+			}),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		r, err := New(
+			WithGroupVersionKind(webhookGVK),
+			WithChart(*chartWithSchema()),
+			WithValidatingWebhook(true),
+			WithDefaultingWebhook("image.repository"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.SetupWithManager(mgr)).To(Succeed())
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Expect(mgr.Start(ctx)).To(Succeed())
+		}()
+
+		addr := net.JoinHostPort(wio.LocalServingHost, strconv.Itoa(wio.LocalServingPort))
+		Eventually(func() error {
+			conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}).Should(Succeed())
+
+		cl, err = client.New(cfg, client.Options{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		cancel()
+		Expect(env.Stop()).To(Succeed())
+	})
+
+	newObj := func(name string, replicaCount interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"replicaCount": replicaCount},
+		}}
+		obj.SetGroupVersionKind(webhookGVK)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+
+	It("should admit valid custom resources", func() {
+		Expect(cl.Create(context.Background(), newObj("valid", int64(2)))).To(Succeed())
+	})
+
+	It("should fill the chart's defaults into custom resources", func() {
+		obj := newObj("defaulted", int64(2))
+		Expect(cl.Create(context.Background(), obj)).To(Succeed())
+		Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{
+			"image":        map[string]interface{}{"repository": "nginx"},
+			"replicaCount": int64(2),
+		}))
+	})
+
+	It("should reject invalid custom resources", func() {
+		err := cl.Create(context.Background(), newObj("invalid", "many"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("replicaCount"))
+	})
+
+	It("should reject updates that make a custom resource invalid", func() {
+		obj := newObj("updated", int64(2))
+		Expect(cl.Create(context.Background(), obj)).To(Succeed())
+
+		obj.Object["spec"] = map[string]interface{}{"replicaCount": "many"}
+		err := cl.Update(context.Background(), obj)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("%s %q is invalid", webhookGVK.Kind, "updated")))
+	})
+})
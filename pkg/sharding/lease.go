@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// observedLease records when a renewal of a Lease was first observed, so that
+// the expiry of Leases is measured with the local clock, like leader election
+// does, and is not affected by the clock skew between members.
+type observedLease struct {
+	renewTime  metav1.MicroTime
+	observedAt time.Time
+}
+
+// sync renews the Lease of the member and updates the members of the group
+// from the Leases of the group. It is only called by Start, so the fields
+// that it uses without holding the mutex are not accessed concurrently.
+func (s *Sharder) sync(ctx context.Context) {
+	now := s.clock.Now()
+	if err := s.renew(ctx, now); err != nil {
+		s.log.Error(err, "failed to renew lease")
+	} else {
+		s.renewedAt = now
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := s.reader.List(ctx, leases, client.InNamespace(s.namespace), client.MatchingLabels{GroupLabel: s.group}); err != nil {
+		s.log.Error(err, "failed to list leases")
+		// Without the Leases the other members are unknown, but this member
+		// must stop owning custom resources once its own Lease expired.
+		if !s.alive(now) {
+			s.setMembers(nil)
+		}
+		return
+	}
+
+	members := sets.New[string]()
+	observed := make(map[string]observedLease, len(leases.Items))
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+		if lease.Name == s.leaseName || holder == "" || lease.Spec.RenewTime == nil {
+			continue
+		}
+		o, ok := s.observed[lease.Name]
+		if !ok || !o.renewTime.Equal(lease.Spec.RenewTime) {
+			o = observedLease{renewTime: *lease.Spec.RenewTime, observedAt: now}
+		}
+		observed[lease.Name] = o
+
+		duration := s.leaseDuration
+		if seconds := ptr.Deref(lease.Spec.LeaseDurationSeconds, 0); seconds > 0 {
+			duration = time.Duration(seconds) * time.Second
+		}
+		switch expired := now.Sub(o.observedAt); {
+		case expired < duration:
+			members.Insert(holder)
+		case expired >= 2*duration:
+			s.deleteExpired(ctx, lease)
+		}
+	}
+	s.observed = observed
+
+	// A member that joins only owns custom resources after a renew period,
+	// so that the other members have seen its Lease by then and stopped
+	// reconciling the custom resources that move to it.
+	if s.alive(now) && now.Sub(s.joinedAt) >= s.renewPeriod {
+		members.Insert(s.identity)
+	}
+	s.setMembers(sets.List(members))
+}
+
+func (s *Sharder) alive(now time.Time) bool {
+	return !s.renewedAt.IsZero() && now.Sub(s.renewedAt) < s.leaseDuration
+}
+
+func (s *Sharder) renew(ctx context.Context, now time.Time) error {
+	renewTime := metav1.NewMicroTime(now)
+	lease := &coordinationv1.Lease{}
+	err := s.reader.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.leaseName}, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.namespace,
+				Name:      s.leaseName,
+				Labels:    map[string]string{GroupLabel: s.group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.identity),
+				LeaseDurationSeconds: ptr.To(int32(s.leaseDuration / time.Second)),
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		if err := s.client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("create lease: %w", err)
+		}
+		s.joinedAt = now
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get lease: %w", err)
+	}
+
+	// The Lease may be left over from a previous run of a member with the
+	// same identity, which is taken over.
+	if s.joinedAt.IsZero() {
+		s.joinedAt = now
+		lease.Spec.AcquireTime = &renewTime
+	}
+	if lease.Labels == nil {
+		lease.Labels = map[string]string{}
+	}
+	lease.Labels[GroupLabel] = s.group
+	lease.Spec.HolderIdentity = ptr.To(s.identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.leaseDuration / time.Second))
+	lease.Spec.RenewTime = &renewTime
+	if err := s.client.Update(ctx, lease); err != nil {
+		return fmt.Errorf("update lease: %w", err)
+	}
+	return nil
+}
+
+// deleteExpired deletes the Lease of a member that has not renewed it for
+// twice its duration, e.g. because its pod was deleted without leaving the
+// group, unless the Lease was renewed in the meantime.
+func (s *Sharder) deleteExpired(ctx context.Context, lease *coordinationv1.Lease) {
+	err := s.client.Delete(ctx, lease, client.Preconditions{UID: &lease.UID, ResourceVersion: &lease.ResourceVersion})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		s.log.Error(err, "failed to delete expired lease", "lease", lease.Name)
+		return
+	}
+	s.log.V(1).Info("deleted expired lease", "lease", lease.Name, "holder", ptr.Deref(lease.Spec.HolderIdentity, ""))
+}
+
+// leave stops owning custom resources and deletes the Lease of the member.
+func (s *Sharder) leave() {
+	s.setMembers(nil)
+	if s.joinedAt.IsZero() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.renewPeriod)
+	defer cancel()
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.leaseName}}
+	if err := s.client.Delete(ctx, lease); err != nil && !apierrors.IsNotFound(err) {
+		s.log.Error(err, "failed to delete lease")
+		return
+	}
+	s.log.Info("left shard group")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Lease membership", func() {
+	var (
+		ctx   context.Context
+		cl    client.Client
+		clock *clocktesting.FakeClock
+		s     *Sharder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cl = fake.NewClientBuilder().Build()
+		clock = clocktesting.NewFakeClock(time.Now())
+		var err error
+		s, err = New(cl, cl, "pod-a", "operators", WithGroup("nginx"), WithClock(clock))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	getLease := func(name string) (*coordinationv1.Lease, error) {
+		lease := &coordinationv1.Lease{}
+		return lease, cl.Get(ctx, client.ObjectKey{Namespace: "operators", Name: name}, lease)
+	}
+
+	createLease := func(name, holder string, renewTime time.Time) *coordinationv1.Lease {
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "operators",
+				Name:      name,
+				Labels:    map[string]string{GroupLabel: "nginx"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(holder),
+				LeaseDurationSeconds: ptr.To(int32(15)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(renewTime)),
+			},
+		}
+		Expect(cl.Create(ctx, lease)).To(Succeed())
+		return lease
+	}
+
+	It("creates its lease and joins the group after a renew period", func() {
+		s.sync(ctx)
+		lease, err := getLease("nginx-pod-a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lease.Labels).To(HaveKeyWithValue(GroupLabel, "nginx"))
+		Expect(lease.Spec.HolderIdentity).To(Equal(ptr.To("pod-a")))
+		Expect(lease.Spec.LeaseDurationSeconds).To(Equal(ptr.To(int32(15))))
+		Expect(s.Members()).To(BeEmpty())
+
+		clock.Step(DefaultRenewPeriod)
+		s.sync(ctx)
+		Expect(s.Members()).To(Equal([]string{"pod-a"}))
+		renewed, err := getLease("nginx-pod-a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewed.Spec.RenewTime.Time).To(BeTemporally(">", lease.Spec.RenewTime.Time))
+	})
+
+	It("takes over a lease that is left over from a previous run", func() {
+		createLease("nginx-pod-a", "pod-a", clock.Now().Add(-time.Hour))
+		s.sync(ctx)
+		Expect(s.Members()).To(BeEmpty())
+		clock.Step(DefaultRenewPeriod)
+		s.sync(ctx)
+		Expect(s.Members()).To(Equal([]string{"pod-a"}))
+	})
+
+	It("ignores the leases of other groups", func() {
+		other := createLease("other-pod-b", "pod-b", clock.Now())
+		other.Labels[GroupLabel] = "other"
+		Expect(cl.Update(ctx, other)).To(Succeed())
+		s.sync(ctx)
+		clock.Step(DefaultRenewPeriod)
+		s.sync(ctx)
+		Expect(s.Members()).To(Equal([]string{"pod-a"}))
+	})
+
+	It("follows the members that renew their leases", func() {
+		lease := createLease("nginx-pod-b", "pod-b", clock.Now())
+		s.sync(ctx)
+		Expect(s.Members()).To(Equal([]string{"pod-b"}))
+
+		By("keeping a member that renews its lease")
+		clock.Step(10 * time.Second)
+		lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(clock.Now()))
+		Expect(cl.Update(ctx, lease)).To(Succeed())
+		s.sync(ctx)
+		Expect(s.Members()).To(Equal([]string{"pod-a", "pod-b"}))
+		clock.Step(10 * time.Second)
+		s.sync(ctx)
+		Expect(s.Members()).To(Equal([]string{"pod-a", "pod-b"}))
+
+		By("removing a member whose lease expired")
+		clock.Step(5 * time.Second)
+		s.sync(ctx)
+		Expect(s.Members()).To(Equal([]string{"pod-a"}))
+		_, err := getLease("nginx-pod-b")
+		Expect(err).ToNot(HaveOccurred())
+
+		By("deleting a lease that expired twice its duration ago")
+		clock.Step(15 * time.Second)
+		s.sync(ctx)
+		_, err = getLease("nginx-pod-b")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("leaves the group when it is stopped", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() {
+			done <- s.Start(ctx)
+		}()
+		Eventually(func() error {
+			_, err := getLease("nginx-pod-a")
+			return err
+		}).Should(Succeed())
+		Eventually(clock.HasWaiters).Should(BeTrue())
+		clock.Step(DefaultRenewPeriod)
+		Eventually(s.Members).Should(Equal([]string{"pod-a"}))
+
+		By("waiting for the context when started again")
+		again := make(chan error)
+		go func() {
+			again <- s.Start(ctx)
+		}()
+		Consistently(again).ShouldNot(Receive())
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Eventually(again).Should(Receive(BeNil()))
+		Expect(s.Members()).To(BeEmpty())
+		_, err := getLease("nginx-pod-a")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// ErrNotOwner is returned by Lock if the custom resource is not assigned
+	// to this member.
+	ErrNotOwner = errors.New("sharding: custom resource is not in the shard of this member")
+
+	// ErrLocked is returned by Lock if another live member holds the lock of
+	// the custom resource, e.g. because it has not finished an action that it
+	// started before the custom resource moved to this member.
+	ErrLocked = errors.New("sharding: custom resource is locked by another member")
+)
+
+// heldLock is a lock of a custom resource that this member holds, and the
+// number of callers of Lock that have not unlocked it yet.
+type heldLock struct {
+	lease  *coordinationv1.Lease
+	active int
+}
+
+// Lock locks the custom resource with the given key for this member, so that
+// a member that still acts on it after it moved to another member, e.g.
+// because the members briefly disagree about the members of the group, is
+// fenced off. The lock is a Lease in the namespace of the group, which is
+// held until this member no longer owns the custom resource and no caller
+// holds the lock anymore, or until Release is called.
+//
+// Lock returns ErrNotOwner if this member does not own the custom resource,
+// and ErrLocked if another live member holds its lock. The lock of a member
+// that left the group is taken over.
+func (s *Sharder) Lock(ctx context.Context, key types.NamespacedName) (unlock func(), err error) {
+	if !s.Owns(key) {
+		return nil, ErrNotOwner
+	}
+	unlock = func() { s.unlock(key) }
+
+	s.locksMu.Lock()
+	if l, ok := s.locks[key]; ok {
+		l.active++
+		s.locksMu.Unlock()
+		return unlock, nil
+	}
+	s.locksMu.Unlock()
+
+	lease, err := s.acquire(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	s.locksMu.Lock()
+	defer s.locksMu.Unlock()
+	if l, ok := s.locks[key]; ok {
+		l.active++
+	} else {
+		s.locks[key] = &heldLock{lease: lease, active: 1}
+	}
+	return unlock, nil
+}
+
+// Release deletes the lock of the custom resource with the given key if no
+// caller holds it, e.g. after the custom resource was deleted.
+func (s *Sharder) Release(ctx context.Context, key types.NamespacedName) {
+	s.locksMu.Lock()
+	l, ok := s.locks[key]
+	if !ok || l.active > 0 {
+		s.locksMu.Unlock()
+		return
+	}
+	delete(s.locks, key)
+	s.locksMu.Unlock()
+	s.deleteLock(ctx, key, l.lease)
+}
+
+func (s *Sharder) unlock(key types.NamespacedName) {
+	s.locksMu.Lock()
+	l, ok := s.locks[key]
+	if !ok {
+		s.locksMu.Unlock()
+		return
+	}
+	l.active--
+	if l.active > 0 || s.Owns(key) {
+		s.locksMu.Unlock()
+		return
+	}
+	delete(s.locks, key)
+	s.locksMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.renewPeriod)
+	defer cancel()
+	s.deleteLock(ctx, key, l.lease)
+}
+
+// releaseUnowned deletes the locks of the custom resources that this member
+// no longer owns and that no caller holds. The locks that are still held are
+// deleted when they are unlocked.
+func (s *Sharder) releaseUnowned() {
+	released := map[types.NamespacedName]*coordinationv1.Lease{}
+	s.locksMu.Lock()
+	for key, l := range s.locks {
+		if l.active == 0 && !s.Owns(key) {
+			released[key] = l.lease
+			delete(s.locks, key)
+		}
+	}
+	s.locksMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.renewPeriod)
+	defer cancel()
+	for key, lease := range released {
+		s.deleteLock(ctx, key, lease)
+	}
+}
+
+// acquire creates the lock Lease of the custom resource with the given key, or
+// takes it over if its holder is this member or not a live member.
+func (s *Sharder) acquire(ctx context.Context, key types.NamespacedName) (*coordinationv1.Lease, error) {
+	now := metav1.NewMicroTime(s.clock.Now())
+	name := s.lockName(key)
+	lease := &coordinationv1.Lease{}
+	err := s.reader.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: name}, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   s.namespace,
+				Name:        name,
+				Labels:      map[string]string{LockLabel: s.group},
+				Annotations: map[string]string{LockObjectAnnotation: key.String()},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity: ptr.To(s.identity),
+				AcquireTime:    &now,
+				RenewTime:      &now,
+			},
+		}
+		if err := s.client.Create(ctx, lease); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return nil, ErrLocked
+			}
+			return nil, fmt.Errorf("create lock lease: %w", err)
+		}
+		s.log.V(1).Info("locked custom resource", "object", key, "lease", name)
+		return lease, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get lock lease: %w", err)
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder != s.identity && slices.Contains(s.Members(), holder) {
+		return nil, fmt.Errorf("%w %q", ErrLocked, holder)
+	}
+	lease.Spec.HolderIdentity = ptr.To(s.identity)
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+	if err := s.client.Update(ctx, lease); err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("update lock lease: %w", err)
+	}
+	s.log.V(1).Info("took over lock of custom resource", "object", key, "lease", name, "previousHolder", holder)
+	return lease, nil
+}
+
+// deleteLock deletes the lock Lease of the custom resource with the given
+// key, unless another member took it over in the meantime.
+func (s *Sharder) deleteLock(ctx context.Context, key types.NamespacedName, lease *coordinationv1.Lease) {
+	err := s.client.Delete(ctx, lease, client.Preconditions{UID: &lease.UID, ResourceVersion: &lease.ResourceVersion})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		s.log.Error(err, "failed to delete lock lease", "object", key, "lease", lease.Name)
+		return
+	}
+	s.log.V(1).Info("released lock of custom resource", "object", key, "lease", lease.Name)
+}
+
+// lockName returns the name of the lock Lease of the custom resource with the
+// given key. Keys are hashed, since they may be longer than a name.
+func (s *Sharder) lockName(key types.NamespacedName) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key.String()))
+	return fmt.Sprintf("%s-lock-%016x", s.group, h.Sum64())
+}
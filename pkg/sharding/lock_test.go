@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Lock", func() {
+	var (
+		ctx  context.Context
+		cl   client.Client
+		a, b *Sharder
+		key  types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cl = fake.NewClientBuilder().Build()
+		var err error
+		a, err = New(cl, cl, "pod-a", "operators", WithGroup("nginx"))
+		Expect(err).ToNot(HaveOccurred())
+		b, err = New(cl, cl, "pod-b", "operators", WithGroup("nginx"))
+		Expect(err).ToNot(HaveOccurred())
+
+		// key is owned by pod-a until pod-b joins the group.
+		for _, k := range keys(100) {
+			if Assign(k, []string{"pod-a", "pod-b"}) == "pod-b" {
+				key = k
+				break
+			}
+		}
+		a.setMembers([]string{"pod-a"})
+	})
+
+	getLock := func(s *Sharder) (*coordinationv1.Lease, error) {
+		lease := &coordinationv1.Lease{}
+		return lease, cl.Get(ctx, client.ObjectKey{Namespace: "operators", Name: s.lockName(key)}, lease)
+	}
+
+	It("only locks custom resources of its shard", func() {
+		b.setMembers([]string{"pod-a"})
+		_, err := b.Lock(ctx, key)
+		Expect(err).To(MatchError(ErrNotOwner))
+		_, err = getLock(b)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("holds the lock until the custom resource moves to another member", func() {
+		unlock, err := a.Lock(ctx, key)
+		Expect(err).ToNot(HaveOccurred())
+		lease, err := getLock(a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lease.Labels).To(HaveKeyWithValue(LockLabel, "nginx"))
+		Expect(lease.Annotations).To(HaveKeyWithValue(LockObjectAnnotation, key.String()))
+		Expect(lease.Spec.HolderIdentity).To(Equal(ptr.To("pod-a")))
+
+		By("keeping the lock after it is unlocked")
+		unlock()
+		_, err = getLock(a)
+		Expect(err).ToNot(HaveOccurred())
+		unlock, err = a.Lock(ctx, key)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("fencing off the new owner until the previous owner unlocks")
+		a.setMembers([]string{"pod-a", "pod-b"})
+		b.setMembers([]string{"pod-a", "pod-b"})
+		_, err = b.Lock(ctx, key)
+		Expect(err).To(MatchError(ErrLocked))
+		unlock()
+		_, err = getLock(a)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		unlock, err = b.Lock(ctx, key)
+		Expect(err).ToNot(HaveOccurred())
+		defer unlock()
+		lease, err = getLock(b)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lease.Spec.HolderIdentity).To(Equal(ptr.To("pod-b")))
+	})
+
+	It("releases unlocked locks when the custom resource moves to another member", func() {
+		unlock, err := a.Lock(ctx, key)
+		Expect(err).ToNot(HaveOccurred())
+		unlock()
+
+		a.setMembers([]string{"pod-a", "pod-b"})
+		_, err = getLock(a)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("takes over the lock of a member that left the group", func() {
+		_, err := a.Lock(ctx, key)
+		Expect(err).ToNot(HaveOccurred())
+
+		b.setMembers([]string{"pod-b"})
+		unlock, err := b.Lock(ctx, key)
+		Expect(err).ToNot(HaveOccurred())
+		defer unlock()
+		lease, err := getLock(b)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lease.Spec.HolderIdentity).To(Equal(ptr.To("pod-b")))
+	})
+
+	It("deletes the lock when it is released", func() {
+		unlock, err := a.Lock(ctx, key)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("keeping a lock that is held")
+		a.Release(ctx, key)
+		_, err = getLock(a)
+		Expect(err).ToNot(HaveOccurred())
+
+		unlock()
+		a.Release(ctx, key)
+		_, err = getLock(a)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,380 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding assigns custom resources to the replicas of an operator,
+// so that all replicas reconcile a share of the custom resources instead of a
+// single leader reconciling all of them.
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// GroupLabel is the label of the Leases of the members of a shard group,
+	// whose value is the name of the group.
+	GroupLabel = "helm.sdk.operatorframework.io/shard-group"
+
+	// ShardLabel is the label of the custom resources and their dependent
+	// resources, whose value is the identity of the member that owns them.
+	ShardLabel = "helm.sdk.operatorframework.io/shard"
+
+	// LockLabel is the label of the Leases that lock custom resources for a
+	// member of a shard group, whose value is the name of the group.
+	LockLabel = "helm.sdk.operatorframework.io/shard-lock-group"
+
+	// LockObjectAnnotation is the annotation of the Leases that lock custom
+	// resources, whose value is the namespace and name of the custom
+	// resource.
+	LockObjectAnnotation = "helm.sdk.operatorframework.io/shard-lock-object"
+
+	DefaultGroup         = "helm-operator"
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewPeriod   = 5 * time.Second
+)
+
+// Option configures a Sharder.
+type Option func(*Sharder)
+
+// WithGroup sets the name of the shard group, which must be unique among the
+// operators that shard in the same namespace. It defaults to DefaultGroup.
+func WithGroup(group string) Option {
+	return func(s *Sharder) {
+		s.group = group
+	}
+}
+
+// WithLeaseDuration sets how long a member is considered alive after it last
+// renewed its Lease. It defaults to DefaultLeaseDuration.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(s *Sharder) {
+		s.leaseDuration = d
+	}
+}
+
+// WithRenewPeriod sets how often the Lease of the member is renewed and the
+// members are listed. It defaults to DefaultRenewPeriod.
+func WithRenewPeriod(d time.Duration) Option {
+	return func(s *Sharder) {
+		s.renewPeriod = d
+	}
+}
+
+// WithLogger sets the logger of the Sharder.
+func WithLogger(log logr.Logger) Option {
+	return func(s *Sharder) {
+		s.log = log
+	}
+}
+
+// WithClock sets the clock of the Sharder, e.g. for tests.
+func WithClock(c clock.WithTicker) Option {
+	return func(s *Sharder) {
+		s.clock = c
+	}
+}
+
+// Sharder assigns custom resources to the members of a shard group, i.e. the
+// replicas of an operator. Each member holds a Lease in the namespace of the
+// group that it renews while it runs, and custom resources are assigned to
+// the live members by rendezvous hashing of their namespace and name, so that
+// only the custom resources of a member that joins or leaves the group move
+// to other members.
+//
+// A Sharder must be added to the manager, which must not use leader
+// election, since every replica reconciles its shard. Until the Sharder has
+// joined the group, it owns no custom resources.
+//
+// The owner of a custom resource records its identity in the ShardLabel of
+// the custom resource and its dependent resources, so that the caches of each
+// member only hold the objects of its shard, see Selector.
+//
+// Members may briefly disagree about the members of the group, e.g. when the
+// Lease of a crashed member has not expired yet, so a custom resource may be
+// unowned, or rarely owned by two members, for up to a renew period. Members
+// Lock custom resources before they act on them, so that only one of them
+// does.
+type Sharder struct {
+	client        client.Client
+	reader        client.Reader
+	identity      string
+	namespace     string
+	group         string
+	leaseDuration time.Duration
+	renewPeriod   time.Duration
+	clock         clock.WithTicker
+	log           logr.Logger
+
+	leaseName string
+
+	// The fields below are only accessed by Start.
+	observed  map[string]observedLease
+	joinedAt  time.Time
+	renewedAt time.Time
+
+	mu        sync.RWMutex
+	started   bool
+	members   []string
+	listeners []func(ownedBefore func(types.NamespacedName) bool)
+
+	locksMu sync.Mutex
+	locks   map[types.NamespacedName]*heldLock
+}
+
+var (
+	_ manager.Runnable               = &Sharder{}
+	_ manager.LeaderElectionRunnable = &Sharder{}
+)
+
+// New returns a Sharder for the member with the given identity, e.g. the name
+// of its pod, whose Lease is created in namespace with c. The Leases of the
+// members are listed with reader, which should not be a cache.
+func New(c client.Client, reader client.Reader, identity, namespace string, opts ...Option) (*Sharder, error) {
+	s := &Sharder{
+		client:        c,
+		reader:        reader,
+		identity:      identity,
+		namespace:     namespace,
+		group:         DefaultGroup,
+		leaseDuration: DefaultLeaseDuration,
+		renewPeriod:   DefaultRenewPeriod,
+		clock:         clock.RealClock{},
+		log:           logf.Log.WithName("sharding"),
+		observed:      map[string]observedLease{},
+		locks:         map[types.NamespacedName]*heldLock{},
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	if identity == "" {
+		return nil, errors.New("sharding: identity must not be empty")
+	}
+	if errs := validation.IsValidLabelValue(identity); len(errs) > 0 {
+		return nil, fmt.Errorf("sharding: invalid identity %q: %s", identity, strings.Join(errs, ", "))
+	}
+	if namespace == "" {
+		return nil, errors.New("sharding: namespace must not be empty")
+	}
+	if errs := validation.IsValidLabelValue(s.group); s.group == "" || len(errs) > 0 {
+		return nil, fmt.Errorf("sharding: invalid group %q: %s", s.group, strings.Join(errs, ", "))
+	}
+	if s.renewPeriod <= 0 || s.leaseDuration <= s.renewPeriod {
+		return nil, fmt.Errorf("sharding: lease duration %s must be greater than renew period %s", s.leaseDuration, s.renewPeriod)
+	}
+	s.leaseName = fmt.Sprintf("%s-%s", s.group, identity)
+	if errs := validation.IsDNS1123Subdomain(s.leaseName); len(errs) > 0 {
+		return nil, fmt.Errorf("sharding: invalid lease name %q: %s", s.leaseName, strings.Join(errs, ", "))
+	}
+	s.log = s.log.WithValues("group", s.group, "identity", identity)
+	return s, nil
+}
+
+// Identity returns the identity of the member.
+func (s *Sharder) Identity() string {
+	return s.identity
+}
+
+// Selector returns the selector of the objects in the shard of this member.
+func (s *Sharder) Selector() labels.Selector {
+	return labels.SelectorFromSet(s.Labels())
+}
+
+// Labels returns the labels that record that an object is in the shard of
+// this member.
+func (s *Sharder) Labels() labels.Set {
+	return labels.Set{ShardLabel: s.identity}
+}
+
+// RenewPeriod returns how often the Lease of the member is renewed and the
+// members are listed, which is the longest time until a change of the
+// members is observed.
+func (s *Sharder) RenewPeriod() time.Duration {
+	return s.renewPeriod
+}
+
+// Members returns the sorted identities of the live members of the group.
+func (s *Sharder) Members() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.members)
+}
+
+// Owns returns whether the custom resource with the given key is assigned to
+// this member.
+func (s *Sharder) Owns(key types.NamespacedName) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Assign(key, s.members) == s.identity
+}
+
+// OnRebalance registers f to be called after the members of the group have
+// changed. ownedBefore returns whether a custom resource was assigned to this
+// member before the change, so that f can find the custom resources that
+// this member owns now, but did not own before.
+func (s *Sharder) OnRebalance(f func(ownedBefore func(types.NamespacedName) bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, f)
+}
+
+// NewQueue returns a work queue that drops the requests for custom resources
+// that this member does not own. It can be used as the NewQueue option of a
+// controller, so that all its event handlers and requeues are filtered to
+// the shard of this member.
+func (s *Sharder) NewQueue(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return &shardQueue{
+		TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+			Name: controllerName,
+		}),
+		sharder: s,
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. All members
+// of the group run, since each of them reconciles its shard.
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// setMembers sets the members of the group and notifies the listeners if they
+// changed.
+func (s *Sharder) setMembers(members []string) {
+	slices.Sort(members)
+	s.mu.Lock()
+	if slices.Equal(members, s.members) {
+		s.mu.Unlock()
+		return
+	}
+	before := s.members
+	s.members = members
+	listeners := slices.Clone(s.listeners)
+	s.mu.Unlock()
+
+	s.log.Info("shard group members changed", "members", members)
+	ownedBefore := func(key types.NamespacedName) bool {
+		return Assign(key, before) == s.identity
+	}
+	for _, f := range listeners {
+		f(ownedBefore)
+	}
+	s.releaseUnowned()
+}
+
+// Assign returns the member that the custom resource with the given key is
+// assigned to by rendezvous hashing, or an empty string if there are no
+// members.
+func Assign(key types.NamespacedName, members []string) string {
+	var (
+		owner string
+		max   uint64
+	)
+	for _, m := range members {
+		if w := weight(m, key); owner == "" || w > max || (w == max && m < owner) {
+			owner, max = m, w
+		}
+	}
+	return owner
+}
+
+func weight(member string, key types.NamespacedName) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key.String()))
+	// FNV does not mix the last bytes well, so the sum is finalized with the
+	// mixer of splitmix64 to spread similar keys over all members.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// shardQueue is a work queue that drops the requests that are not in the
+// shard of its Sharder.
+type shardQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	sharder *Sharder
+}
+
+func (q *shardQueue) Add(req reconcile.Request) {
+	if q.sharder.Owns(req.NamespacedName) {
+		q.TypedRateLimitingInterface.Add(req)
+	}
+}
+
+func (q *shardQueue) AddAfter(req reconcile.Request, d time.Duration) {
+	if q.sharder.Owns(req.NamespacedName) {
+		q.TypedRateLimitingInterface.AddAfter(req, d)
+	}
+}
+
+func (q *shardQueue) AddRateLimited(req reconcile.Request) {
+	if q.sharder.Owns(req.NamespacedName) {
+		q.TypedRateLimitingInterface.AddRateLimited(req)
+	}
+}
+
+// Start joins the group and keeps the Lease of the member renewed until ctx
+// is done, when it leaves the group and deletes the Lease, so that the other
+// members take over its shard without waiting for the Lease to expire.
+// Starting a Sharder that has already been started waits until ctx is done,
+// so that a Sharder that is shared by several reconcilers can be added to the
+// manager by each of them.
+func (s *Sharder) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		<-ctx.Done()
+		return nil
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	s.log.Info("joining shard group", "namespace", s.namespace, "lease", s.leaseName)
+	ticker := s.clock.NewTicker(s.renewPeriod)
+	defer ticker.Stop()
+	for {
+		s.sync(ctx)
+		select {
+		case <-ctx.Done():
+			s.leave()
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func keys(n int) []types.NamespacedName {
+	out := make([]types.NamespacedName, n)
+	for i := range out {
+		out[i] = types.NamespacedName{Namespace: fmt.Sprintf("ns-%d", i%17), Name: fmt.Sprintf("cr-%d", i)}
+	}
+	return out
+}
+
+var _ = Describe("Assign", func() {
+	It("assigns nothing without members", func() {
+		Expect(Assign(types.NamespacedName{Namespace: "ns", Name: "cr"}, nil)).To(BeEmpty())
+	})
+
+	It("assigns keys independently of the order of members", func() {
+		for _, key := range keys(100) {
+			Expect(Assign(key, []string{"a", "b", "c"})).To(Equal(Assign(key, []string{"c", "a", "b"})))
+		}
+	})
+
+	It("spreads keys evenly over members", func() {
+		counts := map[string]int{}
+		for _, key := range keys(3000) {
+			counts[Assign(key, []string{"pod-a", "pod-b", "pod-c"})]++
+		}
+		Expect(counts).To(HaveLen(3))
+		for member, count := range counts {
+			Expect(count).To(BeNumerically("~", 1000, 150), member)
+		}
+	})
+
+	It("only moves keys to a member that joins", func() {
+		before := []string{"pod-a", "pod-b", "pod-c"}
+		after := append([]string{"pod-d"}, before...)
+		moved := 0
+		for _, key := range keys(3000) {
+			if owner := Assign(key, after); owner != Assign(key, before) {
+				Expect(owner).To(Equal("pod-d"))
+				moved++
+			}
+		}
+		Expect(moved).To(BeNumerically("~", 750, 150))
+	})
+
+	It("only moves the keys of a member that leaves", func() {
+		before := []string{"pod-a", "pod-b", "pod-c"}
+		after := []string{"pod-a", "pod-c"}
+		for _, key := range keys(3000) {
+			if owner := Assign(key, before); owner != "pod-b" {
+				Expect(Assign(key, after)).To(Equal(owner))
+			}
+		}
+	})
+})
+
+var _ = Describe("Sharder", func() {
+	var s *Sharder
+
+	BeforeEach(func() {
+		var err error
+		cl := fake.NewClientBuilder().Build()
+		s, err = New(cl, cl, "pod-a", "operators")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("rejects invalid configurations", func() {
+		cl := fake.NewClientBuilder().Build()
+		_, err := New(cl, cl, "", "operators")
+		Expect(err).To(MatchError(ContainSubstring("identity must not be empty")))
+		_, err = New(cl, cl, "pod-a", "")
+		Expect(err).To(MatchError(ContainSubstring("namespace must not be empty")))
+		_, err = New(cl, cl, "pod-a", "operators", WithGroup("not a label value"))
+		Expect(err).To(MatchError(ContainSubstring("invalid group")))
+		_, err = New(cl, cl, "pod-a", "operators", WithLeaseDuration(time.Second), WithRenewPeriod(time.Second))
+		Expect(err).To(MatchError(ContainSubstring("must be greater than renew period")))
+		_, err = New(cl, cl, "operators/pod-a", "operators")
+		Expect(err).To(MatchError(ContainSubstring("invalid identity")))
+		_, err = New(cl, cl, "Pod_A", "operators")
+		Expect(err).To(MatchError(ContainSubstring("invalid lease name")))
+	})
+
+	It("owns nothing before it joins the group", func() {
+		for _, key := range keys(10) {
+			Expect(s.Owns(key)).To(BeFalse())
+		}
+	})
+
+	It("selects the objects of its shard", func() {
+		Expect(s.Labels()).To(Equal(labels.Set{ShardLabel: "pod-a"}))
+		Expect(s.Selector().Matches(labels.Set{ShardLabel: "pod-a"})).To(BeTrue())
+		Expect(s.Selector().Matches(labels.Set{ShardLabel: "pod-b"})).To(BeFalse())
+		Expect(s.Selector().Matches(labels.Set{})).To(BeFalse())
+	})
+
+	It("owns the keys that are assigned to it", func() {
+		s.setMembers([]string{"pod-b", "pod-a"})
+		Expect(s.Members()).To(Equal([]string{"pod-a", "pod-b"}))
+		for _, key := range keys(100) {
+			Expect(s.Owns(key)).To(Equal(Assign(key, []string{"pod-a", "pod-b"}) == "pod-a"))
+		}
+	})
+
+	It("notifies listeners of rebalances with the previous assignment", func() {
+		var calls int
+		var newlyOwned []types.NamespacedName
+		s.OnRebalance(func(ownedBefore func(types.NamespacedName) bool) {
+			calls++
+			newlyOwned = nil
+			for _, key := range keys(300) {
+				if s.Owns(key) && !ownedBefore(key) {
+					newlyOwned = append(newlyOwned, key)
+				}
+			}
+		})
+
+		s.setMembers([]string{"pod-a", "pod-b"})
+		Expect(calls).To(Equal(1))
+		Expect(newlyOwned).ToNot(BeEmpty())
+		for _, key := range newlyOwned {
+			Expect(Assign(key, []string{"pod-a", "pod-b"})).To(Equal("pod-a"))
+		}
+
+		By("not notifying listeners if the members did not change")
+		s.setMembers([]string{"pod-b", "pod-a"})
+		Expect(calls).To(Equal(1))
+
+		By("notifying listeners of the keys of a member that left")
+		s.setMembers([]string{"pod-a"})
+		Expect(calls).To(Equal(2))
+		for _, key := range newlyOwned {
+			Expect(Assign(key, []string{"pod-a", "pod-b"})).To(Equal("pod-b"))
+		}
+	})
+
+	It("creates queues that drop requests of other shards", func() {
+		s.setMembers([]string{"pod-a", "pod-b"})
+		q := s.NewQueue("test", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer q.ShutDown()
+
+		owned := 0
+		for _, key := range keys(100) {
+			q.Add(reconcile.Request{NamespacedName: key})
+			if s.Owns(key) {
+				owned++
+			}
+		}
+		Expect(q.Len()).To(Equal(owned))
+
+		for _, key := range keys(100) {
+			q.AddRateLimited(reconcile.Request{NamespacedName: key})
+			q.AddAfter(reconcile.Request{NamespacedName: key}, time.Millisecond)
+		}
+		Consistently(q.Len).Should(Equal(owned))
+	})
+
+	It("does not need leader election", func() {
+		Expect(s.NeedLeaderElection()).To(BeFalse())
+	})
+})
@@ -0,0 +1,346 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statusmapping maps values of the objects of a release into the
+// status of its custom resource.
+package statusmapping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/interpreter"
+	"google.golang.org/protobuf/types/known/structpb"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/hook"
+)
+
+// Mapping maps a value of an object of the release to a field in the status
+// of the custom resource. The value is read with either a JSONPath or a CEL
+// expression.
+type Mapping struct {
+	// APIVersion is the API version of the object, e.g. "apps/v1".
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the object, e.g. "Deployment".
+	Kind string `json:"kind"`
+	// Name is the name of the object, which may contain wildcards, e.g.
+	// "*-web". The first object of the kind is used if it is empty.
+	Name string `json:"name,omitempty"`
+	// JSONPath is a JSONPath expression that selects the value, e.g.
+	// "{.status.loadBalancer.ingress[0].ip}". The braces are optional.
+	JSONPath string `json:"jsonPath,omitempty"`
+	// CEL is a CEL expression that computes the value from the object,
+	// which is bound to "object", e.g. "object.status.readyReplicas".
+	CEL string `json:"cel,omitempty"`
+	// Field is the dot-separated path of the field under status.custom
+	// that the value is written to, e.g. "endpoints.web".
+	Field string `json:"field"`
+}
+
+// Mapper maps the values of compiled Mappings into the status of custom
+// resources.
+type Mapper struct {
+	mappings []compiledMapping
+}
+
+type compiledMapping struct {
+	Mapping
+	gvk   schema.GroupVersionKind
+	field []string
+	eval  func(obj map[string]interface{}) (interface{}, bool, error)
+}
+
+// New compiles the mappings. It returns the errors of all invalid mappings.
+func New(mappings ...Mapping) (*Mapper, error) {
+	m := &Mapper{}
+	var errs []error
+	for i, mapping := range mappings {
+		c, err := compile(mapping)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid status mapping [%d]: %w", i, err))
+			continue
+		}
+		m.mappings = append(m.mappings, c)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Validate returns an error if the mapping is invalid.
+func (m Mapping) Validate() error {
+	_, err := compile(m)
+	return err
+}
+
+func compile(m Mapping) (compiledMapping, error) {
+	c := compiledMapping{Mapping: m}
+	gv, err := schema.ParseGroupVersion(m.APIVersion)
+	if err != nil {
+		return c, err
+	}
+	if m.APIVersion == "" || m.Kind == "" {
+		return c, errors.New("apiVersion and kind must not be empty")
+	}
+	c.gvk = gv.WithKind(m.Kind)
+	if _, err := path.Match(m.Name, ""); err != nil {
+		return c, fmt.Errorf("invalid name pattern %q", m.Name)
+	}
+	if m.Field == "" || strings.HasPrefix(m.Field, ".") || strings.HasSuffix(m.Field, ".") || strings.Contains(m.Field, "..") {
+		return c, fmt.Errorf("invalid field %q", m.Field)
+	}
+	c.field = strings.Split(m.Field, ".")
+
+	switch {
+	case m.JSONPath != "" && m.CEL != "":
+		return c, errors.New("only one of jsonPath and cel may be set")
+	case m.JSONPath != "":
+		c.eval, err = compileJSONPath(m.JSONPath)
+	case m.CEL != "":
+		c.eval, err = compileCEL(m.CEL)
+	default:
+		return c, errors.New("one of jsonPath and cel must be set")
+	}
+	return c, err
+}
+
+func compileJSONPath(expr string) (func(map[string]interface{}) (interface{}, bool, error), error) {
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	j := jsonpath.New("statusMapping").AllowMissingKeys(true)
+	if err := j.Parse(expr); err != nil {
+		return nil, fmt.Errorf("invalid jsonPath %q: %w", expr, err)
+	}
+	return func(obj map[string]interface{}) (interface{}, bool, error) {
+		results, err := j.FindResults(obj)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(results) == 0 || len(results[0]) == 0 {
+			return nil, false, nil
+		}
+		return results[0][0].Interface(), true, nil
+	}, nil
+}
+
+var celEnv, celEnvErr = cel.NewEnv(cel.Variable("object", cel.DynType))
+
+func compileCEL(expr string) (func(map[string]interface{}) (interface{}, bool, error), error) {
+	if celEnvErr != nil {
+		return nil, celEnvErr
+	}
+	ast, iss := celEnv.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("invalid cel %q: %w", expr, iss.Err())
+	}
+	// The state of the evaluation is tracked, so that errors caused by
+	// missing fields can be told apart from other errors.
+	prg, err := celEnv.Program(ast, cel.EvalOptions(cel.OptTrackState))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cel %q: %w", expr, err)
+	}
+	return func(obj map[string]interface{}) (interface{}, bool, error) {
+		out, details, err := prg.Eval(map[string]interface{}{"object": obj})
+		if err != nil {
+			// Missing fields are errors in CEL, and mean that
+			// there is no value yet, e.g. before a Service has
+			// been assigned an IP.
+			if details != nil && accessesMissingField(ast.NativeRep().Expr(), details.State(), obj) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		v, err := out.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+		if err != nil {
+			return nil, false, err
+		}
+		return v.(*structpb.Value).AsInterface(), true, nil
+	}, nil
+}
+
+// accessesMissingField returns whether the evaluation of expr for obj failed
+// to select a field that is missing from a map, or an index that is out of
+// the range of a list.
+func accessesMissingField(expr celast.Expr, state interpreter.EvalState, obj map[string]interface{}) bool {
+	failed := func(e celast.Expr) bool {
+		v, ok := state.Value(e.ID())
+		return ok && types.IsError(v)
+	}
+	// The values of identifiers and literals are not tracked.
+	value := func(e celast.Expr) ref.Val {
+		switch e.Kind() {
+		case celast.IdentKind:
+			if e.AsIdent() == "object" {
+				return types.DefaultTypeAdapter.NativeToValue(obj)
+			}
+		case celast.LiteralKind:
+			return e.AsLiteral()
+		}
+		v, _ := state.Value(e.ID())
+		return v
+	}
+	missing := false
+	celast.PostOrderVisit(expr, celast.NewExprVisitor(func(e celast.Expr) {
+		if missing || !failed(e) {
+			return
+		}
+		switch e.Kind() {
+		case celast.SelectKind:
+			sel := e.AsSelect()
+			if m, ok := value(sel.Operand()).(traits.Mapper); ok && !sel.IsTestOnly() {
+				_, found := m.Find(types.String(sel.FieldName()))
+				missing = !found
+			}
+		case celast.CallKind:
+			call := e.AsCall()
+			if call.FunctionName() != operators.Index || len(call.Args()) != 2 {
+				return
+			}
+			switch operand := value(call.Args()[0]).(type) {
+			case traits.Mapper:
+				if key := value(call.Args()[1]); key != nil && !types.IsError(key) {
+					_, found := operand.Find(key)
+					missing = !found
+				}
+			case traits.Lister:
+				index, ok := value(call.Args()[1]).(types.Int)
+				size, _ := operand.Size().(types.Int)
+				missing = ok && (index < 0 || index >= size)
+			}
+		}
+	}))
+	return missing
+}
+
+// Map reads the values of the mappings from the objects in the manifest of a
+// release, which it gets with reader, and writes them to w. Namespaced objects
+// without a namespace are looked up in namespace, and whether objects are
+// namespaced is resolved with mapper. The fields of values that are not found
+// are removed, so that they do not become stale.
+func (m *Mapper) Map(ctx context.Context, reader client.Reader, mapper meta.RESTMapper, namespace, manifest string, w hook.StatusWriter) error {
+	objs, err := manifestObjects(manifest)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, c := range m.mappings {
+		value, ok, err := c.read(ctx, reader, mapper, namespace, objs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("status mapping to %q: %w", c.Field, err))
+			continue
+		}
+		if !ok {
+			w.RemoveField(c.field...)
+			continue
+		}
+		if err := w.SetField(value, c.field...); err != nil {
+			errs = append(errs, fmt.Errorf("status mapping to %q: %w", c.Field, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Kinds returns the group kinds of the objects the mappings read from.
+func (m *Mapper) Kinds() []schema.GroupKind {
+	var gks []schema.GroupKind
+	for _, c := range m.mappings {
+		gks = append(gks, c.gvk.GroupKind())
+	}
+	return gks
+}
+
+func (c compiledMapping) read(ctx context.Context, reader client.Reader, mapper meta.RESTMapper, namespace string, objs []*unstructured.Unstructured) (interface{}, bool, error) {
+	for _, o := range objs {
+		if o.GroupVersionKind() != c.gvk {
+			continue
+		}
+		if c.Name != "" {
+			if ok, _ := path.Match(c.Name, o.GetName()); !ok {
+				continue
+			}
+		}
+
+		namespaced, err := apiutil.IsGVKNamespaced(c.gvk, mapper)
+		if err != nil {
+			return nil, false, err
+		}
+		key := client.ObjectKey{Name: o.GetName()}
+		if namespaced {
+			key.Namespace = o.GetNamespace()
+			if key.Namespace == "" {
+				key.Namespace = namespace
+			}
+		}
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(c.gvk)
+		if err := reader.Get(ctx, key, live); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		return c.eval(live.Object)
+	}
+	return nil, false, nil
+}
+
+func manifestObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	manifests := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(manifests))
+	for k := range manifests {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var objs []*unstructured.Unstructured
+	for _, k := range keys {
+		if strings.TrimSpace(manifests[k]) == "" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifests[k]), obj); err != nil {
+			return nil, err
+		}
+		if obj.GroupVersionKind().Empty() {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Operator-SDK Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusmapping_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/statusmapping"
+)
+
+const manifest = `---
+# Source: chart/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: release-web
+---
+# Source: chart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: release-web
+  namespace: other
+---
+# Source: chart/templates/namespace.yaml
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: release-tenant
+`
+
+type fakeStatusWriter struct {
+	fields  map[string]interface{}
+	removed []string
+}
+
+func (w *fakeStatusWriter) SetCondition(string, corev1.ConditionStatus, string, string) error {
+	return nil
+}
+
+func (w *fakeStatusWriter) SetField(value interface{}, fields ...string) error {
+	return unstructured.SetNestedField(w.fields, value, fields...)
+}
+
+func (w *fakeStatusWriter) RemoveField(fields ...string) {
+	w.removed = append(w.removed, fields[len(fields)-1])
+}
+
+func newObject(apiVersion, kind, namespace, name string, status map[string]interface{}) client.Object {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+var _ = Describe("New", func() {
+	DescribeTable("rejects invalid mappings",
+		func(m statusmapping.Mapping, message string) {
+			_, err := statusmapping.New(m)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+			Expect(m.Validate()).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("without a kind", statusmapping.Mapping{APIVersion: "v1", JSONPath: ".status", Field: "f"}, "apiVersion and kind must not be empty"),
+		Entry("with an invalid name", statusmapping.Mapping{APIVersion: "v1", Kind: "Service", Name: "[", JSONPath: ".status", Field: "f"}, "invalid name pattern"),
+		Entry("with an invalid field", statusmapping.Mapping{APIVersion: "v1", Kind: "Service", JSONPath: ".status", Field: "f."}, "invalid field"),
+		Entry("without an expression", statusmapping.Mapping{APIVersion: "v1", Kind: "Service", Field: "f"}, "one of jsonPath and cel must be set"),
+		Entry("with both expressions", statusmapping.Mapping{APIVersion: "v1", Kind: "Service", JSONPath: ".status", CEL: "object.status", Field: "f"}, "only one of jsonPath and cel may be set"),
+		Entry("with an invalid jsonPath", statusmapping.Mapping{APIVersion: "v1", Kind: "Service", JSONPath: "{.status[", Field: "f"}, "invalid jsonPath"),
+		Entry("with an invalid cel", statusmapping.Mapping{APIVersion: "v1", Kind: "Service", CEL: "object.", Field: "f"}, "invalid cel"),
+	)
+})
+
+var _ = Describe("Mapper", func() {
+	var (
+		ctx    context.Context
+		cl     client.Client
+		mapper *meta.DefaultRESTMapper
+		w      *fakeStatusWriter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		w = &fakeStatusWriter{fields: map[string]interface{}{}}
+		mapper = meta.NewDefaultRESTMapper(nil)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+		mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+		cl = fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(
+			newObject("v1", "Service", "ns", "release-web", map[string]interface{}{
+				"loadBalancer": map[string]interface{}{"ingress": []interface{}{map[string]interface{}{"ip": "10.0.0.1"}}},
+			}),
+			newObject("apps/v1", "Deployment", "other", "release-web", map[string]interface{}{"readyReplicas": int64(2)}),
+			newObject("v1", "Namespace", "", "release-tenant", map[string]interface{}{"phase": "Active"}),
+		).Build()
+	})
+
+	It("maps values with JSONPath and CEL", func() {
+		m, err := statusmapping.New(
+			statusmapping.Mapping{APIVersion: "v1", Kind: "Service", Name: "*-web", JSONPath: "{.status.loadBalancer.ingress[0].ip}", Field: "endpoints.web"},
+			statusmapping.Mapping{APIVersion: "apps/v1", Kind: "Deployment", CEL: "object.status.readyReplicas * 10", Field: "readyPercent"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Map(ctx, cl, mapper, "ns", manifest, w)).To(Succeed())
+		Expect(w.fields).To(Equal(map[string]interface{}{
+			"endpoints":    map[string]interface{}{"web": "10.0.0.1"},
+			"readyPercent": float64(20),
+		}))
+	})
+
+	It("maps values of cluster-scoped objects", func() {
+		m, err := statusmapping.New(statusmapping.Mapping{APIVersion: "v1", Kind: "Namespace", JSONPath: ".status.phase", Field: "phase"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Map(ctx, cl, mapper, "ns", manifest, w)).To(Succeed())
+		Expect(w.fields).To(Equal(map[string]interface{}{"phase": "Active"}))
+	})
+
+	It("removes the fields of values that are not found", func() {
+		m, err := statusmapping.New(
+			statusmapping.Mapping{APIVersion: "v1", Kind: "Service", JSONPath: ".status.loadBalancer.ingress[0].hostname", Field: "hostname"},
+			statusmapping.Mapping{APIVersion: "apps/v1", Kind: "Deployment", CEL: "object.status.availableReplicas", Field: "available"},
+			statusmapping.Mapping{APIVersion: "v1", Kind: "ConfigMap", JSONPath: ".data", Field: "data"},
+			statusmapping.Mapping{APIVersion: "v1", Kind: "Service", Name: "other", JSONPath: ".status", Field: "other"},
+			statusmapping.Mapping{APIVersion: "v1", Kind: "Service", CEL: "object.status.loadBalancer.ingress[1].ip", Field: "secondIP"},
+			statusmapping.Mapping{APIVersion: "v1", Kind: "Service", CEL: `object.status["conditions"]`, Field: "conditions"},
+			statusmapping.Mapping{APIVersion: "v1", Kind: "Service", CEL: "object.spec.clusterIP", Field: "clusterIP"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Map(ctx, cl, mapper, "ns", manifest, w)).To(Succeed())
+		Expect(w.fields).To(BeEmpty())
+		Expect(w.removed).To(Equal([]string{"hostname", "available", "data", "other", "secondIP", "conditions", "clusterIP"}))
+	})
+
+	It("returns the errors of CEL expressions that do not read missing fields", func() {
+		m, err := statusmapping.New(statusmapping.Mapping{APIVersion: "apps/v1", Kind: "Deployment", CEL: `object.status.readyReplicas + "x"`, Field: "invalid"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Map(ctx, cl, mapper, "ns", manifest, w)).To(MatchError(ContainSubstring("no such overload")))
+		Expect(w.removed).To(BeEmpty())
+	})
+
+	It("returns the group kinds it reads from", func() {
+		m, err := statusmapping.New(statusmapping.Mapping{APIVersion: "apps/v1", Kind: "Deployment", CEL: "object.status", Field: "f"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Kinds()).To(HaveLen(1))
+		Expect(m.Kinds()[0].String()).To(Equal("Deployment.apps"))
+	})
+})
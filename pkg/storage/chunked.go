@@ -19,47 +19,101 @@ import (
 	"helm.sh/helm/v3/pkg/storage/driver"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
-	"k8s.io/utils/ptr"
 )
 
-var _ driver.Driver = (*chunkedSecrets)(nil)
+var _ driver.Driver = (*chunked)(nil)
 
-type ChunkedSecretsConfig struct {
+// ChunkedConfig configures a chunked storage driver, regardless of the
+// backend it stores its objects in.
+type ChunkedConfig struct {
+	// ChunkSize is the maximum size of a chunk of the encoded release. It
+	// defaults to DefaultChunkSize and is ignored if AdaptiveChunkSize is
+	// enabled.
 	ChunkSize      int
 	MaxReadChunks  int
 	MaxWriteChunks int
 	Log            func(string, ...interface{})
+
+	// Codec compresses releases before they are chunked. It defaults to
+	// gzip at its best compression level.
+	Codec Codec
+
+	// AdaptiveChunkSize makes the driver ignore ChunkSize and instead use as
+	// few chunks as MaxObjectSize allows, spreading the encoded release
+	// evenly across them. The chunk size only adapts to MaxObjectSize, not
+	// to the limits of the cluster the release is stored in.
+	AdaptiveChunkSize bool
+
+	// MaxObjectSize is the maximum size of the data of a single stored
+	// object. It is only used when AdaptiveChunkSize is enabled and
+	// defaults to corev1.MaxSecretSize (1 MiB). It is not discovered from
+	// the API server and must not exceed the size of the objects that the
+	// cluster accepts, i.e. the --max-request-bytes of the API server and
+	// etcd, or storing large releases fails. 16 KiB of it are reserved for
+	// the other data of the index.
+	MaxObjectSize int
+}
+
+// ChunkedSecretsConfig configures a chunked storage driver.
+//
+// Deprecated: use ChunkedConfig instead.
+type ChunkedSecretsConfig = ChunkedConfig
+
+// DefaultChunkSize is the default size of chunks when the chunk size is not
+// chosen adaptively. It leaves enough room below corev1.MaxSecretSize for the
+// other data of the index.
+const DefaultChunkSize = 512 * 1024
+
+// adaptiveChunkSizeReserve is the number of bytes of MaxObjectSize that
+// adaptive chunking leaves unused, so that the index has room for the list
+// of extra chunk names and the codec next to its chunk.
+const adaptiveChunkSizeReserve = 16 * 1024
+
+// NewChunkedSecrets returns a chunked storage driver that persists releases
+// as Secrets.
+func NewChunkedSecrets(client clientcorev1.SecretInterface, owner string, config ChunkedConfig) driver.Driver {
+	return NewChunked(NewSecretsStore(client), owner, config)
 }
 
-func NewChunkedSecrets(client clientcorev1.SecretInterface, owner string, config ChunkedSecretsConfig) driver.Driver {
+// NewChunked returns a storage driver that splits releases into chunks and
+// persists them as an index object and zero or more chunk objects in store.
+func NewChunked(store ObjectStore, owner string, config ChunkedConfig) driver.Driver {
 	if config.Log == nil {
 		config.Log = func(string, ...interface{}) {}
 	}
+	if config.Codec == nil {
+		config.Codec = GzipCodec(gzip.BestCompression)
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = DefaultChunkSize
+	}
+	if config.MaxObjectSize <= 0 {
+		config.MaxObjectSize = corev1.MaxSecretSize
+	}
 
-	return &chunkedSecrets{
-		client:               client,
-		owner:                owner,
-		ChunkedSecretsConfig: config,
+	return &chunked{
+		store:         store,
+		owner:         owner,
+		ChunkedConfig: config,
 
 		hashEncoding: base32.NewEncoding("abcdefghijklmnopqrstuvwxyz123456").WithPadding(base32.NoPadding),
 		hash:         fnv.New64a(),
 	}
 }
 
-type chunkedSecrets struct {
-	client clientcorev1.SecretInterface
-	owner  string
-	ChunkedSecretsConfig
+type chunked struct {
+	store ObjectStore
+	owner string
+	ChunkedConfig
 
 	hashMu       sync.Mutex
 	hash         hash.Hash64
 	hashEncoding *base32.Encoding
 }
 
-func (c *chunkedSecrets) Create(key string, rls *release.Release) error {
+func (c *chunked) Create(key string, rls *release.Release) error {
 	c.Log("create: %q", key)
 	defer c.Log("created: %q", key)
 
@@ -69,9 +123,9 @@ func (c *chunkedSecrets) Create(key string, rls *release.Release) error {
 	}
 
 	createdAt := time.Now()
-	indexSecret := c.indexSecretFromChunks(key, rls, chunks)
-	indexSecret.Labels["createdAt"] = strconv.Itoa(int(createdAt.Unix()))
-	indexSecret, err = c.client.Create(context.Background(), indexSecret, metav1.CreateOptions{})
+	index := c.indexFromChunks(key, rls, chunks)
+	index.Labels["createdAt"] = strconv.Itoa(int(createdAt.Unix()))
+	index, err = c.store.Create(context.Background(), index)
 	if err != nil {
 		if apierrors.IsAlreadyExists(err) {
 			return driver.ErrReleaseExists
@@ -80,9 +134,9 @@ func (c *chunkedSecrets) Create(key string, rls *release.Release) error {
 	}
 
 	for i, ch := range chunks[1:] {
-		chunkSecret := c.chunkSecretFromChunk(indexSecret, ch)
-		chunkSecret.Labels["createdAt"] = strconv.Itoa(int(createdAt.Unix()))
-		if _, err := c.client.Create(context.Background(), chunkSecret, metav1.CreateOptions{}); err != nil {
+		chunkObj := c.chunkFromChunk(index, ch)
+		chunkObj.Labels["createdAt"] = strconv.Itoa(int(createdAt.Unix()))
+		if _, err := c.store.Create(context.Background(), chunkObj); err != nil {
 			return fmt.Errorf("create: failed to create chunk secret %d of %d %q: %w", i+2, len(chunks), ch.name, err)
 		}
 	}
@@ -106,28 +160,36 @@ func wrapRelease(rls *release.Release) *releaseWrapper {
 	}
 }
 
-// encodeRelease encodes a release returning a base64 encoded
-// gzipped string representation, or error.
-func (c *chunkedSecrets) encodeReleaseAsChunks(key string, rls *release.Release) ([]chunk, error) {
+// encodeRelease encodes a release returning its compressed
+// representation split into chunks, or error.
+func (c *chunked) encodeReleaseAsChunks(key string, rls *release.Release) ([]chunk, error) {
 	buf := &bytes.Buffer{}
 
 	if err := func() error {
-		gzw, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+		cw, err := c.Codec.NewWriter(buf)
 		if err != nil {
 			return err
 		}
-		defer gzw.Close()
-		return json.NewEncoder(gzw).Encode(wrapRelease(rls))
+		if err := json.NewEncoder(cw).Encode(wrapRelease(rls)); err != nil {
+			_ = cw.Close()
+			return err
+		}
+		return cw.Close()
 	}(); err != nil {
 		return nil, err
 	}
 	data := buf.Bytes()
 
+	chunkSize := c.ChunkSize
+	if c.AdaptiveChunkSize {
+		chunkSize = adaptiveChunkSize(len(data), c.MaxObjectSize-adaptiveChunkSizeReserve)
+	}
+
 	// Split the encoded release into chunks of chunkSize
 	// and return the chunks.
 	var chunks []chunk
-	for i := 0; i < len(data); i += c.ChunkSize {
-		end := i + c.ChunkSize
+	for i := 0; i < len(data); i += chunkSize {
+		end := i + chunkSize
 		if end > len(data) {
 			end = len(data)
 		}
@@ -144,12 +206,21 @@ func (c *chunkedSecrets) encodeReleaseAsChunks(key string, rls *release.Release)
 	return chunks, nil
 }
 
-const (
-	SecretTypeChunkedIndex = corev1.SecretType("operatorframework.io/index.v1")
-	SecretTypeChunkedChunk = corev1.SecretType("operatorframework.io/chunk.v1")
-)
+// adaptiveChunkSize returns the chunk size that splits dataSize bytes into
+// the fewest chunks of at most maxChunkSize bytes, with all chunks but the
+// last one being of equal size.
+func adaptiveChunkSize(dataSize, maxChunkSize int) int {
+	if maxChunkSize <= 0 {
+		maxChunkSize = 1
+	}
+	if dataSize <= maxChunkSize {
+		return max(dataSize, 1)
+	}
+	numChunks := (dataSize + maxChunkSize - 1) / maxChunkSize
+	return (dataSize + numChunks - 1) / numChunks
+}
 
-func (c *chunkedSecrets) indexSecretFromChunks(key string, rls *release.Release, chunks []chunk) *corev1.Secret {
+func (c *chunked) indexFromChunks(key string, rls *release.Release, chunks []chunk) *StoredObject {
 	extraChunkNames := make([]string, 0, len(chunks)-1)
 	for _, ch := range chunks[1:] {
 		extraChunkNames = append(extraChunkNames, ch.name)
@@ -159,60 +230,47 @@ func (c *chunkedSecrets) indexSecretFromChunks(key string, rls *release.Release,
 		panic(err)
 	}
 
-	indexLabels := newIndexLabels(c.owner, key, rls)
-	indexSecret := &corev1.Secret{
-		Type: SecretTypeChunkedIndex,
-		ObjectMeta: metav1.ObjectMeta{
-			Name:   key,
-			Labels: indexLabels,
-		},
-		Immutable: ptr.To(false),
+	return &StoredObject{
+		Name:      key,
+		Type:      ObjectTypeIndex,
+		Labels:    newIndexLabels(c.owner, key, rls),
+		Immutable: false,
 		Data: map[string][]byte{
 			"extraChunks": extraChunkNamesData,
 			"chunk":       chunks[0].data,
+			"codec":       []byte(c.Codec.Name()),
 		},
 	}
-	return indexSecret
 }
 
-func (c *chunkedSecrets) chunkSecretFromChunk(indexSecret *corev1.Secret, ch chunk) *corev1.Secret {
-	chunkLabels := newChunkLabels(c.owner, indexSecret.Name)
-	chunkSecret := &corev1.Secret{
-		Type: SecretTypeChunkedChunk,
-		ObjectMeta: metav1.ObjectMeta{
-			Name:   ch.name,
-			Labels: chunkLabels,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion:         corev1.SchemeGroupVersion.String(),
-					Kind:               "Secret",
-					Name:               indexSecret.Name,
-					UID:                indexSecret.UID,
-					Controller:         ptr.To(true),
-					BlockOwnerDeletion: ptr.To(false),
-				},
-			},
-		},
-		Immutable: ptr.To(true),
+func (c *chunked) chunkFromChunk(index *StoredObject, ch chunk) *StoredObject {
+	return &StoredObject{
+		Name:      ch.name,
+		Type:      ObjectTypeChunk,
+		Labels:    newChunkLabels(c.owner, index.Name),
+		Immutable: true,
 		Data: map[string][]byte{
 			"chunk": ch.data,
 		},
+		Owner: &ObjectReference{
+			Name: index.Name,
+			UID:  index.UID,
+		},
 	}
-	return chunkSecret
 }
 
-func (c *chunkedSecrets) getIndex(ctx context.Context, key string) (*corev1.Secret, error) {
-	indexSecret, err := c.client.Get(ctx, key, metav1.GetOptions{})
+func (c *chunked) getIndex(ctx context.Context, key string) (*StoredObject, error) {
+	index, err := c.store.Get(ctx, key)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil, driver.ErrReleaseNotFound
 		}
-		return nil, fmt.Errorf("failed to get secret for key %q: %w", key, err)
+		return nil, fmt.Errorf("failed to get index for key %q: %w", key, err)
 	}
-	return indexSecret, nil
+	return index, nil
 }
 
-func (c *chunkedSecrets) Update(key string, rls *release.Release) error {
+func (c *chunked) Update(key string, rls *release.Release) error {
 	c.Log("update: %q", key)
 	defer c.Log("updated: %q", key)
 
@@ -222,9 +280,9 @@ func (c *chunkedSecrets) Update(key string, rls *release.Release) error {
 		return fmt.Errorf("update: %w", err)
 	}
 
-	// Delete the existing chunk secrets
-	if err := c.client.DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: newListChunksForKeySelector(c.owner, existingIndex.Name).String()}); err != nil {
-		return fmt.Errorf("update: failed to delete previous chunk secrets for key %q: %w", key, err)
+	// Delete the existing chunks
+	if err := c.store.DeleteCollection(context.Background(), newListChunksForKeySelector(c.owner, existingIndex.Name)); err != nil {
+		return fmt.Errorf("update: failed to delete previous chunks for key %q: %w", key, err)
 	}
 
 	// Generate new chunks
@@ -235,56 +293,57 @@ func (c *chunkedSecrets) Update(key string, rls *release.Release) error {
 
 	modifiedAt := time.Now()
 
-	// Update the index secret
-	updatedIndexSecret := c.indexSecretFromChunks(key, rls, chunks)
-	updatedIndexSecret.Labels["createdAt"] = existingIndex.Labels["createdAt"]
-	updatedIndexSecret.Labels["modifiedAt"] = strconv.Itoa(int(modifiedAt.Unix()))
-	updatedIndexSecret, err = c.client.Update(context.Background(), updatedIndexSecret, metav1.UpdateOptions{})
+	// Update the index
+	updatedIndex := c.indexFromChunks(key, rls, chunks)
+	updatedIndex.Labels["createdAt"] = existingIndex.Labels["createdAt"]
+	updatedIndex.Labels["modifiedAt"] = strconv.Itoa(int(modifiedAt.Unix()))
+	updatedIndex, err = c.store.Update(context.Background(), updatedIndex)
 	if err != nil {
 		return fmt.Errorf("create: failed to create index and chunk %d of %d secret %q: %w", 1, len(chunks), key, err)
 	}
 
 	// Create the new chunks
 	for i, ch := range chunks[1:] {
-		chunkSecret := c.chunkSecretFromChunk(updatedIndexSecret, ch)
-		if _, err := c.client.Create(context.Background(), chunkSecret, metav1.CreateOptions{}); err != nil {
+		chunkObj := c.chunkFromChunk(updatedIndex, ch)
+		chunkObj.Labels["createdAt"] = strconv.Itoa(int(modifiedAt.Unix()))
+		if _, err := c.store.Create(context.Background(), chunkObj); err != nil {
 			return fmt.Errorf("create: failed to create chunk secret %d of %d %q: %w", i+2, len(chunks), ch.name, err)
 		}
 	}
 	return nil
 }
 
-func (c *chunkedSecrets) Delete(key string) (*release.Release, error) {
+func (c *chunked) Delete(key string) (*release.Release, error) {
 	c.Log("delete: %q", key)
 	defer c.Log("deleted: %q", key)
 
-	indexSecret, rls, err := c.getIndexAndRelease(key)
+	index, rls, err := c.getIndexAndRelease(key)
 	if err != nil {
 		if errors.Is(err, driver.ErrReleaseNotFound) {
 			return nil, driver.ErrReleaseNotFound
 		}
 		return nil, fmt.Errorf("delete: %w", err)
 	}
-	if err := c.client.DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: newListAllForKeySelector(c.owner, key).String()}); err != nil {
-		return nil, fmt.Errorf("delete: failed to delete index secret %q: %w", indexSecret.Name, err)
+	if err := c.store.DeleteCollection(context.Background(), newListAllForKeySelector(c.owner, key)); err != nil {
+		return nil, fmt.Errorf("delete: failed to delete index %q: %w", index.Name, err)
 	}
 	return rls, nil
 }
 
-func (c *chunkedSecrets) getIndexAndRelease(key string) (*corev1.Secret, *release.Release, error) {
-	indexSecret, err := c.getIndex(context.Background(), key)
+func (c *chunked) getIndexAndRelease(key string) (*StoredObject, *release.Release, error) {
+	index, err := c.getIndex(context.Background(), key)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	rls, err := c.decodeRelease(context.Background(), indexSecret)
+	rls, err := c.decodeRelease(context.Background(), index)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to decode release from index secret %q: %w", indexSecret.Name, err)
+		return nil, nil, fmt.Errorf("failed to decode release from index %q: %w", index.Name, err)
 	}
-	return indexSecret, rls, nil
+	return index, rls, nil
 }
 
-func (c *chunkedSecrets) Get(key string) (*release.Release, error) {
+func (c *chunked) Get(key string) (*release.Release, error) {
 	c.Log("get: %q", key)
 	defer c.Log("got: %q", key)
 
@@ -295,23 +354,23 @@ func (c *chunkedSecrets) Get(key string) (*release.Release, error) {
 	return rls, nil
 }
 
-func (c *chunkedSecrets) List(filter func(*release.Release) bool) ([]*release.Release, error) {
+func (c *chunked) List(filter func(*release.Release) bool) ([]*release.Release, error) {
 	c.Log("list")
 	defer c.Log("listed")
 
-	indexSecrets, err := c.client.List(context.Background(), metav1.ListOptions{LabelSelector: newListIndicesLabelSelector(c.owner).String()})
+	indices, err := c.store.List(context.Background(), newListIndicesLabelSelector(c.owner))
 	if err != nil {
 		return nil, fmt.Errorf("list: %w", err)
 	}
 
 	var results []*release.Release
-	for _, indexSecret := range indexSecrets.Items {
-		indexSecret := indexSecret
-		rls, err := c.decodeRelease(context.Background(), &indexSecret)
+	for _, index := range indices {
+		index := index
+		rls, err := c.decodeRelease(context.Background(), &index)
 		if err != nil {
-			return nil, fmt.Errorf("list: failed to decode release for key %q: %w", indexSecret.Labels["key"], err)
+			return nil, fmt.Errorf("list: failed to decode release for key %q: %w", index.Labels["key"], err)
 		}
-		rls.Labels = indexSecret.Labels
+		rls.Labels = filterProjectionLabels(index.Labels)
 		if filter(rls) {
 			results = append(results, rls)
 		}
@@ -319,7 +378,7 @@ func (c *chunkedSecrets) List(filter func(*release.Release) bool) ([]*release.Re
 	return results, nil
 }
 
-func (c *chunkedSecrets) Query(queryLabels map[string]string) ([]*release.Release, error) {
+func (c *chunked) Query(queryLabels map[string]string) ([]*release.Release, error) {
 	for k, v := range queryLabels {
 		if k == "owner" && v == "helm" {
 			// Helm hardcodes some queries with owner=helm. We'll translate this
@@ -330,45 +389,73 @@ func (c *chunkedSecrets) Query(queryLabels map[string]string) ([]*release.Releas
 	c.Log("query: labels=%v", queryLabels)
 	defer c.Log("queried: labels=%v", queryLabels)
 
-	// The only labels that get stored on the index secret are system labels, so we'll do a two-pass
-	// query. First, we'll request index secrets from the API server that match the query labels that
-	// are system labels. From there, we decode the releases that match, and then further filter those
-	// based on the rest of the query labels that are not system labels.
-	serverSelectorSet := labels.Set{}
+	// System labels and projectable custom labels are stored on the index, so we'll do a two-pass
+	// query. First, we'll request indices from the store that match the query labels that are stored
+	// on the index. From there, we decode the releases that match, and then further filter those
+	// based on the rest of the query labels.
+	//
+	// Indices written before custom labels were projected onto them do not carry the projected
+	// labels, so we query those separately using only the system labels and filter all custom labels
+	// client-side.
+	systemSelectorSet := labels.Set{}
+	projectedSelectorSet := labels.Set{}
+	customSelectorSet := labels.Set{}
 	clientSelectorSet := labels.Set{}
 	for k, v := range queryLabels {
-		if isSystemLabel(k) {
-			serverSelectorSet[k] = v
-		} else {
+		switch {
+		case isSystemLabel(k):
+			systemSelectorSet[k] = v
+		case isProjectableLabel(k, v):
+			projectedSelectorSet[customLabelPrefix+k] = v
+			customSelectorSet[k] = v
+		default:
+			customSelectorSet[k] = v
 			clientSelectorSet[k] = v
 		}
 	}
 
-	// Pass 1: build the server selector and query for index secrets
-	serverSelector := newListIndicesLabelSelector(c.owner)
-	if queryRequirements, selectable := serverSelectorSet.AsSelector().Requirements(); selectable {
-		serverSelector = serverSelector.Add(queryRequirements...)
+	// Pass 1: build the server selectors and query for indices
+	projectedSelector := newListProjectedIndicesLabelSelector(c.owner)
+	legacySelector := newListLegacyIndicesLabelSelector(c.owner)
+	if queryRequirements, selectable := systemSelectorSet.AsSelector().Requirements(); selectable {
+		projectedSelector = projectedSelector.Add(queryRequirements...)
+		legacySelector = legacySelector.Add(queryRequirements...)
+	}
+	if queryRequirements, selectable := projectedSelectorSet.AsSelector().Requirements(); selectable {
+		projectedSelector = projectedSelector.Add(queryRequirements...)
 	}
 
-	indexSecrets, err := c.client.List(context.Background(), metav1.ListOptions{LabelSelector: serverSelector.String()})
+	projectedIndices, err := c.store.List(context.Background(), projectedSelector)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	legacyIndices, err := c.store.List(context.Background(), legacySelector)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 
-	// Pass 2: decode the releases that matched the server selector and filter based on the client selector
-	results := make([]*release.Release, 0, len(indexSecrets.Items))
-	clientSelector := clientSelectorSet.AsSelector()
-	for _, indexSecret := range indexSecrets.Items {
-		indexSecret := indexSecret
-		rls, err := c.decodeRelease(context.Background(), &indexSecret)
-		if err != nil {
-			return nil, fmt.Errorf("query: failed to decode release: %w", err)
-		}
+	// Pass 2: decode the releases that matched the server selectors and filter based on the client selectors
+	results := make([]*release.Release, 0, len(projectedIndices)+len(legacyIndices))
+	decodeAndFilter := func(indices []StoredObject, clientSelector labels.Selector) error {
+		for _, index := range indices {
+			index := index
+			rls, err := c.decodeRelease(context.Background(), &index)
+			if err != nil {
+				return fmt.Errorf("query: failed to decode release: %w", err)
+			}
 
-		if !clientSelector.Matches(labels.Set(rls.Labels)) {
-			continue
+			if !clientSelector.Matches(labels.Set(rls.Labels)) {
+				continue
+			}
+			results = append(results, rls)
 		}
-		results = append(results, rls)
+		return nil
+	}
+	if err := decodeAndFilter(projectedIndices, clientSelectorSet.AsSelector()); err != nil {
+		return nil, err
+	}
+	if err := decodeAndFilter(legacyIndices, customSelectorSet.AsSelector()); err != nil {
+		return nil, err
 	}
 
 	if len(results) == 0 {
@@ -378,14 +465,14 @@ func (c *chunkedSecrets) Query(queryLabels map[string]string) ([]*release.Releas
 	return results, nil
 }
 
-func (c *chunkedSecrets) Name() string {
-	return fmt.Sprintf("%s/chunkedSecrets", c.owner)
+func (c *chunked) Name() string {
+	return fmt.Sprintf("%s/chunked%s", c.owner, c.store.Name())
 }
 
-func (c *chunkedSecrets) decodeRelease(ctx context.Context, indexSecret *corev1.Secret) (*release.Release, error) {
-	extraChunkNamesData, ok := indexSecret.Data["extraChunks"]
+func (c *chunked) decodeRelease(ctx context.Context, index *StoredObject) (*release.Release, error) {
+	extraChunkNamesData, ok := index.Data["extraChunks"]
 	if !ok {
-		return nil, fmt.Errorf("index secret %q missing chunks data: %#v", indexSecret.Name, indexSecret)
+		return nil, fmt.Errorf("index %q missing chunks data: %#v", index.Name, index)
 	}
 
 	var extraChunkNames []string
@@ -393,31 +480,38 @@ func (c *chunkedSecrets) decodeRelease(ctx context.Context, indexSecret *corev1.
 		return nil, fmt.Errorf("failed to parse chunk names from index: %w", err)
 	}
 
+	// Indices written before the codec was recorded are always gzipped.
+	codec, err := codecForName(c.Codec, string(index.Data["codec"]))
+	if err != nil {
+		return nil, fmt.Errorf("index %q: %w", index.Name, err)
+	}
+
 	if c.MaxReadChunks > 0 && 1+len(extraChunkNames) > c.MaxReadChunks {
-		return nil, fmt.Errorf("release too large: %q consists of %d chunks, which exceeds the maximum of %d", indexSecret.Name, 1+len(extraChunkNames), c.MaxReadChunks)
+		return nil, fmt.Errorf("release too large: %q consists of %d chunks, which exceeds the maximum of %d", index.Name, 1+len(extraChunkNames), c.MaxReadChunks)
 	}
 
 	pr, pw := io.Pipe()
+	defer pr.Close()
 	go func() {
 		defer pw.Close()
-		firstChunkData, ok := indexSecret.Data["chunk"]
+		firstChunkData, ok := index.Data["chunk"]
 		if !ok {
-			pw.CloseWithError(fmt.Errorf("index secret %q missing chunk %d data", indexSecret.Name, 1))
+			pw.CloseWithError(fmt.Errorf("index %q missing chunk %d data", index.Name, 1))
 			return
 		}
 		if _, err := pw.Write(firstChunkData); err != nil {
-			pw.CloseWithError(fmt.Errorf("failed to write chunk %d data from %q: %w", 1, indexSecret.Name, err))
+			pw.CloseWithError(fmt.Errorf("failed to write chunk %d data from %q: %w", 1, index.Name, err))
 			return
 		}
 		for i, chunkName := range extraChunkNames {
-			chunkSecret, err := c.client.Get(ctx, chunkName, metav1.GetOptions{})
+			chunkObj, err := c.store.Get(ctx, chunkName)
 			if err != nil {
-				pw.CloseWithError(fmt.Errorf("failed to get chunk %d secret %q: %w", i+2, chunkName, err))
+				pw.CloseWithError(fmt.Errorf("failed to get chunk %d %q: %w", i+2, chunkName, err))
 				return
 			}
-			chunkData, ok := chunkSecret.Data["chunk"]
+			chunkData, ok := chunkObj.Data["chunk"]
 			if !ok {
-				pw.CloseWithError(fmt.Errorf("chunk %d secret %q missing chunk data", i+2, chunkName))
+				pw.CloseWithError(fmt.Errorf("chunk %d %q missing chunk data", i+2, chunkName))
 				return
 			}
 			if _, err := pw.Write(chunkData); err != nil {
@@ -427,11 +521,12 @@ func (c *chunkedSecrets) decodeRelease(ctx context.Context, indexSecret *corev1.
 		}
 	}()
 
-	gzr, err := gzip.NewReader(pr)
+	cr, err := codec.NewReader(pr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		return nil, fmt.Errorf("failed to create %s reader: %w", codec.Name(), err)
 	}
-	releaseDecoder := json.NewDecoder(gzr)
+	defer cr.Close()
+	releaseDecoder := json.NewDecoder(cr)
 	var wrappedRelease releaseWrapper
 	if err := releaseDecoder.Decode(&wrappedRelease); err != nil {
 		return nil, fmt.Errorf("failed to decode release: %w", err)
@@ -442,7 +537,7 @@ func (c *chunkedSecrets) decodeRelease(ctx context.Context, indexSecret *corev1.
 	return &r, nil
 }
 
-func (c *chunkedSecrets) hashForData(data []byte) string {
+func (c *chunked) hashForData(data []byte) string {
 	c.hashMu.Lock()
 	defer c.hashMu.Unlock()
 
@@ -27,7 +27,7 @@ var _ = Describe("chunkedSecrets", func() {
 
 	BeforeEach(func() {
 		secretInterface = clientcorev1.NewForConfigOrDie(cfg).Secrets("default")
-		chunkedDriver = NewChunkedSecrets(secretInterface, "test-owner", ChunkedSecretsConfig{
+		chunkedDriver = NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
 			ChunkSize:      chunkSize,
 			MaxReadChunks:  2,
 			MaxWriteChunks: 2,
@@ -87,7 +87,7 @@ var _ = Describe("chunkedSecrets", func() {
 			rel := genRelease("test-release", 1, release.StatusPendingInstall, nil, chunkSize*2)
 			Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
 
-			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedSecretsConfig{
+			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
 				ChunkSize:      chunkSize,
 				MaxReadChunks:  1,
 				MaxWriteChunks: 2,
@@ -164,7 +164,7 @@ var _ = Describe("chunkedSecrets", func() {
 			rel := genRelease("test-release", 1, release.StatusPendingInstall, nil, chunkSize*2)
 			Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
 
-			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedSecretsConfig{
+			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
 				ChunkSize:      chunkSize,
 				MaxReadChunks:  1,
 				MaxWriteChunks: 2,
@@ -216,7 +216,7 @@ var _ = Describe("chunkedSecrets", func() {
 		})
 
 		It("should fail if any release is too large", func() {
-			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedSecretsConfig{
+			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
 				ChunkSize:      chunkSize,
 				MaxReadChunks:  1,
 				MaxWriteChunks: 2,
@@ -250,13 +250,51 @@ var _ = Describe("chunkedSecrets", func() {
 			Expect(key1Releases).To(HaveLen(1))
 		})
 
+		It("should project custom labels onto the index secret", func() {
+			indexSecret, err := secretInterface.Get(context.Background(), "b.v3", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexSecret.Labels).To(HaveKeyWithValue(customLabelPrefix+"key1", "val1"))
+			Expect(indexSecret.Labels).To(HaveKeyWithValue(customLabelPrefix+"globalKey", "globalValue"))
+			Expect(indexSecret.Labels).To(HaveKeyWithValue(customLabelsProjectedLabel, customLabelsProjectedValue))
+		})
+
+		It("should query legacy index secrets without projected custom labels", func() {
+			indexSecret, err := secretInterface.Get(context.Background(), "b.v3", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			for k := range indexSecret.Labels {
+				if isProjectionLabel(k) {
+					delete(indexSecret.Labels, k)
+				}
+			}
+			_, err = secretInterface.Update(context.Background(), indexSecret, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			key1Releases, err := chunkedDriver.Query(map[string]string{"key1": "val1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key1Releases).To(HaveLen(1))
+
+			globalReleases, err := chunkedDriver.Query(map[string]string{"globalKey": "globalValue"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(globalReleases).To(HaveLen(7))
+		})
+
+		It("should not return projected custom labels when listing releases", func() {
+			allReleases, err := chunkedDriver.List(func(_ *release.Release) bool { return true })
+			Expect(err).ToNot(HaveOccurred())
+			for _, rel := range allReleases {
+				for k := range rel.Labels {
+					Expect(isProjectionLabel(k)).To(BeFalse())
+				}
+			}
+		})
+
 		It("should return ErrReleaseNotFound when there is no match", func() {
 			_, err := chunkedDriver.Query(map[string]string{"nonexistentKey": "nonexistentVal"})
 			Expect(err).To(MatchError(driver.ErrReleaseNotFound))
 		})
 
 		It("should succeed if no matched release is too large", func() {
-			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedSecretsConfig{
+			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
 				ChunkSize:      chunkSize,
 				MaxReadChunks:  1,
 				MaxWriteChunks: 2,
@@ -267,7 +305,7 @@ var _ = Describe("chunkedSecrets", func() {
 		})
 
 		It("should fail if any matched release is too large", func() {
-			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedSecretsConfig{
+			maxReadDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
 				ChunkSize:      chunkSize,
 				MaxReadChunks:  1,
 				MaxWriteChunks: 2,
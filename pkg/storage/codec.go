@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec compresses and decompresses the encoded release before it is split
+// into chunks. The name of the codec is recorded in the index, so that
+// releases can be read regardless of the codec that is configured when they
+// are read.
+type Codec interface {
+	// Name returns the name under which the codec is recorded in the index.
+	Name() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+const (
+	CodecNameGzip = "gzip"
+	CodecNameZstd = "zstd"
+)
+
+// GzipCodec returns a Codec that compresses with gzip at the given level.
+// It is the default codec, and it is assumed for indices that do not record
+// a codec.
+func GzipCodec(level int) Codec {
+	return gzipCodec{level: level}
+}
+
+type gzipCodec struct {
+	level int
+}
+
+func (gzipCodec) Name() string {
+	return CodecNameGzip
+}
+
+func (c gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, c.level)
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// ZstdCodec returns a Codec that compresses with zstd at the given level.
+// zstd is considerably faster than gzip at comparable compression ratios,
+// which matters for large releases.
+func ZstdCodec(level zstd.EncoderLevel) Codec {
+	return zstdCodec{level: level}
+}
+
+type zstdCodec struct {
+	level zstd.EncoderLevel
+}
+
+func (zstdCodec) Name() string {
+	return CodecNameZstd
+}
+
+func (c zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w, zstd.WithEncoderLevel(c.level), zstd.WithEncoderConcurrency(1))
+}
+
+func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return zr.IOReadCloser(), nil
+}
+
+// CodecForName returns the codec with the given name at its default level,
+// which is gzip.BestCompression for gzip and zstd.SpeedDefault for zstd.
+func CodecForName(name string) (Codec, error) {
+	switch name {
+	case CodecNameGzip:
+		return GzipCodec(gzip.BestCompression), nil
+	case CodecNameZstd:
+		return ZstdCodec(zstd.SpeedDefault), nil
+	}
+	return nil, fmt.Errorf("unknown codec %q", name)
+}
+
+// codecForName returns the codec to decode data recorded with name. The
+// configured codec takes precedence, so that custom codecs can be read back.
+func codecForName(configured Codec, name string) (Codec, error) {
+	if name == "" {
+		name = CodecNameGzip
+	}
+	if configured != nil && configured.Name() == name {
+		return configured, nil
+	}
+	return CodecForName(name)
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/klauspost/compress/zstd"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var _ = Describe("Codec", func() {
+	DescribeTable("should round-trip data",
+		func(codec Codec) {
+			expected := bytes.Repeat([]byte("some release data "), 1000)
+			buf := &bytes.Buffer{}
+			w, err := codec.NewWriter(buf)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = w.Write(expected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(w.Close()).To(Succeed())
+			Expect(buf.Len()).To(BeNumerically("<", len(expected)))
+
+			r, err := codec.NewReader(buf)
+			Expect(err).ToNot(HaveOccurred())
+			defer r.Close()
+			actual, err := io.ReadAll(r)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(expected))
+		},
+		Entry("gzip", GzipCodec(gzip.BestSpeed)),
+		Entry("zstd", ZstdCodec(zstd.SpeedFastest)),
+	)
+
+	It("should default to gzip for indices that do not record a codec", func() {
+		codec, err := codecForName(ZstdCodec(zstd.SpeedDefault), "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codec.Name()).To(Equal(CodecNameGzip))
+	})
+
+	It("should fail for unknown codecs", func() {
+		_, err := codecForName(nil, "lz4")
+		Expect(err).To(MatchError(ContainSubstring(`unknown codec "lz4"`)))
+	})
+})
+
+var _ = Describe("adaptiveChunkSize", func() {
+	DescribeTable("should use the fewest evenly sized chunks",
+		func(dataSize, maxChunkSize, expected int) {
+			Expect(adaptiveChunkSize(dataSize, maxChunkSize)).To(Equal(expected))
+		},
+		Entry("empty data", 0, 100, 1),
+		Entry("data fits into one chunk", 50, 100, 50),
+		Entry("data fills one chunk", 100, 100, 100),
+		Entry("data barely needs two chunks", 101, 100, 51),
+		Entry("data needs three chunks", 250, 100, 84),
+	)
+})
+
+var _ = Describe("chunkedSecrets codecs", func() {
+	var secretInterface clientcorev1.SecretInterface
+
+	BeforeEach(func() {
+		secretInterface = clientcorev1.NewForConfigOrDie(cfg).Secrets("default")
+	})
+
+	AfterEach(func() {
+		Expect(secretInterface.DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{})).To(Succeed())
+	})
+
+	It("should record the codec and read releases written with another codec", func() {
+		zstdDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
+			ChunkSize: 1000,
+			Codec:     ZstdCodec(zstd.SpeedFastest),
+		})
+		gzipDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
+			ChunkSize: 1000,
+		})
+
+		expected := genRelease("test-release", 1, release.StatusDeployed, nil, 3000)
+		Expect(zstdDriver.Create(releaseKey(expected), expected)).To(Succeed())
+
+		index, err := secretInterface.Get(context.Background(), releaseKey(expected), metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(index.Data).To(HaveKeyWithValue("codec", []byte(CodecNameZstd)))
+
+		actual, err := gzipDriver.Get(releaseKey(expected))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(expected))
+	})
+
+	It("should read indices that do not record a codec as gzip", func() {
+		chunkedDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{ChunkSize: 1000})
+		expected := genRelease("test-release", 1, release.StatusDeployed, nil, 100)
+		Expect(chunkedDriver.Create(releaseKey(expected), expected)).To(Succeed())
+
+		index, err := secretInterface.Get(context.Background(), releaseKey(expected), metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		delete(index.Data, "codec")
+		_, err = secretInterface.Update(context.Background(), index, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		actual, err := chunkedDriver.Get(releaseKey(expected))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(expected))
+	})
+
+	It("should pick the chunk size from the maximum object size in adaptive mode", func() {
+		const maxObjectSize = adaptiveChunkSizeReserve + 1000
+		chunkedDriver := NewChunkedSecrets(secretInterface, "test-owner", ChunkedConfig{
+			ChunkSize:         10,
+			AdaptiveChunkSize: true,
+			MaxObjectSize:     maxObjectSize,
+		})
+
+		expected := genRelease("test-release", 1, release.StatusDeployed, nil, 5000)
+		Expect(chunkedDriver.Create(releaseKey(expected), expected)).To(Succeed())
+
+		index, err := secretInterface.Get(context.Background(), releaseKey(expected), metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		var extraChunkNames []string
+		Expect(json.Unmarshal(index.Data["extraChunks"], &extraChunkNames)).To(Succeed())
+		Expect(extraChunkNames).ToNot(BeEmpty())
+
+		chunkSizes := []int{len(index.Data["chunk"])}
+		for _, name := range extraChunkNames {
+			chunkSecret, err := secretInterface.Get(context.Background(), name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			chunkSizes = append(chunkSizes, len(chunkSecret.Data["chunk"]))
+		}
+		totalSize := 0
+		for _, size := range chunkSizes {
+			Expect(size).To(BeNumerically("<=", 1000))
+			totalSize += size
+		}
+		Expect(chunkSizes).To(HaveLen((totalSize + 999) / 1000))
+		for _, size := range chunkSizes[:len(chunkSizes)-1] {
+			Expect(size).To(Equal(chunkSizes[0]))
+		}
+
+		actual, err := chunkedDriver.Get(releaseKey(expected))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(expected))
+	})
+})
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// NewChunkedConfigMaps returns a chunked storage driver that persists
+// releases as ConfigMaps. ConfigMaps are not meant to hold sensitive data,
+// so it should only be used for releases that do not contain any.
+func NewChunkedConfigMaps(client clientcorev1.ConfigMapInterface, owner string, config ChunkedConfig) driver.Driver {
+	return NewChunked(NewConfigMapsStore(client), owner, config)
+}
+
+var _ ObjectStore = (*configMapsStore)(nil)
+
+// NewConfigMapsStore returns an ObjectStore that persists objects as
+// ConfigMaps.
+func NewConfigMapsStore(client clientcorev1.ConfigMapInterface) ObjectStore {
+	return &configMapsStore{client: client}
+}
+
+type configMapsStore struct {
+	client clientcorev1.ConfigMapInterface
+}
+
+func (s *configMapsStore) Name() string {
+	return "ConfigMaps"
+}
+
+func (s *configMapsStore) Get(ctx context.Context, name string) (*StoredObject, error) {
+	cm, err := s.client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return storedObjectFromConfigMap(cm), nil
+}
+
+func (s *configMapsStore) List(ctx context.Context, selector labels.Selector) ([]StoredObject, error) {
+	cms, err := s.client.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]StoredObject, 0, len(cms.Items))
+	for i := range cms.Items {
+		objs = append(objs, *storedObjectFromConfigMap(&cms.Items[i]))
+	}
+	return objs, nil
+}
+
+func (s *configMapsStore) Create(ctx context.Context, obj *StoredObject) (*StoredObject, error) {
+	cm, err := s.client.Create(ctx, configMapFromStoredObject(obj), metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return storedObjectFromConfigMap(cm), nil
+}
+
+func (s *configMapsStore) Update(ctx context.Context, obj *StoredObject) (*StoredObject, error) {
+	cm, err := s.client.Update(ctx, configMapFromStoredObject(obj), metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return storedObjectFromConfigMap(cm), nil
+}
+
+func (s *configMapsStore) Delete(ctx context.Context, name string) error {
+	return s.client.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (s *configMapsStore) DeleteCollection(ctx context.Context, selector labels.Selector) error {
+	return s.client.DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector.String()})
+}
+
+// ConfigMaps do not have a type field, so the object type is derived from the
+// "type" label that the chunked driver sets on every object it stores.
+func configMapFromStoredObject(obj *StoredObject) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            obj.Name,
+			Labels:          obj.Labels,
+			OwnerReferences: ownerReferencesFor(obj.Owner, "ConfigMap"),
+		},
+		Immutable:  ptr.To(obj.Immutable),
+		BinaryData: obj.Data,
+	}
+}
+
+func storedObjectFromConfigMap(cm *corev1.ConfigMap) *StoredObject {
+	return &StoredObject{
+		Name:      cm.Name,
+		UID:       cm.UID,
+		Type:      ObjectType(cm.Labels["type"]),
+		Labels:    cm.Labels,
+		Immutable: ptr.Deref(cm.Immutable, false),
+		Data:      cm.BinaryData,
+		Owner:     ownerFromReferences(cm.OwnerReferences, "ConfigMap"),
+
+		CreationTimestamp: cm.CreationTimestamp.Time,
+	}
+}
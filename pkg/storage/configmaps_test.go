@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("chunkedConfigMaps", func() {
+	const chunkSize = 1000
+	var (
+		configMapInterface clientcorev1.ConfigMapInterface
+		chunkedDriver      driver.Driver
+	)
+
+	BeforeEach(func() {
+		configMapInterface = clientcorev1.NewForConfigOrDie(cfg).ConfigMaps("default")
+		chunkedDriver = NewChunkedConfigMaps(configMapInterface, "test-owner", ChunkedConfig{
+			ChunkSize:      chunkSize,
+			MaxReadChunks:  2,
+			MaxWriteChunks: 2,
+		})
+	})
+
+	AfterEach(func() {
+		Expect(configMapInterface.DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{})).To(Succeed())
+	})
+
+	It("should be named after the backend", func() {
+		Expect(chunkedDriver.Name()).To(Equal("test-owner/chunkedConfigMaps"))
+	})
+
+	It("should create and get a large release with multiple config maps", func() {
+		expected := genRelease("test-release", 1, release.StatusPendingInstall, nil, chunkSize*2)
+		Expect(chunkedDriver.Create(releaseKey(expected), expected)).To(Succeed())
+
+		items, err := configMapInterface.List(context.Background(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items.Items).To(HaveLen(2))
+		for _, cm := range items.Items {
+			Expect(cm.BinaryData).To(HaveKey("chunk"))
+			switch cm.Labels["type"] {
+			case "index":
+				Expect(cm.Name).To(Equal(releaseKey(expected)))
+				Expect(cm.Immutable).To(Equal(ptr.To(false)))
+				var extraChunks []string
+				Expect(json.Unmarshal(cm.BinaryData["extraChunks"], &extraChunks)).To(Succeed())
+				Expect(extraChunks).To(HaveLen(1))
+			case "chunk":
+				Expect(cm.Immutable).To(Equal(ptr.To(true)))
+				Expect(cm.OwnerReferences).To(HaveLen(1))
+				Expect(cm.OwnerReferences[0].Kind).To(Equal("ConfigMap"))
+				Expect(cm.OwnerReferences[0].Name).To(Equal(releaseKey(expected)))
+			default:
+				Fail("unexpected config map type " + cm.Labels["type"])
+			}
+		}
+
+		actual, err := chunkedDriver.Get(releaseKey(expected))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(expected))
+	})
+
+	It("should update, query and delete releases", func() {
+		rel := genRelease("test-release", 1, release.StatusPendingInstall, nil, chunkSize/2)
+		Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
+
+		rel.Info.Status = release.StatusDeployed
+		Expect(chunkedDriver.Update(releaseKey(rel), rel)).To(Succeed())
+
+		rels, err := chunkedDriver.Query(map[string]string{"status": release.StatusDeployed.String()})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rels).To(ConsistOf(rel))
+
+		deleted, err := chunkedDriver.Delete(releaseKey(rel))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(Equal(rel))
+
+		_, err = chunkedDriver.Get(releaseKey(rel))
+		Expect(err).To(MatchError(driver.ErrReleaseNotFound))
+	})
+})
@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/storage/driver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// NewChunkedFilesystem returns a chunked storage driver that persists
+// releases in the local directory dir. The chunks it writes are identical to
+// those written by the Secrets and ConfigMaps backends for the same release
+// and configuration, which makes it suitable for offline rendering, tests and
+// exports of release storage.
+func NewChunkedFilesystem(dir, owner string, config ChunkedConfig) (driver.Driver, error) {
+	store, err := NewFilesystemStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return NewChunked(store, owner, config), nil
+}
+
+var _ ObjectStore = (*filesystemStore)(nil)
+
+// NewFilesystemStore returns an ObjectStore that persists each object as a
+// directory below dir. The directory of an object contains its metadata in
+// a "metadata.json" file and each of its data keys as a file with the raw
+// data in the "data" directory.
+func NewFilesystemStore(dir string) (ObjectStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create storage directory %q: %w", dir, err)
+	}
+	return &filesystemStore{dir: dir}, nil
+}
+
+var filesGroupResource = schema.GroupResource{Resource: "files"}
+
+const (
+	fileMetadataName = "metadata.json"
+	fileDataDir      = "data"
+)
+
+type filesystemStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+type fileObjectMetadata struct {
+	Name      string            `json:"name"`
+	UID       types.UID         `json:"uid"`
+	Type      ObjectType        `json:"type"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Immutable bool              `json:"immutable,omitempty"`
+	Owner     *ObjectReference  `json:"owner,omitempty"`
+
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+func (s *filesystemStore) Name() string {
+	return "Files"
+}
+
+func (s *filesystemStore) Get(_ context.Context, name string) (*StoredObject, error) {
+	if err := validateFileObjectName(name); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.read(name)
+}
+
+func (s *filesystemStore) List(_ context.Context, selector labels.Selector) ([]StoredObject, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var objs []StoredObject
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		obj, err := s.read(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if selector.Matches(labels.Set(obj.Labels)) {
+			objs = append(objs, *obj)
+		}
+	}
+	return objs, nil
+}
+
+func (s *filesystemStore) Create(_ context.Context, obj *StoredObject) (*StoredObject, error) {
+	if err := validateFileObjectName(obj.Name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.objectDir(obj.Name)); err == nil {
+		return nil, apierrors.NewAlreadyExists(filesGroupResource, obj.Name)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	out := *obj
+	out.UID = uuid.NewUUID()
+	out.CreationTimestamp = time.Now().UTC().Truncate(time.Second)
+	if err := s.write(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *filesystemStore) Update(_ context.Context, obj *StoredObject) (*StoredObject, error) {
+	if err := validateFileObjectName(obj.Name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.read(obj.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Immutable {
+		return nil, apierrors.NewInvalid(schema.GroupKind{Kind: "File"}, obj.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("data"), "field is immutable when `immutable` is set"),
+		})
+	}
+
+	out := *obj
+	out.UID = existing.UID
+	out.CreationTimestamp = existing.CreationTimestamp
+	if err := s.write(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *filesystemStore) Delete(_ context.Context, name string) error {
+	if err := validateFileObjectName(name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.objectDir(name)); err != nil {
+		if os.IsNotExist(err) {
+			return apierrors.NewNotFound(filesGroupResource, name)
+		}
+		return err
+	}
+	return os.RemoveAll(s.objectDir(name))
+}
+
+func (s *filesystemStore) DeleteCollection(_ context.Context, selector labels.Selector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		obj, err := s.read(entry.Name())
+		if err != nil {
+			return err
+		}
+		if !selector.Matches(labels.Set(obj.Labels)) {
+			continue
+		}
+		if err := os.RemoveAll(s.objectDir(obj.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *filesystemStore) objectDir(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+func (s *filesystemStore) read(name string) (*StoredObject, error) {
+	objDir := s.objectDir(name)
+	metadataData, err := os.ReadFile(filepath.Join(objDir, fileMetadataName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, apierrors.NewNotFound(filesGroupResource, name)
+		}
+		return nil, err
+	}
+	var metadata fileObjectMetadata
+	if err := json.Unmarshal(metadataData, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata of %q: %w", name, err)
+	}
+
+	dataEntries, err := os.ReadDir(filepath.Join(objDir, fileDataDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(dataEntries))
+	for _, entry := range dataEntries {
+		value, err := os.ReadFile(filepath.Join(objDir, fileDataDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		data[entry.Name()] = value
+	}
+
+	return &StoredObject{
+		Name:      metadata.Name,
+		UID:       metadata.UID,
+		Type:      metadata.Type,
+		Labels:    metadata.Labels,
+		Immutable: metadata.Immutable,
+		Data:      data,
+		Owner:     metadata.Owner,
+
+		CreationTimestamp: metadata.CreationTimestamp,
+	}, nil
+}
+
+// write writes obj into a temporary directory and then swaps it in place of
+// the object's directory, so that readers never observe a partially written
+// object.
+func (s *filesystemStore) write(obj *StoredObject) error {
+	tmpDir, err := os.MkdirTemp(s.dir, "."+obj.Name+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	metadataData, err := json.Marshal(fileObjectMetadata{
+		Name:      obj.Name,
+		UID:       obj.UID,
+		Type:      obj.Type,
+		Labels:    obj.Labels,
+		Immutable: obj.Immutable,
+		Owner:     obj.Owner,
+
+		CreationTimestamp: obj.CreationTimestamp,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, fileMetadataName), metadataData, 0o600); err != nil {
+		return err
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, fileDataDir), 0o700); err != nil {
+		return err
+	}
+	for k, v := range obj.Data {
+		if errs := validation.IsConfigMapKey(k); len(errs) > 0 {
+			return fmt.Errorf("invalid data key %q: %s", k, strings.Join(errs, ", "))
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, fileDataDir, k), v, 0o600); err != nil {
+			return err
+		}
+	}
+
+	objDir := s.objectDir(obj.Name)
+	oldDir := ""
+	if _, err := os.Stat(objDir); err == nil {
+		oldDir = tmpDir + ".old"
+		if err := os.Rename(objDir, oldDir); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmpDir, objDir); err != nil {
+		if oldDir != "" {
+			_ = os.Rename(oldDir, objDir)
+		}
+		return err
+	}
+	if oldDir != "" {
+		return os.RemoveAll(oldDir)
+	}
+	return nil
+}
+
+func validateFileObjectName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Kind: "File"}, name, field.ErrorList{
+			field.Invalid(field.NewPath("metadata", "name"), name, strings.Join(errs, ", ")),
+		})
+	}
+	return nil
+}
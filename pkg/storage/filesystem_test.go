@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var _ = Describe("chunkedFilesystem", func() {
+	const chunkSize = 1000
+	var (
+		dir           string
+		chunkedDriver driver.Driver
+		config        ChunkedConfig
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		config = ChunkedConfig{
+			ChunkSize:      chunkSize,
+			MaxReadChunks:  2,
+			MaxWriteChunks: 2,
+		}
+		var err error
+		chunkedDriver, err = NewChunkedFilesystem(dir, "test-owner", config)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should be named after the backend", func() {
+		Expect(chunkedDriver.Name()).To(Equal("test-owner/chunkedFiles"))
+	})
+
+	It("should create and get a large release", func() {
+		expected := genRelease("test-release", 1, release.StatusPendingInstall, nil, chunkSize*2)
+		Expect(chunkedDriver.Create(releaseKey(expected), expected)).To(Succeed())
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+		for _, entry := range entries {
+			Expect(filepath.Join(dir, entry.Name(), "metadata.json")).To(BeARegularFile())
+			Expect(filepath.Join(dir, entry.Name(), "data", "chunk")).To(BeARegularFile())
+		}
+
+		actual, err := chunkedDriver.Get(releaseKey(expected))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(expected))
+	})
+
+	It("should update, list and delete releases", func() {
+		rel := genRelease("test-release", 1, release.StatusPendingInstall, nil, chunkSize*2)
+		Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
+
+		rel.Info.Status = release.StatusDeployed
+		Expect(chunkedDriver.Update(releaseKey(rel), rel)).To(Succeed())
+
+		rels, err := chunkedDriver.List(func(*release.Release) bool { return true })
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rels).To(HaveLen(1))
+		Expect(rels[0].Info.Status).To(Equal(release.StatusDeployed))
+
+		_, err = chunkedDriver.Delete(releaseKey(rel))
+		Expect(err).ToNot(HaveOccurred())
+		entries, err := os.ReadDir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should persist the same chunks as the Secrets backend", func() {
+		secretInterface := clientcorev1.NewForConfigOrDie(cfg).Secrets("default")
+		DeferCleanup(func() {
+			Expect(secretInterface.DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{})).To(Succeed())
+		})
+		secretsDriver := NewChunkedSecrets(secretInterface, "test-owner", config)
+
+		rel := genRelease("test-release", 1, release.StatusPendingInstall, nil, chunkSize*2)
+		Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
+		Expect(secretsDriver.Create(releaseKey(rel), rel)).To(Succeed())
+
+		secrets, err := secretInterface.List(context.Background(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secrets.Items).To(HaveLen(2))
+		for _, secret := range secrets.Items {
+			fileData, err := os.ReadFile(filepath.Join(dir, secret.Name, "data", "chunk"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fileData).To(Equal(secret.Data["chunk"]))
+		}
+	})
+
+	Describe("store", func() {
+		var store ObjectStore
+
+		BeforeEach(func() {
+			var err error
+			store, err = NewFilesystemStore(dir)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return not found and already exists errors", func() {
+			_, err := store.Get(context.Background(), "missing")
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			obj := &StoredObject{Name: "obj", Type: ObjectTypeIndex, Data: map[string][]byte{"chunk": []byte("data")}}
+			created, err := store.Create(context.Background(), obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created.UID).ToNot(BeEmpty())
+
+			_, err = store.Create(context.Background(), obj)
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		})
+
+		It("should reject updates of immutable objects", func() {
+			obj := &StoredObject{Name: "obj", Type: ObjectTypeChunk, Immutable: true, Data: map[string][]byte{"chunk": []byte("data")}}
+			_, err := store.Create(context.Background(), obj)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = store.Update(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("should reject invalid object names", func() {
+			_, err := store.Create(context.Background(), &StoredObject{Name: "../escape"})
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("should only delete objects matching the selector", func() {
+			for _, name := range []string{"a", "b"} {
+				_, err := store.Create(context.Background(), &StoredObject{Name: name, Labels: map[string]string{"name": name}})
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(store.DeleteCollection(context.Background(), labels.SelectorFromSet(labels.Set{"name": "a"}))).To(Succeed())
+
+			objs, err := store.List(context.Background(), labels.Everything())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objs).To(HaveLen(1))
+			Expect(objs[0].Name).To(Equal("b"))
+		})
+	})
+})
@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// DefaultGarbageCollectionGracePeriod is the minimum age of an orphaned
+	// chunk before it is deleted. It protects chunks of releases that are
+	// being written while the garbage collector runs.
+	DefaultGarbageCollectionGracePeriod = time.Hour
+
+	// DefaultGarbageCollectionInterval is the interval between garbage
+	// collection runs when the garbage collector runs in a manager.
+	DefaultGarbageCollectionInterval = time.Hour
+)
+
+var (
+	gcChunksDeleted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "helm_operator",
+			Name:      "storage_gc_chunks_deleted_total",
+			Help:      "Number of orphaned chunks deleted from chunked release storage",
+		},
+		[]string{"owner"},
+	)
+	gcBytesReclaimed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "helm_operator",
+			Name:      "storage_gc_bytes_reclaimed_total",
+			Help:      "Number of bytes of chunk data reclaimed by deleting orphaned chunks from chunked release storage",
+		},
+		[]string{"owner"},
+	)
+)
+
+// RegisterGarbageCollectorMetrics registers the garbage collector metrics to
+// be included in metrics collection.
+func RegisterGarbageCollectorMetrics(r prometheus.Registerer) {
+	r.MustRegister(gcChunksDeleted, gcBytesReclaimed)
+}
+
+// GarbageCollectorConfig configures a GarbageCollector.
+type GarbageCollectorConfig struct {
+	// Owners limits garbage collection to chunks of the given owners. All
+	// owners are considered if it is empty.
+	Owners []string
+
+	// GracePeriod is the minimum age of an orphaned chunk before it is
+	// deleted. It defaults to DefaultGarbageCollectionGracePeriod.
+	GracePeriod time.Duration
+
+	// Interval is the interval between runs when the garbage collector runs
+	// in a manager. It defaults to DefaultGarbageCollectionInterval.
+	Interval time.Duration
+
+	// DryRun makes the garbage collector report orphaned chunks without
+	// deleting them.
+	DryRun bool
+
+	Log logr.Logger
+}
+
+// GarbageCollectionResult summarizes a garbage collection run.
+type GarbageCollectionResult struct {
+	// Chunks is the number of orphaned chunks that were deleted, or that
+	// would have been deleted in dry-run mode.
+	Chunks int
+	// Bytes is the size of the chunk data of those chunks.
+	Bytes int64
+}
+
+var (
+	_ manager.Runnable               = (*GarbageCollector)(nil)
+	_ manager.LeaderElectionRunnable = (*GarbageCollector)(nil)
+)
+
+// GarbageCollector deletes chunks that are not referenced by any index of
+// their owner. Such chunks are left behind when a release is only partially
+// written, or when an index is deleted by other means than the storage
+// driver.
+type GarbageCollector struct {
+	store ObjectStore
+	GarbageCollectorConfig
+
+	now func() time.Time
+}
+
+// NewGarbageCollector returns a garbage collector for the chunks in store.
+// It can be run once with Collect, or periodically by adding it to a manager.
+func NewGarbageCollector(store ObjectStore, config GarbageCollectorConfig) *GarbageCollector {
+	if config.GracePeriod <= 0 {
+		config.GracePeriod = DefaultGarbageCollectionGracePeriod
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultGarbageCollectionInterval
+	}
+	if config.Log.GetSink() == nil {
+		config.Log = logr.Discard()
+	}
+	return &GarbageCollector{
+		store:                  store,
+		GarbageCollectorConfig: config,
+		now:                    time.Now,
+	}
+}
+
+// Start runs the garbage collector every Interval until ctx is done. Errors
+// of individual runs are logged and do not stop the garbage collector.
+func (gc *GarbageCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(gc.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := gc.Collect(ctx); err != nil {
+			gc.Log.Error(err, "chunk garbage collection failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes sure that only the leader deletes chunks.
+func (gc *GarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Collect deletes all orphaned chunks that are older than the grace period.
+// Objects that carry the labels of the chunked driver but were not written by
+// it are ignored. If the index of an owner cannot be parsed, no chunks of that
+// owner are deleted and an error is returned after the other owners have been
+// collected.
+func (gc *GarbageCollector) Collect(ctx context.Context) (GarbageCollectionResult, error) {
+	var result GarbageCollectionResult
+
+	// Chunks are listed before indices. Since an index is always written
+	// before its chunks, every chunk that is listed here is referenced by one
+	// of the indices listed below, unless it is orphaned or its index is
+	// being updated concurrently. The grace period covers the latter.
+	chunks, err := gc.store.List(ctx, gc.selectorForType("chunk"))
+	if err != nil {
+		return result, fmt.Errorf("list chunks: %w", err)
+	}
+	if len(chunks) == 0 {
+		return result, nil
+	}
+	indices, err := gc.store.List(ctx, gc.selectorForType("index"))
+	if err != nil {
+		return result, fmt.Errorf("list indices: %w", err)
+	}
+
+	referenced := map[string]sets.Set[string]{}
+	corruptOwners := sets.New[string]()
+	var errs []error
+	for _, index := range indices {
+		if !isChunkedObject(index, ObjectTypeIndex, "extraChunks") {
+			gc.Log.V(1).Info("skipping object that is not a chunked index", "index", index.Name)
+			continue
+		}
+		owner := index.Labels["owner"]
+		if referenced[owner] == nil {
+			referenced[owner] = sets.New[string]()
+		}
+		var extraChunkNames []string
+		if err := json.Unmarshal(index.Data["extraChunks"], &extraChunkNames); err != nil {
+			// Without the list of chunks of this index, we cannot tell which
+			// chunks of its owner are orphaned, so none of them are deleted.
+			corruptOwners.Insert(owner)
+			errs = append(errs, fmt.Errorf("parse chunk names from index %q of owner %q: %w", index.Name, owner, err))
+			continue
+		}
+		referenced[owner].Insert(extraChunkNames...)
+	}
+
+	now := gc.now()
+	for _, ch := range chunks {
+		if !isChunkedObject(ch, ObjectTypeChunk, "chunk") {
+			gc.Log.V(1).Info("skipping object that is not a chunk", "chunk", ch.Name)
+			continue
+		}
+		owner := ch.Labels["owner"]
+		if corruptOwners.Has(owner) || referenced[owner].Has(ch.Name) {
+			continue
+		}
+		if age := now.Sub(createdAt(ch)); age < gc.GracePeriod {
+			gc.Log.V(1).Info("skipping orphaned chunk within grace period", "owner", owner, "chunk", ch.Name, "age", age)
+			continue
+		}
+
+		size := int64(len(ch.Data["chunk"]))
+		if gc.DryRun {
+			gc.Log.Info("found orphaned chunk", "owner", owner, "chunk", ch.Name, "bytes", size)
+		} else {
+			if err := gc.store.Delete(ctx, ch.Name); err != nil && !apierrors.IsNotFound(err) {
+				return result, fmt.Errorf("delete orphaned chunk %q: %w", ch.Name, err)
+			}
+			gc.Log.Info("deleted orphaned chunk", "owner", owner, "chunk", ch.Name, "bytes", size)
+			gcChunksDeleted.WithLabelValues(owner).Inc()
+			gcBytesReclaimed.WithLabelValues(owner).Add(float64(size))
+		}
+		result.Chunks++
+		result.Bytes += size
+	}
+	return result, errors.Join(errs...)
+}
+
+// isChunkedObject returns whether obj is an object of the given type that was
+// written by the chunked driver, as opposed to an object of another tool
+// that happens to have the same labels.
+func isChunkedObject(obj StoredObject, objType ObjectType, dataKey string) bool {
+	_, ok := obj.Data[dataKey]
+	return ok && obj.Type == objType && obj.Labels["key"] != ""
+}
+
+func (gc *GarbageCollector) selectorForType(objType string) labels.Selector {
+	selector := labels.Set{"type": objType}.AsSelector().Add(mustNewRequirement("owner", selection.Exists, nil))
+	if len(gc.Owners) > 0 {
+		selector = selector.Add(mustNewRequirement("owner", selection.In, gc.Owners))
+	}
+	return selector
+}
+
+// createdAt returns the time at which obj was written by the chunked driver,
+// falling back to the creation timestamp of the stored object.
+func createdAt(obj StoredObject) time.Time {
+	if v, ok := obj.Labels["createdAt"]; ok {
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(sec, 0)
+		}
+	}
+	return obj.CreationTimestamp
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ = Describe("GarbageCollector", func() {
+	const chunkSize = 1000
+	var (
+		store         ObjectStore
+		chunkedDriver driver.Driver
+		now           time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		store, err = NewFilesystemStore(GinkgoT().TempDir())
+		Expect(err).ToNot(HaveOccurred())
+		chunkedDriver = NewChunked(store, "test-owner", ChunkedConfig{ChunkSize: chunkSize})
+		now = time.Now().Add(2 * DefaultGarbageCollectionGracePeriod)
+	})
+
+	newGarbageCollector := func(config GarbageCollectorConfig) *GarbageCollector {
+		gc := NewGarbageCollector(store, config)
+		gc.now = func() time.Time { return now }
+		return gc
+	}
+
+	createOrphan := func(owner, name string, createdAt time.Time, size int) {
+		GinkgoHelper()
+		obj := &StoredObject{
+			Name:   name,
+			Type:   ObjectTypeChunk,
+			Labels: newChunkLabels(owner, "deleted-release.v1"),
+			Data:   map[string][]byte{"chunk": make([]byte, size)},
+		}
+		obj.Labels["createdAt"] = strconv.Itoa(int(createdAt.Unix()))
+		_, err := store.Create(context.Background(), obj)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	chunkNames := func() []string {
+		GinkgoHelper()
+		chunks, err := store.List(context.Background(), labels.Set{"type": "chunk"}.AsSelector())
+		Expect(err).ToNot(HaveOccurred())
+		names := make([]string, 0, len(chunks))
+		for _, ch := range chunks {
+			names = append(names, ch.Name)
+		}
+		return names
+	}
+
+	It("should delete orphaned chunks older than the grace period", func() {
+		rel := genRelease("test-release", 1, release.StatusDeployed, nil, chunkSize*2)
+		Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
+		referencedChunks := chunkNames()
+		Expect(referencedChunks).ToNot(BeEmpty())
+
+		createOrphan("test-owner", "orphan-old", now.Add(-2*DefaultGarbageCollectionGracePeriod), 100)
+		createOrphan("test-owner", "orphan-new", now.Add(-DefaultGarbageCollectionGracePeriod/2), 100)
+
+		result, err := newGarbageCollector(GarbageCollectorConfig{}).Collect(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(GarbageCollectionResult{Chunks: 1, Bytes: 100}))
+		Expect(chunkNames()).To(ConsistOf(append(referencedChunks, "orphan-new")))
+
+		actual, err := chunkedDriver.Get(releaseKey(rel))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(rel))
+	})
+
+	It("should treat chunks referenced by another owner's index as orphaned", func() {
+		rel := genRelease("test-release", 1, release.StatusDeployed, nil, chunkSize*2)
+		Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
+		referencedChunks := chunkNames()
+
+		chunk, err := store.Get(context.Background(), referencedChunks[0])
+		Expect(err).ToNot(HaveOccurred())
+		chunk.Name = referencedChunks[0] + "-copy"
+		chunk.Labels = newChunkLabels("other-owner", releaseKey(rel))
+		_, err = store.Create(context.Background(), chunk)
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := newGarbageCollector(GarbageCollectorConfig{GracePeriod: time.Nanosecond}).Collect(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Chunks).To(Equal(1))
+		Expect(chunkNames()).To(ConsistOf(referencedChunks))
+	})
+
+	It("should only consider the configured owners", func() {
+		createOrphan("test-owner", "orphan-a", now.Add(-2*DefaultGarbageCollectionGracePeriod), 10)
+		createOrphan("other-owner", "orphan-b", now.Add(-2*DefaultGarbageCollectionGracePeriod), 10)
+
+		result, err := newGarbageCollector(GarbageCollectorConfig{Owners: []string{"other-owner"}}).Collect(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Chunks).To(Equal(1))
+		Expect(chunkNames()).To(ConsistOf("orphan-a"))
+	})
+
+	It("should not delete anything in dry-run mode", func() {
+		createOrphan("test-owner", "orphan", now.Add(-2*DefaultGarbageCollectionGracePeriod), 10)
+
+		result, err := newGarbageCollector(GarbageCollectorConfig{DryRun: true}).Collect(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(GarbageCollectionResult{Chunks: 1, Bytes: 10}))
+		Expect(chunkNames()).To(ConsistOf("orphan"))
+	})
+
+	It("should ignore objects of other tools that are labeled as chunks", func() {
+		old := strconv.Itoa(int(now.Add(-2 * DefaultGarbageCollectionGracePeriod).Unix()))
+		for _, obj := range []*StoredObject{
+			{Name: "foreign-unowned", Type: ObjectTypeChunk, Labels: map[string]string{"type": "chunk", "createdAt": old}, Data: map[string][]byte{"chunk": []byte("x")}},
+			{Name: "foreign-owned", Labels: map[string]string{"type": "chunk", "owner": "test-owner", "createdAt": old}, Data: map[string][]byte{"config": []byte("x")}},
+		} {
+			_, err := store.Create(context.Background(), obj)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		createOrphan("test-owner", "orphan", now.Add(-2*DefaultGarbageCollectionGracePeriod), 10)
+
+		result, err := newGarbageCollector(GarbageCollectorConfig{}).Collect(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Chunks).To(Equal(1))
+		Expect(chunkNames()).To(ConsistOf("foreign-unowned", "foreign-owned"))
+	})
+
+	It("should skip objects of other tools that are labeled as indices", func() {
+		_, err := store.Create(context.Background(), &StoredObject{
+			Name:   "foreign-index",
+			Labels: map[string]string{"type": "index", "owner": "test-owner"},
+			Data:   map[string][]byte{"extraChunks": []byte("not json")},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		createOrphan("test-owner", "orphan", now.Add(-2*DefaultGarbageCollectionGracePeriod), 10)
+
+		result, err := newGarbageCollector(GarbageCollectorConfig{}).Collect(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Chunks).To(Equal(1))
+		Expect(chunkNames()).To(BeEmpty())
+	})
+
+	It("should only skip the owner of a corrupt index", func() {
+		rel := genRelease("test-release", 1, release.StatusDeployed, nil, chunkSize*2)
+		Expect(chunkedDriver.Create(releaseKey(rel), rel)).To(Succeed())
+		referencedChunks := chunkNames()
+
+		index, err := store.Get(context.Background(), releaseKey(rel))
+		Expect(err).ToNot(HaveOccurred())
+		index.Data["extraChunks"] = []byte("not json")
+		_, err = store.Update(context.Background(), index)
+		Expect(err).ToNot(HaveOccurred())
+
+		createOrphan("test-owner", "orphan-a", now.Add(-2*DefaultGarbageCollectionGracePeriod), 10)
+		createOrphan("other-owner", "orphan-b", now.Add(-2*DefaultGarbageCollectionGracePeriod), 10)
+
+		result, err := newGarbageCollector(GarbageCollectorConfig{}).Collect(context.Background())
+		Expect(err).To(MatchError(ContainSubstring(releaseKey(rel))))
+		Expect(result.Chunks).To(Equal(1))
+		Expect(chunkNames()).To(ConsistOf(append(referencedChunks, "orphan-a")))
+	})
+})
@@ -2,10 +2,27 @@ package storage
 
 import (
 	"strconv"
+	"strings"
 
 	"helm.sh/helm/v3/pkg/release"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// customLabelPrefix is prepended to the keys of custom release labels that
+	// are projected onto the index secret. Custom labels are otherwise only
+	// stored inside the encoded release, which means they cannot be used in
+	// server-side label selectors.
+	customLabelPrefix = "custom.chunked.operatorframework.io/"
+
+	// customLabelsProjectedLabel marks index secrets that carry projected custom
+	// labels. Index secrets written by older versions of this driver lack this
+	// label, and their custom labels can only be filtered client-side.
+	customLabelsProjectedLabel = "chunked.operatorframework.io/custom-labels"
+	customLabelsProjectedValue = "projected"
 )
 
 func newIndexLabels(owner, key string, rls *release.Release) map[string]string {
@@ -16,6 +33,12 @@ func newIndexLabels(owner, key string, rls *release.Release) map[string]string {
 	labels["version"] = strconv.Itoa(rls.Version)
 	labels["key"] = key
 	labels["type"] = "index"
+	labels[customLabelsProjectedLabel] = customLabelsProjectedValue
+	for k, v := range rls.Labels {
+		if isProjectableLabel(k, v) {
+			labels[customLabelPrefix+k] = v
+		}
+	}
 	return labels
 }
 
@@ -31,6 +54,26 @@ func newListIndicesLabelSelector(owner string) labels.Selector {
 	return labels.Set{"owner": owner, "type": "index"}.AsSelector()
 }
 
+// newListProjectedIndicesLabelSelector returns a selector for index secrets
+// that carry projected custom labels.
+func newListProjectedIndicesLabelSelector(owner string) labels.Selector {
+	return newListIndicesLabelSelector(owner).Add(mustNewRequirement(customLabelsProjectedLabel, selection.Equals, []string{customLabelsProjectedValue}))
+}
+
+// newListLegacyIndicesLabelSelector returns a selector for index secrets that
+// were written without projected custom labels.
+func newListLegacyIndicesLabelSelector(owner string) labels.Selector {
+	return newListIndicesLabelSelector(owner).Add(mustNewRequirement(customLabelsProjectedLabel, selection.DoesNotExist, nil))
+}
+
+func mustNewRequirement(key string, op selection.Operator, vals []string) labels.Requirement {
+	req, err := labels.NewRequirement(key, op, vals)
+	if err != nil {
+		panic(err)
+	}
+	return *req
+}
+
 func newListAllForKeySelector(owner, key string) labels.Selector {
 	return labels.Set{"owner": owner, "key": key}.AsSelector()
 }
@@ -46,6 +89,33 @@ func isSystemLabel(key string) bool {
 	return systemLabels.Has(key)
 }
 
+// Checks if a custom release label can be projected onto the index secret.
+// Only labels whose key is a valid unprefixed label name and whose value is a
+// valid label value can be projected.
+func isProjectableLabel(key, value string) bool {
+	if isSystemLabel(key) || strings.Contains(key, "/") {
+		return false
+	}
+	return len(validation.IsQualifiedName(customLabelPrefix+key)) == 0 && len(validation.IsValidLabelValue(value)) == 0
+}
+
+// Checks if label only exists on the index secret to support server-side
+// filtering of custom labels
+func isProjectionLabel(key string) bool {
+	return key == customLabelsProjectedLabel || strings.HasPrefix(key, customLabelPrefix)
+}
+
+// Removes projected custom labels and the projection marker from labels map
+func filterProjectionLabels(lbs map[string]string) map[string]string {
+	result := make(map[string]string, len(lbs))
+	for k, v := range lbs {
+		if !isProjectionLabel(k) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
 // Removes system labels from labels map
 func filterSystemLabels(lbs map[string]string) map[string]string {
 	result := make(map[string]string)
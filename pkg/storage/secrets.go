@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	SecretTypeChunkedIndex = corev1.SecretType("operatorframework.io/index.v1")
+	SecretTypeChunkedChunk = corev1.SecretType("operatorframework.io/chunk.v1")
+)
+
+var _ ObjectStore = (*secretsStore)(nil)
+
+// NewSecretsStore returns an ObjectStore that persists objects as Secrets.
+func NewSecretsStore(client clientcorev1.SecretInterface) ObjectStore {
+	return &secretsStore{client: client}
+}
+
+type secretsStore struct {
+	client clientcorev1.SecretInterface
+}
+
+func (s *secretsStore) Name() string {
+	return "Secrets"
+}
+
+func (s *secretsStore) Get(ctx context.Context, name string) (*StoredObject, error) {
+	secret, err := s.client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return storedObjectFromSecret(secret), nil
+}
+
+func (s *secretsStore) List(ctx context.Context, selector labels.Selector) ([]StoredObject, error) {
+	secrets, err := s.client.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]StoredObject, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		objs = append(objs, *storedObjectFromSecret(&secrets.Items[i]))
+	}
+	return objs, nil
+}
+
+func (s *secretsStore) Create(ctx context.Context, obj *StoredObject) (*StoredObject, error) {
+	secret, err := s.client.Create(ctx, secretFromStoredObject(obj), metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return storedObjectFromSecret(secret), nil
+}
+
+func (s *secretsStore) Update(ctx context.Context, obj *StoredObject) (*StoredObject, error) {
+	secret, err := s.client.Update(ctx, secretFromStoredObject(obj), metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return storedObjectFromSecret(secret), nil
+}
+
+func (s *secretsStore) Delete(ctx context.Context, name string) error {
+	return s.client.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (s *secretsStore) DeleteCollection(ctx context.Context, selector labels.Selector) error {
+	return s.client.DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector.String()})
+}
+
+func secretFromStoredObject(obj *StoredObject) *corev1.Secret {
+	secretType := SecretTypeChunkedIndex
+	if obj.Type == ObjectTypeChunk {
+		secretType = SecretTypeChunkedChunk
+	}
+	return &corev1.Secret{
+		Type: secretType,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            obj.Name,
+			Labels:          obj.Labels,
+			OwnerReferences: ownerReferencesFor(obj.Owner, "Secret"),
+		},
+		Immutable: ptr.To(obj.Immutable),
+		Data:      obj.Data,
+	}
+}
+
+func storedObjectFromSecret(secret *corev1.Secret) *StoredObject {
+	// Secrets of other types were not written by the chunked driver and
+	// have no object type.
+	var objType ObjectType
+	switch secret.Type {
+	case SecretTypeChunkedIndex:
+		objType = ObjectTypeIndex
+	case SecretTypeChunkedChunk:
+		objType = ObjectTypeChunk
+	}
+	return &StoredObject{
+		Name:      secret.Name,
+		UID:       secret.UID,
+		Type:      objType,
+		Labels:    secret.Labels,
+		Immutable: ptr.Deref(secret.Immutable, false),
+		Data:      secret.Data,
+		Owner:     ownerFromReferences(secret.OwnerReferences, "Secret"),
+
+		CreationTimestamp: secret.CreationTimestamp.Time,
+	}
+}
+
+func ownerReferencesFor(owner *ObjectReference, kind string) []metav1.OwnerReference {
+	if owner == nil {
+		return nil
+	}
+	return []metav1.OwnerReference{
+		{
+			APIVersion:         corev1.SchemeGroupVersion.String(),
+			Kind:               kind,
+			Name:               owner.Name,
+			UID:                owner.UID,
+			Controller:         ptr.To(true),
+			BlockOwnerDeletion: ptr.To(false),
+		},
+	}
+}
+
+func ownerFromReferences(refs []metav1.OwnerReference, kind string) *ObjectReference {
+	for _, ref := range refs {
+		if ref.APIVersion == corev1.SchemeGroupVersion.String() && ref.Kind == kind && ptr.Deref(ref.Controller, false) {
+			return &ObjectReference{Name: ref.Name, UID: ref.UID}
+		}
+	}
+	return nil
+}
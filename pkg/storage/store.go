@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ObjectStore is the minimal set of operations the chunked storage driver
+// needs from the backend that persists index and chunk objects.
+//
+// Implementations must return errors for which apierrors.IsNotFound and
+// apierrors.IsAlreadyExists report true when an object does not exist or
+// already exists, respectively.
+type ObjectStore interface {
+	// Name returns the name of the kind of objects this store persists,
+	// e.g. "Secrets". It is used to build the name of the storage driver.
+	Name() string
+
+	Get(ctx context.Context, name string) (*StoredObject, error)
+	List(ctx context.Context, selector labels.Selector) ([]StoredObject, error)
+	Create(ctx context.Context, obj *StoredObject) (*StoredObject, error)
+	Update(ctx context.Context, obj *StoredObject) (*StoredObject, error)
+	Delete(ctx context.Context, name string) error
+	DeleteCollection(ctx context.Context, selector labels.Selector) error
+}
+
+// ObjectType distinguishes index objects from chunk objects.
+type ObjectType string
+
+const (
+	ObjectTypeIndex ObjectType = "index"
+	ObjectTypeChunk ObjectType = "chunk"
+)
+
+// StoredObject is the backend-agnostic representation of an index or chunk
+// object.
+type StoredObject struct {
+	Name      string
+	UID       types.UID
+	Type      ObjectType
+	Labels    map[string]string
+	Immutable bool
+	Data      map[string][]byte
+
+	// CreationTimestamp is set by the store when the object is created.
+	CreationTimestamp time.Time
+
+	// Owner references the index object a chunk object belongs to. Backends
+	// that support garbage collection use it to delete chunks together with
+	// their index.
+	Owner *ObjectReference
+}
+
+// ObjectReference identifies a stored object.
+type ObjectReference struct {
+	Name string    `json:"name"`
+	UID  types.UID `json:"uid"`
+}
@@ -0,0 +1,394 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watches
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/maintenance"
+)
+
+// JSONSchema is the JSON Schema of the watches file.
+//
+//go:embed watches.schema.json
+var JSONSchema []byte
+
+// Problem is a problem found in a watches file.
+type Problem struct {
+	// Line is the line of the watches file the problem was found at, or 0
+	// if the problem is not related to a particular line.
+	Line int
+	// Field is the path of the field the problem was found in, e.g.
+	// "[0].reconcilePeriod".
+	Field   string
+	Message string
+}
+
+func (p Problem) Error() string {
+	var sb strings.Builder
+	if p.Line > 0 {
+		fmt.Fprintf(&sb, "line %d: ", p.Line)
+	}
+	if p.Field != "" {
+		fmt.Fprintf(&sb, "%s: ", p.Field)
+	}
+	sb.WriteString(p.Message)
+	return sb.String()
+}
+
+// Problems is a list of problems found in a watches file.
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	if len(ps) == 1 {
+		return ps[0].Error()
+	}
+	msgs := make([]string, 0, len(ps))
+	for _, p := range ps {
+		msgs = append(msgs, p.Error())
+	}
+	return fmt.Sprintf("%d problems found in watches file:\n%s", len(ps), strings.Join(msgs, "\n"))
+}
+
+// ValidateOptions configures the checks performed by Validate.
+type ValidateOptions struct {
+	// CRDDir is a directory containing the manifests of the
+	// CustomResourceDefinitions of the watched kinds. If set, each watched
+	// group, version and kind must be defined by one of them.
+	CRDDir string
+}
+
+// Validate validates the watches file read from reader and returns all
+// problems found in it. Besides the checks that LoadReader performs, it
+// checks the watches against the CustomResourceDefinitions in
+// opts.CRDDir, if set.
+func Validate(reader io.Reader, opts ValidateOptions) (Problems, error) {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	watches, nodes, problems := load(b)
+	if opts.CRDDir == "" || len(watches) == 0 {
+		return problems, nil
+	}
+
+	crdGVKs, err := loadCRDGVKs(opts.CRDDir)
+	if err != nil {
+		return nil, err
+	}
+	for i, w := range watches {
+		if w.Kind == "" || w.Version == "" {
+			continue
+		}
+		if !crdGVKs[w.GroupVersionKind] {
+			problems = append(problems, Problem{
+				Line:    nodes[i].Line,
+				Field:   fmt.Sprintf("[%d]", i),
+				Message: fmt.Sprintf("no CustomResourceDefinition in %s defines %s", opts.CRDDir, w.GroupVersionKind),
+			})
+		}
+	}
+	sortProblems(problems)
+	return problems, nil
+}
+
+// watchFields maps the fields of a watch in the watches file to the fields
+// of Watch they are decoded into.
+var watchFields = map[string]func(*Watch) interface{}{
+	"group":                   func(w *Watch) interface{} { return &w.Group },
+	"version":                 func(w *Watch) interface{} { return &w.Version },
+	"kind":                    func(w *Watch) interface{} { return &w.Kind },
+	"chart":                   func(w *Watch) interface{} { return &w.ChartPath },
+	"watchDependentResources": func(w *Watch) interface{} { return &w.WatchDependentResources },
+	"overrideValues":          func(w *Watch) interface{} { return &w.OverrideValues },
+	"reconcilePeriod":         func(w *Watch) interface{} { return &w.ReconcilePeriod },
+	"maxConcurrentReconciles": func(w *Watch) interface{} { return &w.MaxConcurrentReconciles },
+	"selector":                func(w *Watch) interface{} { return &w.Selector },
+	"validatingWebhook":       func(w *Watch) interface{} { return &w.ValidatingWebhook },
+	"defaultingWebhook":       func(w *Watch) interface{} { return &w.DefaultingWebhook },
+	"remoteClusters":          func(w *Watch) interface{} { return &w.RemoteClusters },
+	"impersonation":           func(w *Watch) interface{} { return &w.Impersonation },
+	"maintenanceWindows":      func(w *Watch) interface{} { return &w.MaintenanceWindows },
+	"statusMappings":          func(w *Watch) interface{} { return &w.StatusMappings },
+	"dependentWatches":        func(w *Watch) interface{} { return &w.DependentWatches },
+	"dryRunFullCheckPeriod":   func(w *Watch) interface{} { return &w.DryRunFullCheckPeriod },
+}
+
+// load decodes and verifies the watches in b. It returns the watches along
+// with the YAML nodes they were decoded from, and all problems found. The
+// watches are only complete if no problems were found.
+func load(b []byte) ([]Watch, []*yamlv3.Node, Problems) {
+	watches, nodes, problems := decodeStrict(b)
+
+	watchesMap := make(map[schema.GroupVersionKind]int)
+	for i := range watches {
+		w := &watches[i]
+		field := fmt.Sprintf("[%d]", i)
+		line := nodes[i].Line
+
+		gvk := w.GroupVersionKind
+		if err := verifyGVK(gvk); err != nil {
+			problems = append(problems, Problem{Line: line, Field: field, Message: fmt.Sprintf("invalid GVK: %s: %v", gvk, err)})
+		} else if j, ok := watchesMap[gvk]; ok {
+			problems = append(problems, Problem{Line: line, Field: field, Message: fmt.Sprintf("duplicate GVK: %s, already watched by [%d]", gvk, j)})
+		} else {
+			watchesMap[gvk] = i
+		}
+
+		if w.ChartPath == "" {
+			problems = append(problems, Problem{Line: line, Field: field + ".chart", Message: "chart must not be empty"})
+		} else if cl, err := loader.Load(w.ChartPath); err != nil {
+			problems = append(problems, Problem{Line: fieldLine(nodes[i], "chart"), Field: field + ".chart", Message: fmt.Sprintf("invalid chart %s: %v", w.ChartPath, err)})
+		} else {
+			w.Chart = cl
+		}
+
+		if w.WatchDependentResources == nil {
+			trueVal := true
+			w.WatchDependentResources = &trueVal
+		}
+
+		if w.Selector == nil {
+			w.Selector = &metav1.LabelSelector{}
+		} else if _, err := metav1.LabelSelectorAsSelector(w.Selector); err != nil {
+			problems = append(problems, Problem{Line: fieldLine(nodes[i], "selector"), Field: field + ".selector", Message: err.Error()})
+		}
+
+		if w.DefaultingWebhook != nil {
+			for j, p := range w.DefaultingWebhook.AllowedPaths {
+				if p == "" || strings.HasPrefix(p, ".") || strings.HasSuffix(p, ".") || strings.Contains(p, "..") {
+					problems = append(problems, Problem{Line: fieldLine(nodes[i], "defaultingWebhook"), Field: fmt.Sprintf("%s.defaultingWebhook.allowedPaths[%d]", field, j), Message: fmt.Sprintf("invalid path %q", p)})
+				}
+			}
+		}
+
+		if w.Impersonation != nil {
+			for j, pattern := range w.Impersonation.AllowedServiceAccounts {
+				if _, err := path.Match(pattern, ""); err != nil {
+					problems = append(problems, Problem{Line: fieldLine(nodes[i], "impersonation"), Field: fmt.Sprintf("%s.impersonation.allowedServiceAccounts[%d]", field, j), Message: fmt.Sprintf("invalid pattern %q", pattern)})
+				}
+			}
+		}
+
+		for j, mw := range w.MaintenanceWindows {
+			if _, err := maintenance.Parse(mw); err != nil {
+				problems = append(problems, Problem{Line: fieldLine(nodes[i], "maintenanceWindows"), Field: fmt.Sprintf("%s.maintenanceWindows[%d]", field, j), Message: err.Error()})
+			}
+		}
+
+		for j, m := range w.StatusMappings {
+			if err := m.Validate(); err != nil {
+				problems = append(problems, Problem{Line: fieldLine(nodes[i], "statusMappings"), Field: fmt.Sprintf("%s.statusMappings[%d]", field, j), Message: err.Error()})
+			}
+		}
+
+		if w.DependentWatches != nil {
+			if _, _, err := w.DependentWatches.ParseLabel(); err != nil {
+				problems = append(problems, Problem{Line: fieldLine(fieldNode(nodes[i], "dependentWatches"), "label"), Field: field + ".dependentWatches.label", Message: err.Error()})
+			}
+			if err := w.DependentWatches.Config.Validate(); err != nil {
+				problems = append(problems, Problem{Line: fieldLine(nodes[i], "dependentWatches"), Field: field + ".dependentWatches", Message: err.Error()})
+			}
+		}
+
+		if w.DryRunFullCheckPeriod != nil && w.DryRunFullCheckPeriod.Duration <= 0 {
+			problems = append(problems, Problem{Line: fieldLine(nodes[i], "dryRunFullCheckPeriod"), Field: field + ".dryRunFullCheckPeriod", Message: "dryRunFullCheckPeriod must be positive"})
+		}
+
+		if w.OverrideValues != nil {
+			overridesNode := fieldNode(nodes[i], "overrideValues")
+			expanded := make(map[string]string, len(w.OverrideValues))
+			for k, v := range w.OverrideValues {
+				out, err := expandOverrideValues(map[string]string{k: v})
+				if err != nil {
+					problems = append(problems, Problem{Line: fieldLine(overridesNode, k), Field: field + ".overrideValues." + k, Message: err.Error()})
+					continue
+				}
+				expanded[k] = out[k]
+			}
+			w.OverrideValues = expanded
+		}
+	}
+	sortProblems(problems)
+	return watches, nodes, problems
+}
+
+// decodeStrict decodes the watches in b. Unlike yaml.Unmarshal, it reports
+// unknown and duplicate fields, and it continues after a field could not be
+// decoded, so that all such problems are reported at once.
+func decodeStrict(b []byte) ([]Watch, []*yamlv3.Node, Problems) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(b, &doc); err != nil {
+		return nil, nil, Problems{yamlProblem(err)}
+	}
+	if len(doc.Content) == 0 {
+		return []Watch{}, nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind == yamlv3.ScalarNode && root.Tag == "!!null" {
+		return []Watch{}, nil, nil
+	}
+	if root.Kind != yamlv3.SequenceNode {
+		return nil, nil, Problems{{Line: root.Line, Message: "watches file must contain a list of watches"}}
+	}
+
+	var problems Problems
+	watches := make([]Watch, len(root.Content))
+	for i, item := range root.Content {
+		field := fmt.Sprintf("[%d]", i)
+		if item.Kind != yamlv3.MappingNode {
+			problems = append(problems, Problem{Line: item.Line, Field: field, Message: "watch must be a mapping"})
+			continue
+		}
+		seen := make(map[string]bool, len(item.Content)/2)
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			keyNode, valueNode := item.Content[j], item.Content[j+1]
+			key := keyNode.Value
+			fieldPath := field + "." + key
+			target, ok := watchFields[key]
+			if !ok {
+				problems = append(problems, Problem{Line: keyNode.Line, Field: fieldPath, Message: "unknown field"})
+				continue
+			}
+			if seen[key] {
+				problems = append(problems, Problem{Line: keyNode.Line, Field: fieldPath, Message: "duplicate field"})
+				continue
+			}
+			seen[key] = true
+
+			// Each field is decoded by itself with the same decoder
+			// LoadReader used to use for the whole file, so that values are
+			// converted the same way as before.
+			value, err := yamlv3.Marshal(valueNode)
+			if err == nil {
+				err = yaml.UnmarshalStrict(value, target(&watches[i]))
+			}
+			if err != nil {
+				problems = append(problems, Problem{Line: valueNode.Line, Field: fieldPath, Message: strings.TrimPrefix(err.Error(), "error unmarshaling JSON: while decoding JSON: ")})
+			}
+		}
+	}
+	return watches, root.Content, problems
+}
+
+// fieldNode returns the value node of key in the mapping node, or nil.
+func fieldNode(mapping *yamlv3.Node, key string) *yamlv3.Node {
+	if mapping == nil || mapping.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for j := 0; j+1 < len(mapping.Content); j += 2 {
+		if mapping.Content[j].Value == key {
+			return mapping.Content[j+1]
+		}
+	}
+	return nil
+}
+
+// fieldLine returns the line of the value of key in the mapping node,
+// falling back to the line of the mapping node itself.
+func fieldLine(mapping *yamlv3.Node, key string) int {
+	if n := fieldNode(mapping, key); n != nil {
+		return n.Line
+	}
+	if mapping != nil {
+		return mapping.Line
+	}
+	return 0
+}
+
+// yamlProblem converts a YAML syntax error, which carries the line in its
+// message, into a problem.
+func yamlProblem(err error) Problem {
+	var typeErr *yamlv3.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		err = errors.New(typeErr.Errors[0])
+	}
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	var line int
+	if n, _ := fmt.Sscanf(msg, "line %d: ", &line); n == 1 {
+		msg = strings.TrimPrefix(msg, fmt.Sprintf("line %d: ", line))
+	}
+	return Problem{Line: line, Message: msg}
+}
+
+func sortProblems(problems Problems) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+}
+
+// loadCRDGVKs returns the served groups, versions and kinds of all
+// CustomResourceDefinitions found in the YAML and JSON files in dir and its
+// subdirectories.
+func loadCRDGVKs(dir string) (map[schema.GroupVersionKind]bool, error) {
+	gvks := map[schema.GroupVersionKind]bool{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+		for {
+			var crd apiextensionsv1.CustomResourceDefinition
+			if err := decoder.Decode(&crd); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("parse %s: %w", path, err)
+			}
+			if crd.Kind != "CustomResourceDefinition" {
+				continue
+			}
+			for _, v := range crd.Spec.Versions {
+				if v.Served {
+					gvks[schema.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind}] = true
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load CustomResourceDefinitions from %s: %w", dir, err)
+	}
+	return gvks, nil
+}
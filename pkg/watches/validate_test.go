@@ -0,0 +1,347 @@
+// Copyright 2026 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watches
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
+	"github.com/operator-framework/helm-operator-plugins/pkg/statusmapping"
+)
+
+var _ = Describe("Validate", func() {
+	It("should report unknown fields with their line", func() {
+		problems, err := Validate(strings.NewReader(`---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  reconcilePeriode: 1m
+`), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal(Problems{
+			{Line: 6, Field: "[0].reconcilePeriode", Message: "unknown field"},
+		}))
+	})
+
+	It("should report all problems", func() {
+		problems, err := Validate(strings.NewReader(`---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  maxConcurrentReconciles: many
+  overrideValues:
+    image: '{{ "foo" | nosuchfunc }}'
+- group: mygroup
+  kind: MyOtherKind
+  chart: nonexistent/path/to/chart
+  watchDependentResource: false
+`), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(5))
+
+		lines := make([]int, 0, len(problems))
+		fields := make([]string, 0, len(problems))
+		for _, p := range problems {
+			lines = append(lines, p.Line)
+			fields = append(fields, p.Field)
+		}
+		Expect(lines).To(Equal([]int{6, 8, 9, 11, 12}))
+		Expect(fields).To(Equal([]string{
+			"[0].maxConcurrentReconciles",
+			"[0].overrideValues.image",
+			"[1]",
+			"[1].chart",
+			"[1].watchDependentResource",
+		}))
+		Expect(problems[2].Message).To(ContainSubstring("version must not be empty"))
+	})
+
+	It("should decode webhook configuration and report invalid defaulting paths", func() {
+		data := `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  validatingWebhook: true
+  defaultingWebhook:
+    allowedPaths: [image.tag, replicaCount]
+`
+		watches, err := LoadReader(strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watches[0].ValidatingWebhook).To(BeTrue())
+		Expect(watches[0].DefaultingWebhook).To(Equal(&DefaultingWebhook{AllowedPaths: []string{"image.tag", "replicaCount"}}))
+
+		problems, err := Validate(strings.NewReader(strings.Replace(data, "image.tag", "image..tag", 1)), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal(Problems{
+			{Line: 8, Field: "[0].defaultingWebhook.allowedPaths[0]", Message: `invalid path "image..tag"`},
+		}))
+	})
+
+	It("should decode impersonation and report invalid service account patterns", func() {
+		data := `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  impersonation:
+    defaultServiceAccount: helm
+    allowedServiceAccounts: [helm, 'deployer-*']
+`
+		watches, err := LoadReader(strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watches[0].Impersonation).To(Equal(&Impersonation{DefaultServiceAccount: "helm", AllowedServiceAccounts: []string{"helm", "deployer-*"}}))
+
+		problems, err := Validate(strings.NewReader(strings.Replace(data, "deployer-*", "deployer-[", 1)), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal(Problems{
+			{Line: 7, Field: "[0].impersonation.allowedServiceAccounts[1]", Message: `invalid pattern "deployer-["`},
+		}))
+	})
+
+	It("should decode maintenance windows and report invalid ones", func() {
+		data := `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  maintenanceWindows: ['0 2 * * SAT 4h', '0 2 * * SUN 2h']
+`
+		watches, err := LoadReader(strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watches[0].MaintenanceWindows).To(Equal([]string{"0 2 * * SAT 4h", "0 2 * * SUN 2h"}))
+
+		problems, err := Validate(strings.NewReader(strings.Replace(data, "SUN 2h", "SUN", 1)), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Line).To(Equal(6))
+		Expect(problems[0].Field).To(Equal("[0].maintenanceWindows[1]"))
+		Expect(problems[0].Message).To(ContainSubstring(`invalid maintenance window "0 2 * * SUN"`))
+	})
+
+	It("should decode status mappings and report invalid ones", func() {
+		data := `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  statusMappings:
+  - apiVersion: v1
+    kind: Service
+    jsonPath: '{.status.loadBalancer.ingress[0].ip}'
+    field: endpoints.web
+`
+		watches, err := LoadReader(strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watches[0].StatusMappings).To(Equal([]statusmapping.Mapping{{APIVersion: "v1", Kind: "Service", JSONPath: "{.status.loadBalancer.ingress[0].ip}", Field: "endpoints.web"}}))
+
+		problems, err := Validate(strings.NewReader(strings.Replace(data, "jsonPath", "cel", 1)), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Line).To(Equal(7))
+		Expect(problems[0].Field).To(Equal("[0].statusMappings[0]"))
+		Expect(problems[0].Message).To(ContainSubstring("invalid cel"))
+	})
+
+	It("should decode dependent watches and report invalid labels", func() {
+		data := `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  dependentWatches:
+    metadataOnly: true
+    label: example.com/managed-by=my-operator
+`
+		watches, err := LoadReader(strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watches[0].DependentWatches).To(Equal(&DependentWatches{MetadataOnly: true, Label: "example.com/managed-by=my-operator"}))
+		key, value, err := watches[0].DependentWatches.ParseLabel()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(Equal("example.com/managed-by"))
+		Expect(value).To(Equal("my-operator"))
+
+		problems, err := Validate(strings.NewReader(strings.Replace(data, "=my-operator", "", 1)), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Line).To(Equal(8))
+		Expect(problems[0].Field).To(Equal("[0].dependentWatches.label"))
+		Expect(problems[0].Message).To(ContainSubstring("must be of the form key=value"))
+	})
+
+	It("should decode dependent event filters and report invalid ignored fields", func() {
+		data := `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  dependentWatches:
+    ignoreFields:
+    - kind: Deployment.apps
+      path: spec.replicas
+    - path: metadata.annotations[deployment.kubernetes.io/revision]
+    reconcileOnCreate: true
+    updateDebounce: 10s
+`
+		watches, err := LoadReader(strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watches[0].DependentWatches).To(Equal(&DependentWatches{Config: eventfilter.Config{
+			IgnoreFields: []eventfilter.IgnoreField{
+				{Kind: "Deployment.apps", Path: "spec.replicas"},
+				{Path: "metadata.annotations[deployment.kubernetes.io/revision]"},
+			},
+			ReconcileOnCreate: true,
+			UpdateDebounce:    metav1.Duration{Duration: 10 * time.Second},
+		}}))
+
+		problems, err := Validate(strings.NewReader(strings.Replace(data, "[deployment.kubernetes.io/revision]", "[deployment.kubernetes.io", 1)), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Line).To(Equal(7))
+		Expect(problems[0].Field).To(Equal("[0].dependentWatches"))
+		Expect(problems[0].Message).To(ContainSubstring("unterminated bracket"))
+	})
+
+	It("should decode dry-run full check periods and report invalid ones", func() {
+		data := `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+  dryRunFullCheckPeriod: 1h
+`
+		watches, err := LoadReader(strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watches[0].DryRunFullCheckPeriod).To(Equal(&metav1.Duration{Duration: time.Hour}))
+
+		problems, err := Validate(strings.NewReader(strings.Replace(data, "1h", "0s", 1)), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal(Problems{
+			{Line: 6, Field: "[0].dryRunFullCheckPeriod", Message: "dryRunFullCheckPeriod must be positive"},
+		}))
+	})
+
+	It("should report a watches file that is not a list", func() {
+		problems, err := Validate(strings.NewReader("foo: bar\n"), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal(Problems{
+			{Line: 1, Message: "watches file must contain a list of watches"},
+		}))
+	})
+
+	It("should report syntax errors with their line", func() {
+		problems, err := Validate(strings.NewReader("- group: mygroup\n  version: [v1\n"), ValidateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Line).To(BeNumerically(">", 0))
+	})
+
+	When("a CRD directory is given", func() {
+		var crdDir string
+
+		BeforeEach(func() {
+			crdDir = GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(crdDir, "crds.yaml"), []byte(`---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: mykinds.mygroup
+spec:
+  group: mygroup
+  names:
+    kind: MyKind
+    plural: mykinds
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+  - name: v1alpha2
+    served: false
+    storage: false
+`), 0o600)).To(Succeed())
+		})
+
+		It("should report watches without a CRD", func() {
+			problems, err := Validate(strings.NewReader(`---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+- group: mygroup
+  version: v1alpha2
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+- group: othergroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../pkg/internal/testdata/test-chart
+`), ValidateOptions{CRDDir: crdDir})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(HaveLen(2))
+			Expect(problems[0].Line).To(Equal(6))
+			Expect(problems[0].Message).To(ContainSubstring("mygroup/v1alpha2, Kind=MyKind"))
+			Expect(problems[1].Line).To(Equal(10))
+			Expect(problems[1].Message).To(ContainSubstring("othergroup/v1alpha1, Kind=MyKind"))
+		})
+	})
+})
+
+var _ = Describe("JSONSchema", func() {
+	It("should describe all fields of a watch", func() {
+		var s struct {
+			Items struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"items"`
+		}
+		Expect(json.Unmarshal(JSONSchema, &s)).To(Succeed())
+
+		schemaFields := make([]string, 0, len(s.Items.Properties))
+		for name := range s.Items.Properties {
+			schemaFields = append(schemaFields, name)
+		}
+		decodedFields := make([]string, 0, len(watchFields))
+		for name := range watchFields {
+			decodedFields = append(decodedFields, name)
+		}
+		Expect(schemaFields).To(ConsistOf(decodedFields))
+
+		t := reflect.TypeOf(Watch{})
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			switch {
+			case name == "-":
+			case f.Anonymous:
+				for j := 0; j < f.Type.NumField(); j++ {
+					Expect(s.Items.Properties).To(HaveKey(strings.ToLower(f.Type.Field(j).Name)))
+				}
+			default:
+				Expect(s.Items.Properties).To(HaveKey(name), "field %s", f.Name)
+			}
+		}
+	})
+})
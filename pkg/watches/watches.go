@@ -20,26 +20,93 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"text/template"
 
 	sprig "github.com/go-task/slim-sprig/v3"
 	"helm.sh/helm/v3/pkg/chart"
-	"helm.sh/helm/v3/pkg/chart/loader"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"sigs.k8s.io/yaml"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/operator-framework/helm-operator-plugins/pkg/eventfilter"
+	"github.com/operator-framework/helm-operator-plugins/pkg/statusmapping"
 )
 
 type Watch struct {
 	schema.GroupVersionKind `json:",inline"`
 	ChartPath               string `json:"chart"`
 
-	WatchDependentResources *bool                 `json:"watchDependentResources,omitempty"`
-	OverrideValues          map[string]string     `json:"overrideValues,omitempty"`
-	ReconcilePeriod         *metav1.Duration      `json:"reconcilePeriod,omitempty"`
-	MaxConcurrentReconciles *int                  `json:"maxConcurrentReconciles,omitempty"`
-	Selector                *metav1.LabelSelector `json:"selector,omitempty"`
-	Chart                   *chart.Chart          `json:"-"`
+	WatchDependentResources *bool                   `json:"watchDependentResources,omitempty"`
+	OverrideValues          map[string]string       `json:"overrideValues,omitempty"`
+	ReconcilePeriod         *metav1.Duration        `json:"reconcilePeriod,omitempty"`
+	MaxConcurrentReconciles *int                    `json:"maxConcurrentReconciles,omitempty"`
+	Selector                *metav1.LabelSelector   `json:"selector,omitempty"`
+	ValidatingWebhook       bool                    `json:"validatingWebhook,omitempty"`
+	DefaultingWebhook       *DefaultingWebhook      `json:"defaultingWebhook,omitempty"`
+	RemoteClusters          bool                    `json:"remoteClusters,omitempty"`
+	Impersonation           *Impersonation          `json:"impersonation,omitempty"`
+	MaintenanceWindows      []string                `json:"maintenanceWindows,omitempty"`
+	StatusMappings          []statusmapping.Mapping `json:"statusMappings,omitempty"`
+	DependentWatches        *DependentWatches       `json:"dependentWatches,omitempty"`
+	DryRunFullCheckPeriod   *metav1.Duration        `json:"dryRunFullCheckPeriod,omitempty"`
+	Chart                   *chart.Chart            `json:"-"`
+}
+
+// DefaultingWebhook configures the defaulting webhook of a watch, which fills
+// unset fields in the spec of custom resources with the chart's defaults.
+type DefaultingWebhook struct {
+	// AllowedPaths are the dot-separated paths of the values whose defaults
+	// are filled, e.g. "image.tag". All defaults are filled if it is empty.
+	AllowedPaths []string `json:"allowedPaths,omitempty"`
+}
+
+// Impersonation configures a watch to run Helm actions impersonating a
+// ServiceAccount in the namespace of the custom resource, which is named in
+// spec.serviceAccountName.
+type Impersonation struct {
+	// DefaultServiceAccount is impersonated for custom resources that do
+	// not name a ServiceAccount. If it is empty, custom resources must name
+	// one.
+	DefaultServiceAccount string `json:"defaultServiceAccount,omitempty"`
+	// AllowedServiceAccounts are the names of the ServiceAccounts that may
+	// be impersonated, which may contain wildcards, e.g. "deployer-*". All
+	// ServiceAccounts may be impersonated if it is empty.
+	AllowedServiceAccounts []string `json:"allowedServiceAccounts,omitempty"`
+}
+
+// DependentWatches configures how a watch watches the dependent resources of
+// its releases.
+type DependentWatches struct {
+	// MetadataOnly watches dependent resources with informers that only
+	// cache their metadata instead of the full objects.
+	MetadataOnly bool `json:"metadataOnly,omitempty"`
+	// Label is a label "key=value" that is set on all objects of the
+	// releases. Dependent resources are watched with a cache that only
+	// contains objects with this label.
+	Label string `json:"label,omitempty"`
+	// Config configures which events of dependent resources trigger a
+	// reconciliation.
+	eventfilter.Config `json:",inline"`
+}
+
+// ParseLabel returns the key and value of the label. It returns empty strings
+// if no label is set.
+func (d DependentWatches) ParseLabel() (string, string, error) {
+	if d.Label == "" {
+		return "", "", nil
+	}
+	key, value, ok := strings.Cut(d.Label, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid label %q: must be of the form key=value", d.Label)
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid label value %q: %s", value, strings.Join(errs, ", "))
+	}
+	return key, value, nil
 }
 
 // Load loads a slice of Watches from the watch file at `path`. For each entry
@@ -61,52 +128,18 @@ func Load(path string) ([]Watch, error) {
 	return w, err
 }
 
+// LoadReader loads a slice of Watches from reader. The watches file is
+// decoded strictly, i.e. unknown fields are rejected. If any problems are
+// found, all of them are returned as Problems.
 func LoadReader(reader io.Reader) ([]Watch, error) {
 	b, err := io.ReadAll(reader)
 	if err != nil {
 		return nil, err
 	}
 
-	watches := []Watch{}
-	err = yaml.Unmarshal(b, &watches)
-	if err != nil {
-		return nil, err
-	}
-
-	watchesMap := make(map[schema.GroupVersionKind]struct{})
-	for i, w := range watches {
-		gvk := w.GroupVersionKind
-
-		if err := verifyGVK(gvk); err != nil {
-			return nil, fmt.Errorf("invalid GVK: %s: %w", gvk, err)
-		}
-
-		cl, err := loader.Load(w.ChartPath)
-		if err != nil {
-			return nil, fmt.Errorf("invalid chart %s: %w", w.ChartPath, err)
-		}
-		w.Chart = cl
-
-		if _, ok := watchesMap[gvk]; ok {
-			return nil, fmt.Errorf("duplicate GVK: %s", gvk)
-		}
-		watchesMap[gvk] = struct{}{}
-
-		if w.WatchDependentResources == nil {
-			trueVal := true
-			w.WatchDependentResources = &trueVal
-		}
-
-		if w.Selector == nil {
-			w.Selector = &metav1.LabelSelector{}
-		}
-
-		w.OverrideValues, err = expandOverrideValues(w.OverrideValues)
-		if err != nil {
-			return nil, fmt.Errorf("failed to expand override values")
-		}
-
-		watches[i] = w
+	watches, _, problems := load(b)
+	if len(problems) > 0 {
+		return nil, problems
 	}
 	return watches, nil
 }